@@ -5,12 +5,25 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/emergency-auth-code-api/models"
 )
 
+// maxResponseBodySize limits how much of a response body is read from the AuthCode API
+const maxResponseBodySize = 1 << 20
+
+// UnexpectedStatusError is returned when the AuthCode API does not accept an item
+type UnexpectedStatusError struct {
+	StatusCode int
+}
+
+func (e *UnexpectedStatusError) Error() string {
+	return fmt.Sprintf("unexpected status returned from authCode API: %v", e.StatusCode)
+}
+
 // Client interacts with the AuthCode API
 type Client struct {
 	AuthCodeAPIURL  string
@@ -56,20 +69,39 @@ func (c *Client) sendRequest(method, authCodeRequestID string, item *models.Auth
 	return resp, err
 }
 
-// SendAuthCodeItem sends an item to the AuthCode API
-func (c *Client) SendAuthCodeItem(item *models.AuthCodeItem, authCodeRequestID string) error {
+// SendAuthCodeItem sends an item to the AuthCode API, returning the acknowledgement decoded from its
+// response. A response body which cannot be decoded is logged, and only its status code is returned, as
+// the item has still been accepted.
+func (c *Client) SendAuthCodeItem(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
 	resp, err := c.sendRequest(http.MethodPost, authCodeRequestID, item)
 	if err != nil {
 		log.Error(fmt.Errorf("error sending request to authCode API: %v", err))
-		return err
-	}
-	err = resp.Body.Close()
-	if err != nil {
-		log.Error(fmt.Errorf("error closing response body from AuthCode API: %v", err))
-		// No need to return err here, as sending request might have been successful
+		return nil, err
 	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error(fmt.Errorf("error closing response body from AuthCode API: %v", err))
+			// No need to return err here, as sending request might have been successful
+		}
+	}()
+
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("unexpected status returned from authCode API: %v", resp.StatusCode)
+		return nil, &UnexpectedStatusError{StatusCode: resp.StatusCode}
 	}
-	return nil
+
+	itemResponse := &models.AuthCodeItemResponse{}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	if err != nil {
+		log.Error(fmt.Errorf("error reading response body from AuthCode API: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+	} else if len(bytes.TrimSpace(body)) > 0 {
+		if err := json.Unmarshal(body, itemResponse); err != nil {
+			log.Error(fmt.Errorf("error decoding response body from AuthCode API: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+			itemResponse = &models.AuthCodeItemResponse{}
+		}
+	}
+
+	itemResponse.StatusCode = resp.StatusCode
+
+	return itemResponse, nil
 }
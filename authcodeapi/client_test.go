@@ -3,6 +3,7 @@ package authcodeapi
 import (
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/companieshouse/emergency-auth-code-api/models"
 	"github.com/jarcoal/httpmock"
@@ -24,7 +25,8 @@ func TestUnitSendAuthCodeItem(t *testing.T) {
 		responder := httpmock.NewStringResponder(http.StatusNotFound, "")
 		httpmock.RegisterResponder(http.MethodPost, queueAPIURL, responder)
 
-		err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
+		resp, err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
+		So(resp, ShouldBeNil)
 		So(err.Error(), ShouldEqual, "unexpected status returned from authCode API: 404")
 	})
 
@@ -35,8 +37,9 @@ func TestUnitSendAuthCodeItem(t *testing.T) {
 		responder := httpmock.NewStringResponder(http.StatusOK, "error")
 		httpmock.RegisterResponder(http.MethodPost, queueAPIURL, responder)
 
-		err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
+		resp, err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
 		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusOK)
 	})
 
 	Convey("queue API - success (CREATED - 201)", t, func() {
@@ -46,7 +49,23 @@ func TestUnitSendAuthCodeItem(t *testing.T) {
 		responder := httpmock.NewStringResponder(http.StatusCreated, "error")
 		httpmock.RegisterResponder(http.MethodPost, queueAPIURL, responder)
 
-		err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
+		resp, err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
 		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusCreated)
+	})
+
+	Convey("authcode API - acknowledgement decoded", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		client := NewClient(url, path, authKey)
+		responder := httpmock.NewStringResponder(http.StatusCreated, `{"letter_reference":"LTR123","created_at":"2020-05-12T10:00:00Z"}`)
+		httpmock.RegisterResponder(http.MethodPost, queueAPIURL, responder)
+
+		resp, err := client.SendAuthCodeItem(&AuthCodeItem, testRequestID)
+		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusCreated)
+		So(resp.LetterReference, ShouldEqual, "LTR123")
+		So(resp.QueueItemID, ShouldBeEmpty)
+		So(*resp.CreatedAt, ShouldEqual, time.Date(2020, 5, 12, 10, 0, 0, 0, time.UTC))
 	})
 }
@@ -13,7 +13,7 @@ var mtx sync.Mutex
 // Config defines the configuration options for this service.
 type Config struct {
 	BindAddr                       string   `env:"BIND_ADDR"                         flag:"bind-addr"                           flagDesc:"Bind address"`
-	MongoDBURL                     string   `env:"MONGODB_URL"                       flag:"mongodb-url"                         flagDesc:"MongoDB server URL"`
+	MongoDBURL                     string   `env:"MONGODB_URL"                       flag:"mongodb-url"                         flagDesc:"MongoDB server URL, which must be a replica set or sharded cluster as transactions are used"`
 	MongoAuthcodeDatabase          string   `env:"MONGO_AUTHCODE_DATABASE"           flag:"mongodb-authcode-database"           flagDesc:"MongoDB database for auth code data"`
 	MongoAuthCodeCollection        string   `env:"MONGO_AUTHCODE_COLLECTION"         flag:"mongodb-authcode-collection"         flagDesc:"The name of the mongodb auth code collection"`
 	MongoAuthcodeRequestDatabase   string   `env:"MONGO_AUTHCODE_REQUEST_DATABASE"   flag:"mongodb-authcode-request-database"   flagDesc:"MongoDB database for auth code request data"`
@@ -30,7 +30,35 @@ type Config struct {
 	CHSAPIKey                      string   `env:"CHS_API_KEY"                     	 flag:"chs-api-key"                       	flagDesc:"API access key"`
 	APIKey                         string   `env:"API_KEY"                     	     flag:"api-key"                       	    flagDesc:"API access key (internal privileges)"`
 	NewAuthCodeAPIFlow             bool     `env:"NEW_AUTHCODE_API_FLOW"             flag:"new-authcode-api-flow"             	flagDesc:"New AuthCode API Flow ["true"|"false"]"`
+	LetterBackend                  string   `env:"LETTER_BACKEND"                    flag:"letter-backend"                      flagDesc:"Letter backend, one of queue-api, authcode-api or file, chosen using the new AuthCode API flow if not set"`
+	LetterShadowBackend            string   `env:"LETTER_SHADOW_BACKEND"             flag:"letter-shadow-backend"               flagDesc:"Letter backend with a dry run which validates every letter sent, without sending it, for comparison with the letter backend"`
+	LetterFilePath                 string   `env:"LETTER_FILE_PATH"                  flag:"letter-file-path"                    flagDesc:"File to which letters are appended by the file letter backend"`
 	ChsKafkaApiURL                 string   `env:"CHS_KAFKA_API_URL"                 flag:"chs-kafka-api-url"                   flagDesc:"CHS Kafka API URL"`
+	MongoAuthCodeOutboxCollection  string   `env:"MONGO_AUTHCODE_OUTBOX_COLLECTION"  flag:"mongodb-authcode-outbox-collection"  flagDesc:"The name of the mongodb auth code request outbox collection"`
+	MongoAuthCodeAuditCollection   string   `env:"MONGO_AUTHCODE_AUDIT_COLLECTION"   flag:"mongodb-authcode-audit-collection"   flagDesc:"The name of the mongodb auth code request audit collection"`
+	OutboxDispatchIntervalSeconds  int      `env:"OUTBOX_DISPATCH_INTERVAL_SECONDS"  flag:"outbox-dispatch-interval-seconds"    flagDesc:"Interval in seconds between outbox dispatch runs"`
+	OutboxMaxAttempts              int      `env:"OUTBOX_MAX_ATTEMPTS"               flag:"outbox-max-attempts"                 flagDesc:"Maximum number of delivery attempts for an outbox item"`
+	CompanySubmissionWindowHours   int      `env:"COMPANY_SUBMISSION_WINDOW_HOURS"   flag:"company-submission-window-hours"     flagDesc:"Period in hours over which submissions for a company are limited"`
+	CompanySubmissionLimit         int      `env:"COMPANY_SUBMISSION_LIMIT"          flag:"company-submission-limit"            flagDesc:"Maximum number of submissions for a company within its window"`
+	UserSubmissionWindowHours      int      `env:"USER_SUBMISSION_WINDOW_HOURS"      flag:"user-submission-window-hours"        flagDesc:"Period in hours over which submissions by a user are limited"`
+	UserSubmissionLimit            int      `env:"USER_SUBMISSION_LIMIT"             flag:"user-submission-limit"               flagDesc:"Maximum number of submissions by a user within their window"`
+	OfficerSubmissionWindowHours   int      `env:"OFFICER_SUBMISSION_WINDOW_HOURS"   flag:"officer-submission-window-hours"     flagDesc:"Period in hours over which submissions for an officer are limited"`
+	OfficerSubmissionLimit         int      `env:"OFFICER_SUBMISSION_LIMIT"          flag:"officer-submission-limit"            flagDesc:"Maximum number of submissions for an officer, across all companies, within their window"`
+	AddressSubmissionWindowHours   int      `env:"ADDRESS_SUBMISSION_WINDOW_HOURS"   flag:"address-submission-window-hours"     flagDesc:"Period in hours over which submissions to an officer's address are limited"`
+	AddressSubmissionLimit         int      `env:"ADDRESS_SUBMISSION_LIMIT"          flag:"address-submission-limit"            flagDesc:"Maximum number of submissions to an officer's address, across all companies, within its window"`
+	SubmissionLimitExemptCompanies []string `env:"SUBMISSION_LIMIT_EXEMPT_COMPANIES" flag:"submission-limit-exempt-companies"   flagDesc:"Company numbers which are exempt from the submission limits"`
+	SubmissionLimitExemptUsers     []string `env:"SUBMISSION_LIMIT_EXEMPT_USERS"     flag:"submission-limit-exempt-users"       flagDesc:"User emails which are exempt from the submission limits"`
+	PendingResumeHours             int      `env:"PENDING_RESUME_HOURS"              flag:"pending-resume-hours"                flagDesc:"Pending requests created within this many hours are resumed instead of creating another for the same user and company"`
+	PendingExpiryDays              int      `env:"PENDING_EXPIRY_DAYS"               flag:"pending-expiry-days"                 flagDesc:"Pending requests which have not been submitted this many days after they were created are expired"`
+	ExpirySweepIntervalMinutes     int      `env:"EXPIRY_SWEEP_INTERVAL_MINUTES"     flag:"expiry-sweep-interval-minutes"       flagDesc:"Interval in minutes between runs expiring pending requests"`
+	SubmittingTimeoutMinutes       int      `env:"SUBMITTING_TIMEOUT_MINUTES"        flag:"submitting-timeout-minutes"          flagDesc:"Requests still submitting this many minutes after submission started are returned to pending"`
+	RetentionAnonymiseDays         int      `env:"RETENTION_ANONYMISE_DAYS"          flag:"retention-anonymise-days"            flagDesc:"Personal data is removed from requests which are no longer open this many days after they were submitted, or created if never submitted"`
+	RetentionDeleteDays            int      `env:"RETENTION_DELETE_DAYS"             flag:"retention-delete-days"               flagDesc:"Requests which are no longer open are deleted this many days after they were submitted, or created if never submitted"`
+	RetentionDryRun                bool     `env:"RETENTION_DRY_RUN"                 flag:"retention-dry-run"                   flagDesc:"Report the requests the retention job would anonymise and delete without changing them"`
+	RetentionIntervalHours         int      `env:"RETENTION_INTERVAL_HOURS"          flag:"retention-interval-hours"            flagDesc:"Interval in hours between runs of the retention job"`
+	HoldOfficerAppointedDays       int      `env:"HOLD_OFFICER_APPOINTED_DAYS"       flag:"hold-officer-appointed-days"         flagDesc:"Submissions for an officer appointed within this many days are held for review"`
+	HoldUserCompanyWindowHours     int      `env:"HOLD_USER_COMPANY_WINDOW_HOURS"    flag:"hold-user-company-window-hours"      flagDesc:"Period in hours over which the companies a user has requested for are counted"`
+	HoldUserCompanyLimit           int      `env:"HOLD_USER_COMPANY_LIMIT"           flag:"hold-user-company-limit"             flagDesc:"Submissions by a user who has requested for more than this many companies within the window are held for review"`
 }
 
 // Get returns a pointer to a Config instance populated with values from environment or command-line flags
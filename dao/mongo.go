@@ -2,19 +2,48 @@ package dao
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var client *mongo.Client
 
+var (
+	// ErrStatusChanged is returned when an authcode request is no longer in the status it was expected to be in
+	ErrStatusChanged = errors.New("auth code request status has changed")
+
+	// ErrEtagMismatch is returned when an authcode request no longer has the etag it was expected to have
+	ErrEtagMismatch = errors.New("auth code request etag does not match")
+
+	// ErrNotFound is returned when an authcode request to be updated does not exist
+	ErrNotFound = errors.New("auth code request not found")
+
+	// ErrEtagRequired is returned when an authcode request is to be updated without the etag it was read with
+	ErrEtagRequired = errors.New("auth code request etag is required")
+
+	// ErrDuplicateIdempotencyKey is returned when the user has already created an authcode request with the
+	// same idempotency key
+	ErrDuplicateIdempotencyKey = errors.New("auth code request idempotency key already used")
+
+	// ErrNoDataSubject is returned when a data subject has neither a user ID nor an email to match on
+	ErrNoDataSubject = errors.New("data subject must have a user ID or email")
+)
+
+// idempotencyKeyIndexName is the name of the index ensuring a user's idempotency keys are unique
+const idempotencyKeyIndexName = "idempotency_keys"
+
 func getMongoClient(mongoDBURL string) *mongo.Client {
 	if client != nil {
 		return client
@@ -49,6 +78,7 @@ func getMongoClient(mongoDBURL string) *mongo.Client {
 // MongoDatabaseInterface is an interface that describes the mongodb driver
 type MongoDatabaseInterface interface {
 	Collection(name string, opts ...*options.CollectionOptions) *mongo.Collection
+	Client() *mongo.Client
 }
 
 func getMongoDatabase(mongoDBURL, databaseName string) MongoDatabaseInterface {
@@ -57,8 +87,9 @@ func getMongoDatabase(mongoDBURL, databaseName string) MongoDatabaseInterface {
 
 // MongoService is an implementation of the Service interface using MongoDB as the backend driver.
 type MongoService struct {
-	db             MongoDatabaseInterface
-	CollectionName string
+	db                   MongoDatabaseInterface
+	CollectionName       string
+	OutboxCollectionName string
 }
 
 // CompanyHasAuthCode checks whether a company has an active auth code
@@ -84,47 +115,465 @@ func (m *MongoService) UpsertEmptyAuthCode(companyNumber string) error {
 
 // InsertAuthCodeRequest inserts an auth code request into the db
 func (m *MongoService) InsertAuthCodeRequest(dao *models.AuthCodeRequestResourceDao) error {
+	dao.Data.OfficerUraHash = hashOfficerUraID(dao.Data.OfficerUraID)
+
 	collection := m.db.Collection(m.CollectionName)
 	_, err := collection.InsertOne(context.Background(), dao)
+	if err != nil && len(dao.Data.IdempotencyKeys) > 0 && mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateIdempotencyKey
+	}
 	return err
 }
 
-// UpdateAuthCodeRequestOfficer updates an authcode request with officer details
-func (m *MongoService) UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao) error {
+// EnsureAuthCodeRequestIndexes creates the indexes required on the authcode request collection, if they do
+// not already exist. Idempotency keys are unique for each user across all of their requests, whether the key
+// was used to create or resume the request, and requests without one are not indexed.
+func (m *MongoService) EnsureAuthCodeRequestIndexes() error {
 	collection := m.db.Collection(m.CollectionName)
 
-	filter := bson.M{"_id": dao.ID}
+	index := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "data.created_by.user_id", Value: 1},
+			{Key: "data.idempotency_keys.key", Value: 1},
+		},
+		Options: options.Index().
+			SetName(idempotencyKeyIndexName).
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"data.idempotency_keys.key": bson.M{"$exists": true}}),
+	}
+
+	_, err := collection.Indexes().CreateOne(context.Background(), index)
+	return err
+}
+
+// CheckTransactionSupport returns an error if the MongoDB deployment is a standalone mongod, which does
+// not support the transactions used to record outbox items along with the authcode request they belong to.
+// Only replica sets, which report a set name, and sharded clusters, whose mongos reports isdbgrid, do.
+func (m *MongoService) CheckTransactionSupport() error {
+	var result struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+
+	admin := m.db.Client().Database("admin")
+	err := admin.RunCommand(context.Background(), bson.D{{Key: "hello", Value: 1}}).Decode(&result)
+	if err != nil {
+		// servers before MongoDB 4.4.2 only support the legacy name of the command
+		if err = admin.RunCommand(context.Background(), bson.D{{Key: "isMaster", Value: 1}}).Decode(&result); err != nil {
+			return err
+		}
+	}
+
+	if result.SetName == "" && result.Msg != "isdbgrid" {
+		return errors.New("mongodb is a standalone server, but must be a replica set or sharded cluster as transactions are used to record outbox items")
+	}
+
+	return nil
+}
+
+// GetAuthCodeRequestByIdempotencyKey returns the auth code request created or resumed by a user with the
+// supplied idempotency key, or nil if there is none
+func (m *MongoService) GetAuthCodeRequestByIdempotencyKey(userID, idempotencyKey string) (*models.AuthCodeRequestResourceDao, error) {
+	var resource models.AuthCodeRequestResourceDao
+
+	collection := m.db.Collection(m.CollectionName)
+	filter := bson.M{"data.created_by.user_id": userID, "data.idempotency_keys.key": idempotencyKey}
+
+	err := collection.FindOne(context.Background(), filter).Decode(&resource)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &resource, nil
+}
+
+// UpdateAuthCodeRequestOfficer updates an authcode request with officer details, and generates a new
+// etag which is set in the supplied dao. The update is only applied if the request still has the expected
+// etag, which must be supplied. ErrEtagMismatch is returned if it does not, and ErrNotFound if the request
+// no longer exists.
+func (m *MongoService) UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao, expectedEtag string) error {
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return err
+	}
+
+	if expectedEtag == "" {
+		return ErrEtagRequired
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	filter := bson.M{"_id": dao.ID, "data.etag": expectedEtag}
 	update := bson.M{
 		"$set": bson.M{
 			"data.officer_id":       dao.Data.OfficerID,
 			"data.officer_forename": dao.Data.OfficerForename,
 			"data.officer_surname":  dao.Data.OfficerSurname,
 			"data.officer_ura_id":   dao.Data.OfficerUraID,
+			"data.officer_ura_hash": hashOfficerUraID(dao.Data.OfficerUraID),
+			"data.etag":             etag,
 		},
 	}
 
-	_, err := collection.UpdateOne(context.Background(), filter, update)
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return m.etagMismatchOrNotFound(dao.ID)
+	}
 
-	return err
+	dao.Data.Etag = etag
+
+	return nil
+}
+
+// ResumeAuthCodeRequest updates a pending authcode request being resumed with the officer details in the
+// supplied dao, recording the idempotency key it was resumed with, if any, in the same write. A new etag is
+// generated and set in the dao. The update is only applied if the request still has the expected etag, so
+// has not been submitted or otherwise modified since it was read. ErrEtagMismatch is returned if it has,
+// ErrNotFound if the request no longer exists, and ErrDuplicateIdempotencyKey if the user has already used
+// the idempotency key.
+func (m *MongoService) ResumeAuthCodeRequest(dao *models.AuthCodeRequestResourceDao, expectedEtag string, idempotencyKey *models.IdempotencyKeyDao) error {
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return err
+	}
+
+	if expectedEtag == "" {
+		return ErrEtagRequired
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	filter := bson.M{"_id": dao.ID, "data.etag": expectedEtag}
+	update := bson.M{
+		"$set": bson.M{
+			"data.officer_id":       dao.Data.OfficerID,
+			"data.officer_forename": dao.Data.OfficerForename,
+			"data.officer_surname":  dao.Data.OfficerSurname,
+			"data.officer_ura_id":   dao.Data.OfficerUraID,
+			"data.officer_ura_hash": hashOfficerUraID(dao.Data.OfficerUraID),
+			"data.etag":             etag,
+		},
+	}
+	if idempotencyKey != nil {
+		update["$push"] = bson.M{"data.idempotency_keys": idempotencyKey}
+	}
+
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if idempotencyKey != nil && mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateIdempotencyKey
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return m.etagMismatchOrNotFound(dao.ID)
+	}
+
+	dao.Data.Etag = etag
+
+	return nil
 }
 
-// UpdateAuthCodeRequestStatus updates an authcode request with status details
-func (m *MongoService) UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao) error {
+// hashOfficerUraID returns the hash of an officer's usual residential address ID which is kept when the
+// request is anonymised, so that requests sent to the address still count towards its submission limit
+func hashOfficerUraID(officerUraID string) string {
+	if officerUraID == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(officerUraID))
+	return hex.EncodeToString(hash[:])
+}
+
+// etagMismatchOrNotFound returns the reason an authcode request was not matched by its ID and etag, which is
+// ErrNotFound if there is no request with the ID and ErrEtagMismatch if its etag has changed
+func (m *MongoService) etagMismatchOrNotFound(authCodeRequestID string) error {
 	collection := m.db.Collection(m.CollectionName)
 
-	filter := bson.M{"_id": dao.ID}
+	count, err := collection.CountDocuments(context.Background(), bson.M{"_id": authCodeRequestID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+
+	return ErrEtagMismatch
+}
+
+// UpdateAuthCodeRequestStatus moves an authcode request from the supplied status to the status held in
+// the dao, along with the other submission details. Any outbox items supplied are inserted in the same
+// transaction, so they are recorded if and only if the status update is. ErrStatusChanged is returned
+// if the request is no longer in the supplied status.
+func (m *MongoService) UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao, fromStatus models.RequestStatus, outboxItems []models.OutboxItemDao) error {
+	transition, err := models.NewStatusTransition(fromStatus, dao.Data.Status)
+	if err != nil {
+		return err
+	}
+
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":         dao.ID,
+		"data.status": transition.From,
+	}
 	update := bson.M{
 		"$set": bson.M{
-			"data.status":       dao.Data.Status,
+			"data.status":       transition.To,
 			"data.type":         dao.Data.Type,
 			"data.submitted_at": dao.Data.SubmittedAt,
+			"data.etag":         etag,
 		},
+		"$push": bson.M{
+			"data.status_history": transition,
+		},
+	}
+
+	return m.updateAuthCodeRequestWithOutbox(filter, update, outboxItems)
+}
+
+// ResendAuthCodeRequest records the letter for a dispatched authcode request being sent again, inserting
+// the supplied outbox items in the same transaction. A request whose letter was returned is moved back to
+// submitted, so that the new letter is tracked from dispatch. ErrStatusChanged is returned if the letter
+// for the request is no longer dispatched, printed or posted, or is no longer returned if it was returned
+// when read. A submitted request is never resent, as its letter is still waiting in the outbox.
+func (m *MongoService) ResendAuthCodeRequest(authCodeRequestID string, fromStatus models.RequestStatus, resend models.ResendDao, outboxItems []models.OutboxItemDao) error {
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":         authCodeRequestID,
+		"data.status": bson.M{"$in": models.ResendableStatuses, "$ne": models.StatusReturned},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"data.etag": etag,
+		},
+		"$push": bson.M{
+			"data.resends": resend,
+		},
+	}
+
+	if fromStatus == models.StatusReturned {
+		transition, err := models.NewStatusTransition(models.StatusReturned, models.StatusSubmitted)
+		if err != nil {
+			return err
+		}
+		filter["data.status"] = transition.From
+		update["$set"].(bson.M)["data.status"] = transition.To
+		update["$push"].(bson.M)["data.status_history"] = transition
 	}
 
-	_, err := collection.UpdateOne(context.Background(), filter, update)
+	return m.updateAuthCodeRequestWithOutbox(filter, update, outboxItems)
+}
+
+// updateAuthCodeRequestWithOutbox applies the update to the authcode request matching the filter, inserting
+// any outbox items supplied in the same transaction. ErrStatusChanged is returned if no request matches.
+// Transactions are only supported by a replica set or sharded cluster, so writes with outbox items fail
+// against a standalone mongod.
+func (m *MongoService) updateAuthCodeRequestWithOutbox(filter, update bson.M, outboxItems []models.OutboxItemDao) error {
+	collection := m.db.Collection(m.CollectionName)
+
+	updateRequest := func(ctx context.Context) error {
+		result, err := collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return ErrStatusChanged
+		}
+		return nil
+	}
+
+	if len(outboxItems) == 0 {
+		return updateRequest(context.Background())
+	}
+
+	session, err := m.db.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+
+	_, err = session.WithTransaction(context.Background(), func(sessionContext mongo.SessionContext) (interface{}, error) {
+		if err := updateRequest(sessionContext); err != nil {
+			return nil, err
+		}
+
+		documents := make([]interface{}, len(outboxItems))
+		for i := range outboxItems {
+			documents[i] = outboxItems[i]
+		}
+
+		return m.db.Collection(m.OutboxCollectionName).InsertMany(sessionContext, documents)
+	})
+
 	return err
 }
 
+// TransitionAuthCodeRequestStatus atomically moves an authcode request from one status to another,
+// recording the transition in the status history of the request. The update is only applied if the
+// request is still in the expected status, and false is returned if it is not, so only one caller can
+// ever make a given transition. An error is returned if the lifecycle does not permit the transition.
+func (m *MongoService) TransitionAuthCodeRequestStatus(authCodeRequestID string, fromStatus, toStatus models.RequestStatus) (bool, error) {
+	return m.transitionStatus(authCodeRequestID, fromStatus, toStatus, bson.M{}, bson.M{})
+}
+
+// StartAuthCodeRequestSubmission moves a pending authcode request to submitting, if it has not been modified
+// since it was read with the expected etag. False is returned if the request is no longer pending, and
+// ErrEtagMismatch if it is but has been modified.
+func (m *MongoService) StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag string) (bool, error) {
+	if expectedEtag == "" {
+		return false, ErrEtagRequired
+	}
+
+	transitioned, err := m.transitionStatusMatching(authCodeRequestID, models.StatusPending, models.StatusSubmitting,
+		bson.M{"data.etag": expectedEtag}, bson.M{}, bson.M{})
+	if err != nil || transitioned {
+		return transitioned, err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	pending, err := collection.CountDocuments(context.Background(), bson.M{"_id": authCodeRequestID, "data.status": models.StatusPending})
+	if err != nil {
+		return false, err
+	}
+	if pending > 0 {
+		return false, ErrEtagMismatch
+	}
+
+	return false, nil
+}
+
+// HoldAuthCodeRequest moves an authcode request from submitting to held for review, recording the reasons it
+// was held. False is returned if the request was not submitting.
+func (m *MongoService) HoldAuthCodeRequest(authCodeRequestID string, reasons []models.HoldReason) (bool, error) {
+	return m.transitionStatus(authCodeRequestID, models.StatusSubmitting, models.StatusHeld,
+		bson.M{"data.hold_reasons": reasons}, bson.M{})
+}
+
+// ReviewAuthCodeRequest moves a held authcode request to the supplied status, recording the review which
+// decided it. False is returned if the request was not held.
+func (m *MongoService) ReviewAuthCodeRequest(authCodeRequestID string, toStatus models.RequestStatus, review models.ReviewDao) (bool, error) {
+	return m.transitionStatus(authCodeRequestID, models.StatusHeld, toStatus,
+		bson.M{}, bson.M{"data.reviews": review})
+}
+
+// RecordLetterDispatch records the letter for an authcode request being handed to a letter backend, along
+// with the acknowledgement returned by the backend
+func (m *MongoService) RecordLetterDispatch(authCodeRequestID string, dispatch models.LetterDispatchDao) error {
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	update := bson.M{
+		"$set":  bson.M{"data.etag": etag},
+		"$push": bson.M{"data.letter_dispatches": dispatch},
+	}
+
+	_, err = collection.UpdateOne(context.Background(), bson.M{"_id": authCodeRequestID}, update)
+	return err
+}
+
+// RecordLetterEvent records a stage reached by the letter for an authcode request, moving it from the
+// supplied status to the status for the event. If the statuses are the same the event is recorded without
+// changing the status. False is returned if the request was not in the from status.
+func (m *MongoService) RecordLetterEvent(authCodeRequestID string, fromStatus, toStatus models.RequestStatus, event models.LetterEventDao) (bool, error) {
+	if fromStatus != toStatus {
+		return m.transitionStatus(authCodeRequestID, fromStatus, toStatus,
+			bson.M{}, bson.M{"data.letter_events": event})
+	}
+
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return false, err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	filter := bson.M{
+		"_id":         authCodeRequestID,
+		"data.status": fromStatus,
+	}
+	update := bson.M{
+		"$set":  bson.M{"data.etag": etag},
+		"$push": bson.M{"data.letter_events": event},
+	}
+
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// transitionStatus moves an authcode request between the supplied statuses, applying the additional
+// fields to set and push in the same write. The time a request moves into submitting is recorded, so
+// that requests abandoned part way through submission can be found. False is returned if the request
+// was not in the from status.
+func (m *MongoService) transitionStatus(authCodeRequestID string, fromStatus, toStatus models.RequestStatus, set, push bson.M) (bool, error) {
+	return m.transitionStatusMatching(authCodeRequestID, fromStatus, toStatus, bson.M{}, set, push)
+}
+
+// transitionStatusMatching moves an authcode request from one status to another as transitionStatus does,
+// only if the request also matches the supplied fields
+func (m *MongoService) transitionStatusMatching(authCodeRequestID string, fromStatus, toStatus models.RequestStatus, match, set, push bson.M) (bool, error) {
+	transition, err := models.NewStatusTransition(fromStatus, toStatus)
+	if err != nil {
+		return false, err
+	}
+
+	etag, err := utils.GenerateEtag()
+	if err != nil {
+		return false, err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+
+	filter := bson.M{
+		"_id":         authCodeRequestID,
+		"data.status": transition.From,
+	}
+	for field, value := range match {
+		filter[field] = value
+	}
+
+	set["data.status"] = transition.To
+	set["data.etag"] = etag
+	if transition.To == models.StatusSubmitting {
+		set["data.submitting_at"] = transition.At
+	}
+	push["data.status_history"] = transition
+
+	update := bson.M{
+		"$set":  set,
+		"$push": push,
+	}
+
+	result, err := collection.UpdateOne(context.Background(), filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
 // GetAuthCodeRequest returns an auth code request from the db
 func (m *MongoService) GetAuthCodeRequest(authCodeRequestID string) (*models.AuthCodeRequestResourceDao, error) {
 	var resource models.AuthCodeRequestResourceDao
@@ -152,48 +601,683 @@ func (m *MongoService) GetAuthCodeRequest(authCodeRequestID string) (*models.Aut
 	return &resource, nil
 }
 
-// CheckMultipleCorporateBodySubmissions checks for multiple company submitted requests.
-// A maximum of one request every 3 days is permitted per company.
-func (m *MongoService) CheckMultipleCorporateBodySubmissions(companyNumber string) (bool, error) {
+// GetLatestPendingAuthCodeRequest returns the most recently created of a user's pending auth code requests
+// for a company which were created since the supplied time, or nil if there are none
+func (m *MongoService) GetLatestPendingAuthCodeRequest(userID, companyNumber string, createdSince time.Time) (*models.AuthCodeRequestResourceDao, error) {
+	var resource models.AuthCodeRequestResourceDao
 
 	collection := m.db.Collection(m.CollectionName)
-	dbResource := collection.FindOne(
-		context.Background(),
-		bson.M{
-			"data.company_number": companyNumber,
-			"data.status":         "submitted",
-			"data.submitted_at":   bson.M{"$gt": time.Now().AddDate(0, 0, -3)},
+
+	query := bson.M{
+		"data.created_by.user_id": userID,
+		"data.company_number":     companyNumber,
+		"data.status":             models.StatusPending,
+		"data.created_at":         bson.M{"$gte": createdSince},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "data.created_at", Value: -1}, {Key: "_id", Value: -1}})
+
+	err := collection.FindOne(context.Background(), query, opts).Decode(&resource)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error(err)
+		return nil, err
+	}
+
+	return &resource, nil
+}
+
+// ListStalePendingAuthCodeRequestIDs returns the IDs of up to the supplied limit of the pending auth code
+// requests which were created before the supplied time, oldest first
+func (m *MongoService) ListStalePendingAuthCodeRequestIDs(createdBefore time.Time, limit int) ([]string, error) {
+	query := bson.M{
+		"data.status":     models.StatusPending,
+		"data.created_at": bson.M{"$lt": createdBefore},
+	}
+
+	return m.findAuthCodeRequestIDs(query, limit)
+}
+
+// ListStaleSubmittingAuthCodeRequestIDs returns the IDs of up to the supplied limit of the requests which
+// moved into submitting before the supplied time, oldest first. Requests which predate the recording of
+// when submission started are matched on when they were created.
+func (m *MongoService) ListStaleSubmittingAuthCodeRequestIDs(submittingBefore time.Time, limit int) ([]string, error) {
+	query := bson.M{
+		"data.status": models.StatusSubmitting,
+		"$or": bson.A{
+			bson.M{"data.submitting_at": bson.M{"$lt": submittingBefore}},
+			bson.M{"data.submitting_at": nil, "data.created_at": bson.M{"$lt": submittingBefore}},
 		},
+	}
+
+	return m.findAuthCodeRequestIDs(query, limit)
+}
+
+// closedAuthCodeRequestsQuery matches the auth code requests which are no longer open and were submitted
+// before the supplied time, or if never submitted were created before it. Their age is measured from when
+// they were submitted as that is when they start to count towards the submission limits. Submitted requests
+// are open, as their letter has not yet been dispatched.
+func closedAuthCodeRequestsQuery(before time.Time) bson.M {
+	return bson.M{
+		"data.status": bson.M{"$nin": models.OpenStatuses},
+		"$or": bson.A{
+			bson.M{"data.submitted_at": bson.M{"$lt": before}},
+			bson.M{"data.submitted_at": nil, "data.created_at": bson.M{"$lt": before}},
+		},
+	}
+}
+
+// CountClosedAuthCodeRequests counts the auth code requests which are no longer open and were submitted, or
+// if never submitted were created, before the supplied time, excluding those already anonymised if requested.
+// As when they are anonymised or deleted, requests with outbox items still to be delivered are not counted.
+func (m *MongoService) CountClosedAuthCodeRequests(before time.Time, excludeAnonymised bool) (int64, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	query := closedAuthCodeRequestsQuery(before)
+	if excludeAnonymised {
+		query["data.anonymised_at"] = bson.M{"$exists": false}
+	}
+	if err := m.excludeUndeliveredAuthCodeRequests(query); err != nil {
+		return 0, err
+	}
+
+	return collection.CountDocuments(context.Background(), query)
+}
+
+// AnonymiseClosedAuthCodeRequests removes the personal data from up to the supplied limit of the closed
+// auth code requests submitted, or if never submitted created, before the supplied time which have not
+// already been anonymised, returning the IDs of the requests anonymised
+func (m *MongoService) AnonymiseClosedAuthCodeRequests(before time.Time, limit int) ([]string, error) {
+	query := closedAuthCodeRequestsQuery(before)
+	query["data.anonymised_at"] = bson.M{"$exists": false}
+
+	ids, err := m.findErasableAuthCodeRequestIDs(query, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	return ids, m.anonymiseAuthCodeRequests(ids)
+}
+
+// DeleteClosedAuthCodeRequests deletes up to the supplied limit of the closed auth code requests submitted,
+// or if never submitted created, before the supplied time, along with their outbox items, returning the IDs
+// of the requests deleted
+func (m *MongoService) DeleteClosedAuthCodeRequests(before time.Time, limit int) ([]string, error) {
+	ids, err := m.findErasableAuthCodeRequestIDs(closedAuthCodeRequestsQuery(before), limit)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	return ids, m.deleteAuthCodeRequests(ids)
+}
+
+// ListDataSubjectAuthCodeRequests returns every auth code request created by the data subject, matched on
+// their user ID or their email ignoring case, oldest first
+func (m *MongoService) ListDataSubjectAuthCodeRequests(subject models.DataSubject) ([]models.AuthCodeRequestResourceDao, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	conditions := bson.A{}
+	if subject.UserID != "" {
+		conditions = append(conditions, bson.M{"data.created_by.user_id": subject.UserID})
+	}
+	if subject.UserEmail != "" {
+		conditions = append(conditions, bson.M{"data.created_by.user_email": primitive.Regex{
+			Pattern: "^" + regexp.QuoteMeta(subject.UserEmail) + "$",
+			Options: "i",
+		}})
+	}
+	if len(conditions) == 0 {
+		return nil, ErrNoDataSubject
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "data.created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := collection.Find(context.Background(), bson.M{"$or": conditions}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	authCodeRequests := []models.AuthCodeRequestResourceDao{}
+	if err = cursor.All(context.Background(), &authCodeRequests); err != nil {
+		return nil, err
+	}
+
+	return authCodeRequests, nil
+}
+
+// ListUndeliveredAuthCodeRequestIDs returns those of the supplied auth code requests which have outbox items
+// still to be delivered, so cannot yet be anonymised or deleted
+func (m *MongoService) ListUndeliveredAuthCodeRequestIDs(authCodeRequestIDs []string) ([]string, error) {
+	undelivered, err := m.db.Collection(m.OutboxCollectionName).Distinct(
+		context.Background(),
+		"auth_code_request_id",
+		bson.M{"auth_code_request_id": bson.M{"$in": authCodeRequestIDs}, "status": models.OutboxStatusPending},
 	)
+	if err != nil {
+		return nil, err
+	}
 
-	err := dbResource.Err()
+	ids := make([]string, 0, len(undelivered))
+	for _, id := range undelivered {
+		if id, ok := id.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// AnonymiseAuthCodeRequests removes the personal data from those of the supplied auth code requests which
+// are closed and have not already been anonymised, returning the IDs of the requests anonymised
+func (m *MongoService) AnonymiseAuthCodeRequests(authCodeRequestIDs []string) ([]string, error) {
+	ids, err := m.findErasableAuthCodeRequestIDs(bson.M{
+		"_id":                bson.M{"$in": authCodeRequestIDs},
+		"data.status":        bson.M{"$nin": models.OpenStatuses},
+		"data.anonymised_at": bson.M{"$exists": false},
+	}, 0)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	return ids, m.anonymiseAuthCodeRequests(ids)
+}
+
+// DeleteAuthCodeRequests deletes those of the supplied auth code requests which are closed, along with
+// their outbox items, returning the IDs of the requests deleted
+func (m *MongoService) DeleteAuthCodeRequests(authCodeRequestIDs []string) ([]string, error) {
+	ids, err := m.findErasableAuthCodeRequestIDs(bson.M{
+		"_id":         bson.M{"$in": authCodeRequestIDs},
+		"data.status": bson.M{"$nin": models.OpenStatuses},
+	}, 0)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+
+	return ids, m.deleteAuthCodeRequests(ids)
+}
+
+// anonymiseAuthCodeRequests removes the officer names and address ID, the details of the user other than
+// their ID, and the free text recorded by staff and the AuthCode API, from the supplied auth code requests,
+// giving each a new etag. The user ID and the hash of the address ID are kept, so that the requests still
+// count towards the submission limits. Their delivered outbox items, which hold the letters and emails
+// sent, are deleted.
+func (m *MongoService) anonymiseAuthCodeRequests(authCodeRequestIDs []string) error {
+	anonymisedAt := time.Now().Truncate(time.Millisecond)
+
+	etag, err := utils.GenerateEtag()
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return false, nil
+		return err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+	update := bson.M{"$set": bson.M{
+		"data.officer_ura_id":        "",
+		"data.officer_forename":      "",
+		"data.officer_surname":       "",
+		"data.created_by.user_email": "",
+		"data.created_by.forename":   "",
+		"data.created_by.surname":    "",
+		"data.anonymised_at":         anonymisedAt,
+		"data.etag":                  etag,
+	}}
+
+	if _, err := collection.UpdateMany(context.Background(), bson.M{"_id": bson.M{"$in": authCodeRequestIDs}}, update); err != nil {
+		return err
+	}
+
+	// the free text of each review, resend and letter event, which may name the officer or their address,
+	// is removed separately for each array, as only requests which have the array can be updated
+	for array, field := range anonymisedFreeText {
+		filter := bson.M{
+			"_id":                  bson.M{"$in": authCodeRequestIDs},
+			"data." + array + ".0": bson.M{"$exists": true},
 		}
-		return false, err
+		update := bson.M{"$unset": bson.M{"data." + array + ".$[]." + field: ""}}
+
+		if _, err := collection.UpdateMany(context.Background(), filter, update); err != nil {
+			return err
+		}
+	}
+
+	return m.deleteOutboxItems(authCodeRequestIDs)
+}
+
+// anonymisedFreeText maps each array in an auth code request to the free text field of its elements
+// removed when the request is anonymised
+var anonymisedFreeText = map[string]string{
+	"reviews":       "reason",
+	"resends":       "note",
+	"letter_events": "detail",
+}
+
+// deleteAuthCodeRequests deletes the supplied auth code requests along with their delivered outbox items
+func (m *MongoService) deleteAuthCodeRequests(authCodeRequestIDs []string) error {
+	if err := m.deleteOutboxItems(authCodeRequestIDs); err != nil {
+		return err
+	}
+
+	collection := m.db.Collection(m.CollectionName)
+	_, err := collection.DeleteMany(context.Background(), bson.M{"_id": bson.M{"$in": authCodeRequestIDs}})
+	return err
+}
+
+// findErasableAuthCodeRequestIDs returns the IDs of up to the supplied limit of the auth code requests
+// matching the query which may be anonymised or deleted, oldest first. Requests with outbox items still to
+// be delivered are left until they have been, so that their letters and emails are not lost.
+func (m *MongoService) findErasableAuthCodeRequestIDs(query bson.M, limit int) ([]string, error) {
+	if err := m.excludeUndeliveredAuthCodeRequests(query); err != nil {
+		return nil, err
+	}
+
+	return m.findAuthCodeRequestIDs(query, limit)
+}
+
+// excludeUndeliveredAuthCodeRequests restricts the query to auth code requests which have no outbox items
+// still to be delivered
+func (m *MongoService) excludeUndeliveredAuthCodeRequests(query bson.M) error {
+	undelivered, err := m.db.Collection(m.OutboxCollectionName).Distinct(
+		context.Background(),
+		"auth_code_request_id",
+		bson.M{"status": models.OutboxStatusPending},
+	)
+	if err != nil || len(undelivered) == 0 {
+		return err
+	}
+
+	idQuery, ok := query["_id"].(bson.M)
+	if !ok {
+		idQuery = bson.M{}
+	}
+	idQuery["$nin"] = undelivered
+	query["_id"] = idQuery
+
+	return nil
+}
+
+// findAuthCodeRequestIDs returns the IDs of up to the supplied limit of the auth code requests matching the
+// query, oldest first. A limit of zero returns them all.
+func (m *MongoService) findAuthCodeRequestIDs(query bson.M, limit int) ([]string, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "data.created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"_id": 1}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []struct {
+		ID string `bson:"_id"`
+	}
+	if err = cursor.All(context.Background(), &results); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(results))
+	for i := range results {
+		ids[i] = results[i].ID
+	}
+
+	return ids, nil
+}
+
+// deleteOutboxItems deletes the outbox items of the supplied auth code requests which have been delivered,
+// or have failed. Items still to be delivered are kept, so that a letter or email recorded after the
+// requests were checked is still sent.
+func (m *MongoService) deleteOutboxItems(authCodeRequestIDs []string) error {
+	collection := m.db.Collection(m.OutboxCollectionName)
+
+	_, err := collection.DeleteMany(context.Background(), bson.M{
+		"auth_code_request_id": bson.M{"$in": authCodeRequestIDs},
+		"status":               bson.M{"$in": bson.A{models.OutboxStatusDone, models.OutboxStatusFailed}},
+	})
+	return err
+}
+
+// ListAuthCodeRequests returns a page of the auth code requests matching the supplied filter, newest
+// first, along with the total number of matching requests
+func (m *MongoService) ListAuthCodeRequests(filter models.AuthCodeRequestFilter, startIndex, itemsPerPage int) ([]models.AuthCodeRequestResourceDao, int64, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	query := bson.M{}
+	if filter.UserID != "" {
+		query["data.created_by.user_id"] = filter.UserID
+	}
+	if filter.Status != "" {
+		query["data.status"] = filter.Status
+	}
+	if filter.CompanyNumber != "" {
+		query["data.company_number"] = filter.CompanyNumber
+	}
+
+	totalResults, err := collection.CountDocuments(context.Background(), query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "data.created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(startIndex)).
+		SetLimit(int64(itemsPerPage))
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	authCodeRequests := []models.AuthCodeRequestResourceDao{}
+	if err = cursor.All(context.Background(), &authCodeRequests); err != nil {
+		return nil, 0, err
+	}
+
+	return authCodeRequests, totalResults, nil
+}
+
+// SearchAuthCodeRequests returns up to the search limit of the auth code requests matching the supplied
+// search, newest first, starting after the search cursor. User emails are matched ignoring case.
+func (m *MongoService) SearchAuthCodeRequests(search models.AuthCodeRequestSearch) ([]models.AuthCodeRequestResourceDao, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	conditions := bson.A{}
+	if search.CompanyNumber != "" {
+		conditions = append(conditions, bson.M{"data.company_number": search.CompanyNumber})
+	}
+	if search.UserEmail != "" {
+		conditions = append(conditions, bson.M{"data.created_by.user_email": primitive.Regex{
+			Pattern: "^" + regexp.QuoteMeta(search.UserEmail) + "$",
+			Options: "i",
+		}})
+	}
+	if search.OfficerID != "" {
+		conditions = append(conditions, bson.M{"data.officer_id": search.OfficerID})
+	}
+	if search.Status != "" {
+		conditions = append(conditions, bson.M{"data.status": search.Status})
+	}
+	if dateRange := dateRangeQuery(search.CreatedFrom, search.CreatedTo); dateRange != nil {
+		conditions = append(conditions, bson.M{"data.created_at": dateRange})
+	}
+	if dateRange := dateRangeQuery(search.SubmittedFrom, search.SubmittedTo); dateRange != nil {
+		conditions = append(conditions, bson.M{"data.submitted_at": dateRange})
+	}
+	if search.After != nil {
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"data.created_at": bson.M{"$lt": search.After.CreatedAt}},
+			bson.M{"data.created_at": search.After.CreatedAt, "_id": bson.M{"$lt": search.After.ID}},
+		}})
+	}
+
+	query := bson.M{}
+	if len(conditions) > 0 {
+		query["$and"] = conditions
 	}
 
-	return true, nil
+	opts := options.Find().
+		SetSort(bson.D{{Key: "data.created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(search.Limit))
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	authCodeRequests := []models.AuthCodeRequestResourceDao{}
+	if err = cursor.All(context.Background(), &authCodeRequests); err != nil {
+		return nil, err
+	}
+
+	return authCodeRequests, nil
 }
 
-// CheckMultipleUserSubmissions checks whether a user has submitted multiple requests.
-// A maximum of 3 user requests in a 24 hour period are permitted.
-func (m *MongoService) CheckMultipleUserSubmissions(email string) (bool, error) {
+// dateRangeQuery returns the query matching dates from the supplied time up to but excluding the supplied
+// time, or nil if neither is supplied
+func dateRangeQuery(from, to *time.Time) bson.M {
+	if from == nil && to == nil {
+		return nil
+	}
+
+	dateRange := bson.M{}
+	if from != nil {
+		dateRange["$gte"] = *from
+	}
+	if to != nil {
+		dateRange["$lt"] = *to
+	}
+	return dateRange
+}
+
+// CountCorporateBodySubmissions counts the requests submitted for a company since the supplied time, returning
+// the submission times of up to the supplied number of the most recent of them
+func (m *MongoService) CountCorporateBodySubmissions(companyNumber string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	return m.countSubmissions(bson.M{"data.company_number": companyNumber}, since, recent)
+}
 
+// CountUserSubmissions counts the requests submitted by a user since the supplied time, returning the
+// submission times of up to the supplied number of the most recent of them. Requests are matched on the
+// user ID, which is kept when they are anonymised.
+func (m *MongoService) CountUserSubmissions(userID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	return m.countSubmissions(bson.M{"data.created_by.user_id": userID}, since, recent)
+}
+
+// CountOfficerSubmissions counts the requests submitted for an officer, across all companies, since the
+// supplied time, returning the submission times of up to the supplied number of the most recent of them
+func (m *MongoService) CountOfficerSubmissions(officerID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	return m.countSubmissions(bson.M{"data.officer_id": officerID}, since, recent)
+}
+
+// CountOfficerAddressSubmissions counts the requests submitted to an officer's usual residential address,
+// across all companies, since the supplied time, returning the submission times of up to the supplied
+// number of the most recent of them. Requests are matched on the hash of the address ID, which is kept when
+// they are anonymised, or on the address ID for requests recorded before the hash was.
+func (m *MongoService) CountOfficerAddressSubmissions(officerUraID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	return m.countSubmissions(bson.M{"$or": bson.A{
+		bson.M{"data.officer_ura_hash": hashOfficerUraID(officerUraID)},
+		bson.M{"data.officer_ura_id": officerUraID},
+	}}, since, recent)
+}
+
+// CountUserCompanies counts the distinct companies for which a user has created requests since the supplied
+// time. Requests are matched on the user ID, which is kept when they are anonymised.
+func (m *MongoService) CountUserCompanies(userID string, since time.Time) (int, error) {
 	collection := m.db.Collection(m.CollectionName)
-	submissionCount, err := collection.CountDocuments(
+	companyNumbers, err := collection.Distinct(
 		context.Background(),
+		"data.company_number",
 		bson.M{
-			"data.created_by.user_email": email,
-			"data.status":                "submitted",
-			"data.submitted_at":          bson.M{"$gt": time.Now().AddDate(0, 0, -1)},
+			"data.created_by.user_id": userID,
+			"data.created_at":         bson.M{"$gt": since},
 		},
 	)
+	if err != nil {
+		return 0, err
+	}
 
+	return len(companyNumbers), nil
+}
+
+// countSubmissions counts the submitted requests matching the query since the supplied time
+func (m *MongoService) countSubmissions(query bson.M, since time.Time, recent int) (*models.SubmissionCount, error) {
+	query["data.status"] = bson.M{"$in": models.SubmittedStatuses}
+	query["data.submitted_at"] = bson.M{"$gt": since}
+
+	collection := m.db.Collection(m.CollectionName)
+	submissionCount, err := collection.CountDocuments(context.Background(), query)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	count := &models.SubmissionCount{Count: submissionCount}
+	if submissionCount == 0 || recent <= 0 {
+		return count, nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "data.submitted_at", Value: -1}}).
+		SetLimit(int64(recent)).
+		SetProjection(bson.M{"data.submitted_at": 1})
+
+	cursor, err := collection.Find(context.Background(), query, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	authCodeRequests := []models.AuthCodeRequestResourceDao{}
+	if err = cursor.All(context.Background(), &authCodeRequests); err != nil {
+		return nil, err
+	}
+
+	for _, authCodeRequest := range authCodeRequests {
+		if authCodeRequest.Data.SubmittedAt != nil {
+			count.SubmittedAt = append(count.SubmittedAt, *authCodeRequest.Data.SubmittedAt)
+		}
+	}
+
+	return count, nil
+}
+
+// ClaimOutboxItem leases the next outbox item which is due for delivery, so that no other dispatcher
+// will attempt it until the lease has expired. If there are no items due, nil is returned.
+func (m *MongoService) ClaimOutboxItem(leaseDuration time.Duration) (*models.OutboxItemDao, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	now := time.Now().Truncate(time.Millisecond)
+	filter := bson.M{
+		"status":          models.OutboxStatusPending,
+		"next_attempt_at": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt_at": now.Add(leaseDuration)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"next_attempt_at": 1}).
+		SetReturnDocument(options.After)
+
+	var item models.OutboxItemDao
+	err := collection.FindOneAndUpdate(context.Background(), filter, update, opts).Decode(&item)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+// CompleteOutboxItem marks an outbox item as delivered
+func (m *MongoService) CompleteOutboxItem(outboxItemID string) error {
+	collection := m.db.Collection(m.CollectionName)
+
+	completedAt := time.Now().Truncate(time.Millisecond)
+	update := bson.M{
+		"$set": bson.M{
+			"status":       models.OutboxStatusDone,
+			"completed_at": completedAt,
+		},
+	}
+
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": outboxItemID}, update)
+	return err
+}
+
+// RetryOutboxItem records a failed delivery attempt of an outbox item, and when it should next be attempted
+func (m *MongoService) RetryOutboxItem(outboxItemID, lastError string, nextAttemptAt time.Time) error {
+	collection := m.db.Collection(m.CollectionName)
+
+	update := bson.M{
+		"$set": bson.M{
+			"last_error":      lastError,
+			"next_attempt_at": nextAttemptAt,
+		},
+	}
+
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": outboxItemID}, update)
+	return err
+}
+
+// FailOutboxItem marks an outbox item as failed once it is no longer going to be retried
+func (m *MongoService) FailOutboxItem(outboxItemID, lastError string) error {
+	collection := m.db.Collection(m.CollectionName)
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":     models.OutboxStatusFailed,
+			"last_error": lastError,
+		},
+	}
+
+	_, err := collection.UpdateOne(context.Background(), bson.M{"_id": outboxItemID}, update)
+	return err
+}
+
+// InsertAuditEntry appends an entry to the audit trail of an auth code request
+func (m *MongoService) InsertAuditEntry(entry *models.AuditEntryDao) error {
+	collection := m.db.Collection(m.CollectionName)
+
+	_, err := collection.InsertOne(context.Background(), entry)
+	return err
+}
+
+// ListAuditEntries returns the audit trail of an auth code request, oldest entry first
+func (m *MongoService) ListAuditEntries(authCodeRequestID string) ([]models.AuditEntryDao, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	opts := options.Find().SetSort(bson.D{{Key: "at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := collection.Find(context.Background(), bson.M{"auth_code_request_id": authCodeRequestID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []models.AuditEntryDao{}
+	if err = cursor.All(context.Background(), &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// AnonymiseAuditEntries removes the officer names and address IDs, and the emails of users, from the audit
+// trails of the supplied auth code requests
+func (m *MongoService) AnonymiseAuditEntries(authCodeRequestIDs []string) (int64, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	filter := bson.M{"auth_code_request_id": bson.M{"$in": authCodeRequestIDs}}
+	update := bson.M{"$unset": bson.M{
+		"before.officer_ura_id":   "",
+		"before.officer_forename": "",
+		"before.officer_surname":  "",
+		"after.officer_ura_id":    "",
+		"after.officer_forename":  "",
+		"after.officer_surname":   "",
+	}}
+
+	result, err := collection.UpdateMany(context.Background(), filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	userFilter := bson.M{"auth_code_request_id": bson.M{"$in": authCodeRequestIDs}, "actor_type": models.ActorTypeUser}
+	if _, err := collection.UpdateMany(context.Background(), userFilter, bson.M{"$set": bson.M{"actor.email": ""}}); err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// DeleteAuditEntries deletes the audit trails of the supplied auth code requests
+func (m *MongoService) DeleteAuditEntries(authCodeRequestIDs []string) (int64, error) {
+	collection := m.db.Collection(m.CollectionName)
+
+	result, err := collection.DeleteMany(context.Background(), bson.M{"auth_code_request_id": bson.M{"$in": authCodeRequestIDs}})
+	if err != nil {
+		return 0, err
 	}
 
-	return submissionCount >= 3, nil
+	return result.DeletedCount, nil
 }
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	"github.com/companieshouse/emergency-auth-code-api/config"
 	"github.com/companieshouse/emergency-auth-code-api/models"
 )
@@ -19,14 +21,92 @@ type AuthcodeRequestDAOService interface {
 	InsertAuthCodeRequest(dao *models.AuthCodeRequestResourceDao) error
 	// GetAuthCodeRequest returns an auth-code-request
 	GetAuthCodeRequest(authCodeRequestID string) (*models.AuthCodeRequestResourceDao, error)
-	// UpdateAuthCodeRequestOfficer updates the officer details in an auth-code-request
-	UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao) error
-	// UpdateAuthCodeRequestStatus updates the status in an auth-code-request
-	UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao) error
-	// CheckMultipleCorporateBodySubmissions checks whether multiple requests have been made for a company
-	CheckMultipleCorporateBodySubmissions(companyNumber string) (bool, error)
-	// CheckMultipleUserSubmissions checks whether multiple requests have been made for a user
-	CheckMultipleUserSubmissions(email string) (bool, error)
+	// GetAuthCodeRequestByIdempotencyKey returns the auth-code-request created or resumed by a user with an idempotency key
+	GetAuthCodeRequestByIdempotencyKey(userID, idempotencyKey string) (*models.AuthCodeRequestResourceDao, error)
+	// GetLatestPendingAuthCodeRequest returns a user's most recently created pending auth-code-request for a company, created since the supplied time
+	GetLatestPendingAuthCodeRequest(userID, companyNumber string, createdSince time.Time) (*models.AuthCodeRequestResourceDao, error)
+	// ListStalePendingAuthCodeRequestIDs returns the IDs of up to limit pending auth-code-requests created before the supplied time
+	ListStalePendingAuthCodeRequestIDs(createdBefore time.Time, limit int) ([]string, error)
+	// ListStaleSubmittingAuthCodeRequestIDs returns the IDs of up to limit auth-code-requests which moved into submitting before the supplied time
+	ListStaleSubmittingAuthCodeRequestIDs(submittingBefore time.Time, limit int) ([]string, error)
+	// CountClosedAuthCodeRequests counts the auth-code-requests which are no longer open and were submitted, or if never submitted created, before the supplied time, optionally excluding those already anonymised
+	CountClosedAuthCodeRequests(before time.Time, excludeAnonymised bool) (int64, error)
+	// AnonymiseClosedAuthCodeRequests removes the personal data from up to limit closed auth-code-requests submitted, or if never submitted created, before the supplied time, returning their IDs
+	AnonymiseClosedAuthCodeRequests(before time.Time, limit int) ([]string, error)
+	// DeleteClosedAuthCodeRequests deletes up to limit closed auth-code-requests submitted, or if never submitted created, before the supplied time, returning their IDs
+	DeleteClosedAuthCodeRequests(before time.Time, limit int) ([]string, error)
+	// ListDataSubjectAuthCodeRequests returns every auth-code-request created by a data subject, oldest first
+	ListDataSubjectAuthCodeRequests(subject models.DataSubject) ([]models.AuthCodeRequestResourceDao, error)
+	// ListUndeliveredAuthCodeRequestIDs returns those of the supplied auth-code-requests which have outbox items still to be delivered
+	ListUndeliveredAuthCodeRequestIDs(authCodeRequestIDs []string) ([]string, error)
+	// AnonymiseAuthCodeRequests removes the personal data from those of the supplied auth-code-requests which are closed, returning their IDs
+	AnonymiseAuthCodeRequests(authCodeRequestIDs []string) ([]string, error)
+	// DeleteAuthCodeRequests deletes those of the supplied auth-code-requests which are closed, returning their IDs
+	DeleteAuthCodeRequests(authCodeRequestIDs []string) ([]string, error)
+	// EnsureAuthCodeRequestIndexes creates the indexes required on auth-code-requests
+	EnsureAuthCodeRequestIndexes() error
+	// CheckTransactionSupport returns an error if the database does not support the transactions used to record outbox items
+	CheckTransactionSupport() error
+	// UpdateAuthCodeRequestOfficer updates the officer details in an auth-code-request, if it exists and still has the expected etag
+	UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao, expectedEtag string) error
+	// ResumeAuthCodeRequest updates the officer details in a pending auth-code-request being resumed, recording the idempotency key it was resumed with, if it still has the expected etag
+	ResumeAuthCodeRequest(dao *models.AuthCodeRequestResourceDao, expectedEtag string, idempotencyKey *models.IdempotencyKeyDao) error
+	// UpdateAuthCodeRequestStatus updates the status in an auth-code-request, recording any outbox items in the same write
+	UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao, fromStatus models.RequestStatus, outboxItems []models.OutboxItemDao) error
+	// ResendAuthCodeRequest records the letter for a dispatched auth-code-request being sent again, along with its outbox items, moving a returned request back to submitted
+	ResendAuthCodeRequest(authCodeRequestID string, fromStatus models.RequestStatus, resend models.ResendDao, outboxItems []models.OutboxItemDao) error
+	// StartAuthCodeRequestSubmission moves a pending auth-code-request to submitting, if it still has the expected etag
+	StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag string) (bool, error)
+	// TransitionAuthCodeRequestStatus moves an auth-code-request from one status to another, if it is still in the expected status
+	TransitionAuthCodeRequestStatus(authCodeRequestID string, fromStatus, toStatus models.RequestStatus) (bool, error)
+	// ListAuthCodeRequests returns a page of the auth-code-requests matching a filter, and the total number matching
+	ListAuthCodeRequests(filter models.AuthCodeRequestFilter, startIndex, itemsPerPage int) ([]models.AuthCodeRequestResourceDao, int64, error)
+	// SearchAuthCodeRequests returns a page of the auth-code-requests matching an administrator's search
+	SearchAuthCodeRequests(search models.AuthCodeRequestSearch) ([]models.AuthCodeRequestResourceDao, error)
+	// CountCorporateBodySubmissions counts the requests submitted for a company since the supplied time
+	CountCorporateBodySubmissions(companyNumber string, since time.Time, recent int) (*models.SubmissionCount, error)
+	// CountUserSubmissions counts the requests submitted by a user since the supplied time
+	CountUserSubmissions(userID string, since time.Time, recent int) (*models.SubmissionCount, error)
+	// CountOfficerSubmissions counts the requests submitted for an officer across all companies since the supplied time
+	CountOfficerSubmissions(officerID string, since time.Time, recent int) (*models.SubmissionCount, error)
+	// CountOfficerAddressSubmissions counts the requests submitted to an officer's usual residential address since the supplied time
+	CountOfficerAddressSubmissions(officerUraID string, since time.Time, recent int) (*models.SubmissionCount, error)
+	// CountUserCompanies counts the distinct companies for which a user has created requests since the supplied time
+	CountUserCompanies(userID string, since time.Time) (int, error)
+	// HoldAuthCodeRequest moves a submitting auth-code-request to held for review
+	HoldAuthCodeRequest(authCodeRequestID string, reasons []models.HoldReason) (bool, error)
+	// ReviewAuthCodeRequest moves a held auth-code-request to the supplied status, recording the review
+	ReviewAuthCodeRequest(authCodeRequestID string, toStatus models.RequestStatus, review models.ReviewDao) (bool, error)
+	// RecordLetterDispatch records the letter for an auth-code-request being handed to a letter backend
+	RecordLetterDispatch(authCodeRequestID string, dispatch models.LetterDispatchDao) error
+	// RecordLetterEvent records a stage reached by the letter for an auth-code-request, moving it to the supplied status
+	RecordLetterEvent(authCodeRequestID string, fromStatus, toStatus models.RequestStatus, event models.LetterEventDao) (bool, error)
+}
+
+// AuthcodeOutboxDAOService interface declares how to interact with the persistence layer regardless of underlying technology
+type AuthcodeOutboxDAOService interface {
+	// ClaimOutboxItem leases the next outbox item due for delivery
+	ClaimOutboxItem(leaseDuration time.Duration) (*models.OutboxItemDao, error)
+	// CompleteOutboxItem marks an outbox item as delivered
+	CompleteOutboxItem(outboxItemID string) error
+	// RetryOutboxItem records a failed delivery attempt of an outbox item
+	RetryOutboxItem(outboxItemID, lastError string, nextAttemptAt time.Time) error
+	// FailOutboxItem marks an outbox item as failed
+	FailOutboxItem(outboxItemID, lastError string) error
+}
+
+// AuthcodeAuditDAOService interface declares how to interact with the persistence layer regardless of underlying technology.
+// Audit entries can only be appended, and are otherwise only updated or removed to apply the data retention schedule
+// or to erase the data of a user on their request.
+type AuthcodeAuditDAOService interface {
+	// InsertAuditEntry appends an entry to the audit trail of an auth-code-request
+	InsertAuditEntry(entry *models.AuditEntryDao) error
+	// AnonymiseAuditEntries removes the personal data from the audit trails of the supplied auth-code-requests
+	AnonymiseAuditEntries(authCodeRequestIDs []string) (int64, error)
+	// DeleteAuditEntries deletes the audit trails of the supplied auth-code-requests
+	DeleteAuditEntries(authCodeRequestIDs []string) (int64, error)
+	// ListAuditEntries returns the audit trail of an auth-code-request, oldest entry first
+	ListAuditEntries(authCodeRequestID string) ([]models.AuditEntryDao, error)
 }
 
 // NewAuthCodeDAOService will create a new instance of the AuthCode Service interface.
@@ -44,9 +124,32 @@ func NewAuthCodeDAOService(cfg *config.Config) AuthcodeDAOService {
 // All details about its implementation and the
 // database driver will be hidden from outside of this package
 func NewAuthCodeRequestDAOService(cfg *config.Config) AuthcodeRequestDAOService {
+	database := getMongoDatabase(cfg.MongoDBURL, cfg.MongoAuthcodeRequestDatabase)
+	return &MongoService{
+		db:                   database,
+		CollectionName:       cfg.MongoAuthCodeRequestCollection,
+		OutboxCollectionName: cfg.MongoAuthCodeOutboxCollection,
+	}
+}
+
+// NewAuthCodeOutboxDAOService will create a new instance of the AuthCode Outbox Service interface.
+// All details about its implementation and the
+// database driver will be hidden from outside of this package
+func NewAuthCodeOutboxDAOService(cfg *config.Config) AuthcodeOutboxDAOService {
+	database := getMongoDatabase(cfg.MongoDBURL, cfg.MongoAuthcodeRequestDatabase)
+	return &MongoService{
+		db:             database,
+		CollectionName: cfg.MongoAuthCodeOutboxCollection,
+	}
+}
+
+// NewAuthCodeAuditDAOService will create a new instance of the AuthCode Audit Service interface.
+// All details about its implementation and the
+// database driver will be hidden from outside of this package
+func NewAuthCodeAuditDAOService(cfg *config.Config) AuthcodeAuditDAOService {
 	database := getMongoDatabase(cfg.MongoDBURL, cfg.MongoAuthcodeRequestDatabase)
 	return &MongoService{
 		db:             database,
-		CollectionName: cfg.MongoAuthCodeRequestCollection,
+		CollectionName: cfg.MongoAuthCodeAuditCollection,
 	}
 }
@@ -0,0 +1,31 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// GetAuthCodeRequestAudit returns the audit trail of the changes made to an auth code request
+func GetAuthCodeRequestAudit(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		authCodeRequestID := mux.Vars(req)["auth_code_request_id"]
+		if authCodeRequestID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "auth code request ID missing from request")
+			return
+		}
+
+		auditTrail, responseType := authCodeReqSvc.GetAuditTrail(authCodeRequestID)
+		switch responseType {
+		case service.Success:
+			utils.WriteJSON(w, req, auditTrail)
+		case service.NotFound:
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reading auth code request audit trail")
+		}
+	})
+}
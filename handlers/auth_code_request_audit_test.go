@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetAuthCodeRequestAuditHandler(t *testing.T) {
+	Convey("Get auth code request audit trail", t, func() {
+		adminContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		h := GetAuthCodeRequestAudit(&service.AuthCodeRequestService{DAO: mockDaoReqService, AuditDAO: mockAuditService})
+
+		Convey("auth code request ID missing", func() {
+			res := serveReviewHandler(adminContext, h, "", "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("error listing audit entries", func() {
+			mockAuditService.EXPECT().ListAuditEntries("123").Return(nil, fmt.Errorf("error"))
+
+			res := serveReviewHandler(adminContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("auth code request not found", func() {
+			mockAuditService.EXPECT().ListAuditEntries("123").Return([]models.AuditEntryDao{}, nil)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, nil)
+
+			res := serveReviewHandler(adminContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("audit trail returned", func() {
+			mockAuditService.EXPECT().ListAuditEntries("123").Return([]models.AuditEntryDao{
+				{ID: "1", Action: models.AuditActionCreated, Actor: models.ActorDao{ID: testUserID}, ActorType: models.ActorTypeUser, RequestID: "request-id"},
+			}, nil)
+
+			res := serveReviewHandler(adminContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"auth_code_request_id":"123"`)
+			So(res.Body.String(), ShouldContainSubstring, `"action":"created"`)
+			So(res.Body.String(), ShouldContainSubstring, `"request_id":"request-id"`)
+		})
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// CancelAuthCodeRequest cancels a pending auth code request for a specified auth-code-request ID
+func CancelAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		// Check for a auth-code-request ID in the request
+		vars := mux.Vars(req)
+		authCodeRequestID := vars["auth_code_request_id"]
+		if authCodeRequestID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "auth code request ID missing from request")
+			return
+		}
+
+		switch authCodeReqSvc.CancelAuthCodeRequest(authCodeRequestID, requester) {
+		case service.Success:
+		case service.NotFound:
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+			return
+		case service.Conflict:
+			utils.WriteErrorMessage(w, req, http.StatusConflict, "request can no longer be cancelled")
+			return
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error cancelling authcode request")
+			return
+		}
+
+		log.InfoR(req, "authcode request cancelled", log.Data{"auth_code_request_id": authCodeRequestID})
+
+		response, responseType := authCodeReqSvc.GetAuthCodeRequest(authCodeRequestID, requester)
+		if responseType != http.StatusOK {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reading authcode request")
+			return
+		}
+
+		utils.WriteJSONWithStatus(w, req, response, http.StatusOK)
+	})
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveCancelAuthCodeRequest(ctx context.Context, daoReqSvc dao.AuthcodeRequestDAOService, authCodeReqID string) *httptest.ResponseRecorder {
+	authCodeReqSvc := &service.AuthCodeRequestService{
+		DAO: daoReqSvc,
+	}
+
+	h := CancelAuthCodeRequest(authCodeReqSvc)
+	req := httptest.NewRequest(http.MethodDelete, "/", nil).WithContext(ctx)
+	if authCodeReqID != "" {
+		req = mux.SetURLVars(req, map[string]string{"auth_code_request_id": authCodeReqID})
+	}
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitCancelAuthCodeRequestHandler(t *testing.T) {
+	Convey("Cancel auth code request", t, func() {
+		userContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID})
+
+		Convey("user details not in context", func() {
+			res := serveCancelAuthCodeRequest(context.Background(), nil, "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user details not in request context"}`)
+		})
+
+		Convey("auth code request ID missing", func() {
+			res := serveCancelAuthCodeRequest(userContext, nil, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request ID missing from request"}`)
+		})
+
+		Convey("error reading auth code request", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, fmt.Errorf("error"))
+
+			res := serveCancelAuthCodeRequest(userContext, mockDaoReqService, "123")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error cancelling authcode request"}`)
+		})
+
+		Convey("request created by another user", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: "otherUser"},
+					Status:    models.StatusPending,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveCancelAuthCodeRequest(userContext, mockDaoReqService, "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request not found"}`)
+		})
+
+		Convey("request already submitted", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusSubmitted,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveCancelAuthCodeRequest(userContext, mockDaoReqService, "123")
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldStartWith, `{"message":"request can no longer be cancelled"}`)
+		})
+
+		Convey("request cancelled", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusPending,
+				},
+			}
+			cancelledDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusCancelled,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			gomock.InOrder(
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil),
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusPending, models.StatusCancelled).Return(true, nil),
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&cancelledDaoResponse, nil),
+			)
+
+			res := serveCancelAuthCodeRequest(userContext, mockDaoReqService, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"status":"cancelled"`)
+		})
+	})
+}
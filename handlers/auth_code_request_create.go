@@ -2,18 +2,30 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/companieshouse/chs.go/authentication"
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/oracle"
 	"github.com/companieshouse/emergency-auth-code-api/service"
 	"github.com/companieshouse/emergency-auth-code-api/transformers"
 	"github.com/companieshouse/emergency-auth-code-api/utils"
 )
 
-// CreateAuthCodeRequest creates the auth code request for a specific officer ID
+const (
+	// idempotencyKeyHeader may be supplied when creating an auth code request, so that it can be retried safely
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	maxIdempotencyKeyLength = 255
+)
+
+// CreateAuthCodeRequest creates the auth code request for a specific officer ID. If the user has a pending
+// request for the company which may be resumed, it is reused with the officer supplied and returned with
+// 200 OK. If an idempotency key is supplied and the user has already created or resumed a request with it,
+// the response to that request is replayed instead.
 func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		var (
@@ -48,23 +60,51 @@ func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.
 
 		createdBy := userDetails.(authentication.AuthUserDetails)
 
-		validCorporateBody, err := validateCorporateBody(req, authCodeReqSvc, request.CompanyNumber, createdBy.Email)
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		idempotencyKey := req.Header.Get(idempotencyKeyHeader)
+		var idempotencyHash string
+		if idempotencyKey != "" {
+			if len(idempotencyKey) > maxIdempotencyKeyLength {
+				utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("idempotency key must not be longer than %d characters", maxIdempotencyKeyLength))
+				return
+			}
+
+			idempotencyHash, err = service.IdempotencyHash(&request)
+			if err != nil {
+				log.ErrorR(req, fmt.Errorf("error hashing request body: %v", err))
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking idempotency key")
+				return
+			}
+
+			if writeIdempotentAuthCodeRequest(w, req, authCodeReqSvc, requester, idempotencyKey, idempotencyHash) {
+				return
+			}
+		}
+
+		eligibilityFailure, err := validateCorporateBody(req, authCodeReqSvc, request.CompanyNumber, createdBy.ID, createdBy.Email)
 
 		if err != nil {
 			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking corporate body")
 			return
 		}
 
-		if !validCorporateBody {
-			utils.WriteResponseMessage(w, req, http.StatusForbidden, "request not permitted for corporate body")
+		if eligibilityFailure != nil {
+			writeEligibilityFailure(w, req, eligibilityFailure)
 			return
 		}
 
 		request.CreatedBy = createdBy
 
+		var officer *oracle.Officer
 		if request.OfficerID != "" {
+			var officerResponse service.ResponseType
 			// retrieve details for officer from oracle-query-api
-			officer, officerResponse, err := service.GetOfficerDetails(request.CompanyNumber, request.OfficerID)
+			officer, officerResponse, err = service.GetOfficerDetails(request.CompanyNumber, request.OfficerID)
 			if err != nil {
 				log.ErrorR(req, fmt.Errorf("error calling Oracle API to get officer: %v", err))
 				m := models.NewMessageResponse("there was a problem communicating with the Oracle API")
@@ -76,6 +116,17 @@ func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.
 				utils.WriteJSONWithStatus(w, req, m, http.StatusNotFound)
 				return
 			}
+
+			officerFailure, err := authCodeReqSvc.CheckOfficerSubmissions(request.CompanyNumber, createdBy.Email, request.OfficerID, officer.UsualResidentialAddress.ID)
+			if err != nil {
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking officer submissions")
+				return
+			}
+			if officerFailure != nil {
+				writeEligibilityFailure(w, req, officerFailure)
+				return
+			}
+
 			request.OfficerUraID = officer.UsualResidentialAddress.ID
 			request.OfficerForename = officer.Forename
 			request.OfficerSurname = officer.Surname
@@ -91,6 +142,33 @@ func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.
 				return
 			}
 		}
+
+		var resumeIdempotencyKey *models.IdempotencyKeyDao
+		if idempotencyKey != "" {
+			resumeIdempotencyKey = &models.IdempotencyKeyDao{Key: idempotencyKey, Hash: idempotencyHash, Resumed: true}
+		}
+
+		pendingRequest, responseType := authCodeReqSvc.ResumePendingAuthCodeRequest(requester, request.CompanyNumber, officer, resumeIdempotencyKey)
+		switch responseType {
+		case service.Success:
+			utils.WriteJSONWithStatus(w, req, transformers.AuthCodeRequestResourceDaoToResponse(pendingRequest), http.StatusOK)
+			return
+		case service.NotFound, service.InvalidData:
+			// there is no pending request which may be resumed for the user
+		case service.Conflict:
+			// the key was used by a concurrent request after it was checked
+			if !writeIdempotentAuthCodeRequest(w, req, authCodeReqSvc, requester, idempotencyKey, idempotencyHash) {
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking idempotency key")
+			}
+			return
+		case service.PreconditionFailed:
+			utils.WriteErrorMessage(w, req, http.StatusConflict, "pending auth code request was modified while being resumed")
+			return
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error resuming pending auth code request")
+			return
+		}
+
 		model := transformers.AuthCodeResourceRequestToDB(&request)
 
 		companyName, err := service.GetCompanyName(request.CompanyNumber, authCodeReqSvc.Config.APIBaseURL, req)
@@ -101,8 +179,18 @@ func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.
 		}
 
 		model.Data.CompanyName = companyName
+		if idempotencyKey != "" {
+			model.Data.IdempotencyKeys = []models.IdempotencyKeyDao{{Key: idempotencyKey, Hash: idempotencyHash}}
+		}
 
-		err = authCodeReqSvc.CreateAuthCodeRequest(model)
+		err = authCodeReqSvc.CreateAuthCodeRequest(model, requester)
+		// the key was used by a concurrent request after it was checked
+		if errors.Is(err, service.ErrIdempotencyKeyUsed) {
+			if !writeIdempotentAuthCodeRequest(w, req, authCodeReqSvc, requester, idempotencyKey, idempotencyHash) {
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking idempotency key")
+			}
+			return
+		}
 		if err != nil {
 			log.ErrorR(req, fmt.Errorf("error creating Auth Code Request: %v", err))
 			w.WriteHeader(http.StatusInternalServerError)
@@ -112,37 +200,53 @@ func CreateAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.
 	})
 }
 
-func validateCorporateBody(req *http.Request, authCodeReqSvc *service.AuthCodeRequestService, companyNumber string, email string) (bool, error) {
-
-	// Check whether multiple submissions have been made for company
-	corpBodyMultipleRequests, err := authCodeReqSvc.CheckMultipleCorporateBodySubmissions(companyNumber)
-	if corpBodyMultipleRequests {
-		log.InfoR(req, "Request already submitted for company number "+companyNumber)
-		return false, err
-	}
-	if err != nil {
-		return false, err
+// writeIdempotentAuthCodeRequest replays the response to the request which created or resumed the auth code
+// request with the idempotency key, returning false without writing a response if there is no such request.
+// The request is refused if its body differs from that of the original request.
+func writeIdempotentAuthCodeRequest(w http.ResponseWriter, req *http.Request, authCodeReqSvc *service.AuthCodeRequestService, requester *service.Requester, idempotencyKey, idempotencyHash string) bool {
+	authCodeRequest, resumed, responseType := authCodeReqSvc.GetIdempotentAuthCodeRequest(requester, idempotencyKey, idempotencyHash)
+
+	switch responseType {
+	case service.NotFound:
+		return false
+	case service.Success:
+		status := http.StatusCreated
+		if resumed {
+			status = http.StatusOK
+		}
+		utils.WriteJSONWithStatus(w, req, transformers.AuthCodeRequestResourceDaoToResponse(authCodeRequest), status)
+	case service.InvalidData:
+		utils.WriteErrorMessage(w, req, http.StatusBadRequest, "idempotency key can only be used by an authenticated user")
+	case service.Unprocessable:
+		utils.WriteErrorMessage(w, req, http.StatusUnprocessableEntity, "idempotency key has already been used with a different request body")
+	default:
+		utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking idempotency key")
 	}
 
-	// Check whether user has made too many requests
-	userExceededRequests, err := authCodeReqSvc.CheckMultipleUserSubmissions(email)
-	if userExceededRequests {
-		log.InfoR(req, "requests exceeded for user "+email)
-		return false, err
-	}
+	return true
+}
+
+// validateCorporateBody checks whether an auth code may be requested for the company by the user, returning
+// the reason it may not if so
+func validateCorporateBody(req *http.Request, authCodeReqSvc *service.AuthCodeRequestService, companyNumber, userID, email string) (*models.EligibilityFailureResponse, error) {
+
+	eligibilityFailure, err := authCodeReqSvc.ValidateCorporateBody(companyNumber, userID, email)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// Check whether company has made recent filings
-	hasFiledWithinPeriod, err := service.CheckCompanyFilingHistory(companyNumber)
-	if hasFiledWithinPeriod {
-		log.InfoR(req, "Recent filings found for company number "+companyNumber)
-		return false, err
-	}
-	if err != nil {
-		return false, err
+	if eligibilityFailure != nil {
+		log.InfoR(req, fmt.Sprintf("request not permitted for company number [%s]: %s", companyNumber, eligibilityFailure.Code))
 	}
 
-	return true, nil
+	return eligibilityFailure, nil
+}
+
+// writeEligibilityFailure refuses a request for an auth code with the reason it is not permitted, and when it
+// may be retried if that is known
+func writeEligibilityFailure(w http.ResponseWriter, req *http.Request, failure *models.EligibilityFailureResponse) {
+	if failure.RetryAfter != nil {
+		w.Header().Set("Retry-After", failure.RetryAfter.UTC().Format(http.TimeFormat))
+	}
+	utils.WriteJSONWithStatus(w, req, failure, http.StatusForbidden)
 }
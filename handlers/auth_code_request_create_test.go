@@ -8,7 +8,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/companieshouse/chs.go/authentication"
 	"github.com/companieshouse/emergency-auth-code-api/config"
@@ -36,6 +38,7 @@ var (
 	`
 	testBasePath = "http://test-path.gov"
 	testResource = testBasePath + "/company/87654321"
+	retryAfter   = time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
 )
 
 func serveCreateAuthCodeRequestHandler(
@@ -120,8 +123,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -151,8 +154,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -161,7 +164,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 				mockReqService,
 			)
 			So(res.Code, ShouldEqual, http.StatusForbidden)
-			So(res.Body.String(), ShouldStartWith, `{"message":"request not permitted for corporate body"}`)
+			So(res.Header().Get("Retry-After"), ShouldBeEmpty)
+			So(res.Body.String(), ShouldStartWith, `{"code":"company-filed-recently","message":"the company has filed electronically within the eligibility period"}`)
 		})
 
 		Convey("error calling oracle API for officer", func() {
@@ -179,8 +183,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -207,8 +211,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -235,8 +239,9 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -255,8 +260,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			// stub the oracle query lookup
 			responder := httpmock.NewStringResponder(http.StatusNotFound, "")
@@ -282,8 +287,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			// stub the oracle query lookup
 			responder := httpmock.NewStringResponder(http.StatusInternalServerError, "")
@@ -303,6 +308,66 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 		})
 
+		Convey("officer address limit exceeded", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			defer httpmock.Reset()
+
+			// stub the oracle query lookup
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"id":"12345678","usual_residential_address":{"id":"ura1"}}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/12345678", responder)
+
+			// stub the oracle query lookup for the filing history
+			responderFilingHistory := httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/efiling-status", responderFilingHistory)
+
+			// stub the DB lookup
+			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3}, nil)
+
+			res := serveCreateAuthCodeRequestHandler(
+				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
+				t,
+				&models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "12345678"},
+				mockReqService,
+			)
+			So(res.Code, ShouldEqual, http.StatusForbidden)
+			So(res.Header().Get("Retry-After"), ShouldNotBeEmpty)
+			So(res.Body.String(), ShouldStartWith, `{"code":"address-limit-exceeded","message":"the maximum number of auth code requests has been reached for this officer's address"`)
+		})
+
+		Convey("error checking officer submissions", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			defer httpmock.Reset()
+
+			// stub the oracle query lookup
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":3}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/12345678", responder)
+
+			// stub the oracle query lookup for the filing history
+			responderFilingHistory := httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/efiling-status", responderFilingHistory)
+
+			// stub the DB lookup
+			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			res := serveCreateAuthCodeRequestHandler(
+				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
+				t,
+				&models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "12345678"},
+				mockReqService,
+			)
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error checking officer submissions"}`)
+		})
+
 		Convey("successful Authcode Reminder", func() {
 			defer httpmock.Reset()
 			httpmock.RegisterResponder(http.MethodGet, testResource, httpmock.NewStringResponder(http.StatusOK, companyDetailsResponse))
@@ -317,8 +382,9 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 			mockReqService.EXPECT().InsertAuthCodeRequest(gomock.Any()).Return(nil)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			// stub the oracle query lookup for the filing history
 			responderFilingHistory := httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`)
@@ -354,8 +420,9 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 			mockReqService.EXPECT().InsertAuthCodeRequest(gomock.Any()).Return(nil)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -382,7 +449,7 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(true, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{retryAfter.Add(-72 * time.Hour)}}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -391,6 +458,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 				mockReqService,
 			)
 			So(res.Code, ShouldEqual, http.StatusForbidden)
+			So(res.Header().Get("Retry-After"), ShouldEqual, "Tue, 02 Jan 2024 15:04:05 GMT")
+			So(res.Body.String(), ShouldStartWith, `{"code":"company-recently-requested","message":"an auth code has recently been requested for this company","retry_after":"2024-01-02T15:04:05Z"}`)
 
 		})
 
@@ -404,7 +473,7 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, fmt.Errorf("error"))
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -428,8 +497,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(true, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3, SubmittedAt: []time.Time{retryAfter.Add(-time.Hour), retryAfter.Add(-2 * time.Hour), retryAfter.Add(-24 * time.Hour)}}, nil)
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -438,6 +507,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 				mockReqService,
 			)
 			So(res.Code, ShouldEqual, http.StatusForbidden)
+			So(res.Header().Get("Retry-After"), ShouldEqual, "Tue, 02 Jan 2024 15:04:05 GMT")
+			So(res.Body.String(), ShouldStartWith, `{"code":"user-limit-exceeded","message":"the maximum number of auth code requests has been reached for this user","retry_after":"2024-01-02T15:04:05Z"}`)
 
 		})
 
@@ -451,8 +522,8 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 
 			// stub the DB lookup
 			mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockReqService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, nil)
-			mockReqService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, fmt.Errorf("error"))
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
 
 			res := serveCreateAuthCodeRequestHandler(
 				context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}),
@@ -465,3 +536,222 @@ func TestUnitCreateAuthCodeRequestHandler(t *testing.T) {
 		})
 	})
 }
+
+func serveUserCreateAuthCodeRequestHandler(t *testing.T, idempotencyKey string, reqBody *models.AuthCodeRequest, daoReqSvc dao.AuthcodeRequestDAOService) *httptest.ResponseRecorder {
+	authCodeReqSvc := &service.AuthCodeRequestService{
+		Config: &config.Config{APIBaseURL: testBasePath},
+		DAO:    daoReqSvc,
+	}
+
+	b, err := json.Marshal(reqBody)
+	if err != nil {
+		t.Fatal("failed to marshal request body")
+	}
+
+	ctx := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: "user1", Email: "test@test.com"})
+	ctx = context.WithValue(ctx, httpsession.ContextKeySession, &session.Session{})
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(b)).WithContext(ctx)
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	res := httptest.NewRecorder()
+
+	CreateAuthCodeRequest(authCodeReqSvc).ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitCreateAuthCodeRequestHandlerIdempotencyKey(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Create auth code request with idempotency key", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+
+		reqBody := &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "12345678"}
+		idempotencyHash, err := service.IdempotencyHash(reqBody)
+		So(err, ShouldBeNil)
+
+		existing := &models.AuthCodeRequestResourceDao{
+			ID: "existing123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber:   "87654321",
+				CompanyName:     "Test Company",
+				Status:          models.StatusPending,
+				IdempotencyKeys: []models.IdempotencyKeyDao{{Key: "key1", Hash: idempotencyHash}},
+				Links:           models.AuthCodeResourceLinksDao{Self: "/auth-code-requests/existing123"},
+			},
+		}
+
+		Convey("idempotency key too long", func() {
+			res := serveUserCreateAuthCodeRequestHandler(t, strings.Repeat("k", 256), reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("error getting request by idempotency key", func() {
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(nil, fmt.Errorf("error"))
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("original response replayed for same key and body", func() {
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(existing, nil)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusCreated)
+			So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/existing123")
+		})
+
+		Convey("original response replayed for key used to resume request", func() {
+			existing.Data.IdempotencyKeys[0].Resumed = true
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(existing, nil)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/existing123")
+		})
+
+		Convey("same key with different body", func() {
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(existing, nil)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "key1", &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "87654321"}, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusUnprocessableEntity)
+			So(res.Body.String(), ShouldContainSubstring, "idempotency key has already been used with a different request body")
+		})
+
+		Convey("new key", func() {
+			defer httpmock.Reset()
+			httpmock.RegisterResponder(http.MethodGet, testResource, httpmock.NewStringResponder(http.StatusOK, companyDetailsResponse))
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/12345678", httpmock.NewStringResponder(http.StatusOK, `{"total_results":3}`))
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/efiling-status", httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(nil, nil)
+			mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(nil, nil)
+
+			Convey("key and hash stored with request", func() {
+				var inserted *models.AuthCodeRequestResourceDao
+				mockReqService.EXPECT().InsertAuthCodeRequest(gomock.Any()).DoAndReturn(func(dao *models.AuthCodeRequestResourceDao) error {
+					inserted = dao
+					return nil
+				})
+
+				res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+				So(res.Code, ShouldEqual, http.StatusCreated)
+				So(inserted.Data.IdempotencyKeys, ShouldResemble, []models.IdempotencyKeyDao{{Key: "key1", Hash: idempotencyHash}})
+			})
+
+			Convey("key used by concurrent request", func() {
+				mockReqService.EXPECT().InsertAuthCodeRequest(gomock.Any()).Return(dao.ErrDuplicateIdempotencyKey)
+				mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(existing, nil)
+
+				res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+				So(res.Code, ShouldEqual, http.StatusCreated)
+				So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/existing123")
+			})
+		})
+	})
+}
+
+func TestUnitCreateAuthCodeRequestHandlerResumePending(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Create auth code request when user has a pending request for the company", t, func() {
+		httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/12345678", httpmock.NewStringResponder(http.StatusOK, `{"id":"12345678","forename":"New","surname":"Officer","usual_residential_address":{"id":"ura1"}}`))
+		httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/efiling-status", httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+		mockReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+		mockReqService.EXPECT().CountOfficerSubmissions("12345678", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+		mockReqService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+
+		pending := &models.AuthCodeRequestResourceDao{
+			ID: "pending123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: "87654321",
+				Status:        models.StatusPending,
+				OfficerID:     "12345678",
+				Etag:          "etag1",
+				Links:         models.AuthCodeResourceLinksDao{Self: "/auth-code-requests/pending123"},
+			},
+		}
+
+		reqBody := &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "12345678"}
+
+		Convey("pending request resumed", func() {
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(pending, nil)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/pending123")
+		})
+
+		Convey("pending request resumed with different officer", func() {
+			pending.Data.OfficerID = "other"
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(pending, nil)
+			mockReqService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", nil).Return(nil)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusOK)
+			responseBody := decodeResponse(res, t)
+			So(responseBody.OfficerID, ShouldEqual, "12345678")
+			So(responseBody.OfficerName, ShouldEqual, "New Officer")
+		})
+
+		Convey("pending request modified while being resumed", func() {
+			pending.Data.OfficerID = "other"
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(pending, nil)
+			mockReqService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", nil).Return(dao.ErrEtagMismatch)
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("pending request resumed with idempotency key", func() {
+			idempotencyHash, err := service.IdempotencyHash(reqBody)
+			So(err, ShouldBeNil)
+			idempotencyKey := &models.IdempotencyKeyDao{Key: "key1", Hash: idempotencyHash, Resumed: true}
+
+			mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(nil, nil)
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(pending, nil)
+
+			Convey("key stored with request", func() {
+				mockReqService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", idempotencyKey).Return(nil)
+
+				res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+				So(res.Code, ShouldEqual, http.StatusOK)
+				So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/pending123")
+			})
+
+			Convey("key used by concurrent request", func() {
+				resumed := *pending
+				resumed.Data.IdempotencyKeys = []models.IdempotencyKeyDao{*idempotencyKey}
+				mockReqService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", idempotencyKey).Return(dao.ErrDuplicateIdempotencyKey)
+				mockReqService.EXPECT().GetAuthCodeRequestByIdempotencyKey("user1", "key1").Return(&resumed, nil)
+
+				res := serveUserCreateAuthCodeRequestHandler(t, "key1", reqBody, mockReqService)
+				So(res.Code, ShouldEqual, http.StatusOK)
+				So(decodeResponse(res, t).Links.Self, ShouldEqual, "/auth-code-requests/pending123")
+			})
+		})
+
+		Convey("error getting pending request", func() {
+			mockReqService.EXPECT().GetLatestPendingAuthCodeRequest("user1", "87654321", gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			res := serveUserCreateAuthCodeRequestHandler(t, "", reqBody, mockReqService)
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+	})
+}
@@ -24,8 +24,18 @@ func GetAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.Han
 			return
 		}
 
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
 		// Get the auth code request from the ID in request
-		authCodeRequest, responseType := authCodeReqSvc.GetAuthCodeRequest(authCodeRequestID)
+		authCodeRequest, responseType := authCodeReqSvc.GetAuthCodeRequest(authCodeRequestID, requester)
+		if responseType == http.StatusGone {
+			utils.WriteErrorMessage(w, req, http.StatusGone, "auth code request has expired")
+			return
+		}
 		if responseType != http.StatusOK {
 			w.WriteHeader(responseType)
 			return
@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/companieshouse/chs.go/authentication"
 	"github.com/companieshouse/emergency-auth-code-api/dao"
 	"github.com/companieshouse/emergency-auth-code-api/mocks"
 	"github.com/companieshouse/emergency-auth-code-api/models"
@@ -18,15 +20,21 @@ import (
 
 var companyNumber = "12345678"
 var companyName = "testCompany"
+var ownerUserID = "owner123"
 
 var daoResponse = models.AuthCodeRequestResourceDao{
 	Data: models.AuthCodeRequestDataDao{
 		CompanyNumber: companyNumber,
 		CompanyName:   companyName,
+		CreatedBy:     models.CreatedByDao{ID: ownerUserID},
 	},
 }
 
 func serveGetAuthCodeRequest(daoReqSvc dao.AuthcodeRequestDAOService, hasAuthCodeRequestID bool) *httptest.ResponseRecorder {
+	return serveGetAuthCodeRequestAsUser(daoReqSvc, hasAuthCodeRequestID, ownerUserID, nil)
+}
+
+func serveGetAuthCodeRequestAsUser(daoReqSvc dao.AuthcodeRequestDAOService, hasAuthCodeRequestID bool, userID string, headers map[string]string) *httptest.ResponseRecorder {
 
 	authCodeReqSvc := &service.AuthCodeRequestService{}
 
@@ -35,7 +43,11 @@ func serveGetAuthCodeRequest(daoReqSvc dao.AuthcodeRequestDAOService, hasAuthCod
 	}
 
 	h := GetAuthCodeRequest(authCodeReqSvc)
-	req := httptest.NewRequest(http.MethodPost, "/test", nil)
+	ctx := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: userID})
+	req := httptest.NewRequest(http.MethodGet, "/test", nil).WithContext(ctx)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 	if hasAuthCodeRequestID {
 		req = mux.SetURLVars(req, map[string]string{"auth_code_request_id": companyNumber})
 	}
@@ -82,6 +94,22 @@ func TestUnitGetAuthCodeRequestHandler(t *testing.T) {
 		So(res.Code, ShouldEqual, http.StatusNotFound)
 	})
 
+	Convey("GetAuthCodeRequest returns gone for an expired authcode request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		expired := daoResponse
+		expired.Data.Status = models.StatusExpired
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockDaoService.EXPECT().GetAuthCodeRequest(companyNumber).Return(&expired, nil)
+
+		res := serveGetAuthCodeRequest(mockDaoService, true)
+
+		So(res.Code, ShouldEqual, http.StatusGone)
+		So(res.Body.String(), ShouldStartWith, `{"message":"auth code request has expired"}`)
+	})
+
 	Convey("GetAuthCodeRequest successfully returns existing authcode request", t, func() {
 		mockCtrl := gomock.NewController(t)
 		defer mockCtrl.Finish()
@@ -96,4 +124,55 @@ func TestUnitGetAuthCodeRequestHandler(t *testing.T) {
 		So(responseBody.CompanyName, ShouldEqual, companyName)
 		So(responseBody.CompanyNumber, ShouldEqual, companyNumber)
 	})
+
+	Convey("GetAuthCodeRequest returns the stored etag as the ETag", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		etagResponse := daoResponse
+		etagResponse.Data.Etag = "etag1"
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockDaoService.EXPECT().GetAuthCodeRequest(companyNumber).Return(&etagResponse, nil).Times(2)
+
+		res := serveGetAuthCodeRequest(mockDaoService, true)
+
+		So(res.Code, ShouldEqual, http.StatusOK)
+		So(res.Header().Get("ETag"), ShouldEqual, `"etag1"`)
+
+		Convey("and an unmodified request is not returned again", func() {
+			res := serveGetAuthCodeRequestAsUser(mockDaoService, true, ownerUserID, map[string]string{"If-None-Match": `"etag1"`})
+
+			So(res.Code, ShouldEqual, http.StatusNotModified)
+			So(res.Body.String(), ShouldBeEmpty)
+		})
+	})
+
+	Convey("GetAuthCodeRequest returns not found for a request created by another user", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockDaoService.EXPECT().GetAuthCodeRequest(companyNumber).Return(&daoResponse, nil)
+
+		res := serveGetAuthCodeRequestAsUser(mockDaoService, true, "otherUser", nil)
+
+		So(res.Code, ShouldEqual, http.StatusNotFound)
+	})
+
+	Convey("GetAuthCodeRequest returns another user's request to an elevated API key", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockDaoService.EXPECT().GetAuthCodeRequest(companyNumber).Return(&daoResponse, nil)
+
+		headers := map[string]string{
+			"ERIC-Identity-Type":        authentication.APIKeyIdentityType,
+			"ERIC-Authorised-Key-Roles": "*",
+		}
+		res := serveGetAuthCodeRequestAsUser(mockDaoService, true, "supportUser", headers)
+
+		So(res.Code, ShouldEqual, http.StatusOK)
+	})
 }
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// RecordLetterEvent records a stage reached by the letter for a submitted auth code request, as reported
+// by the AuthCode API once it has been sent the letter
+func RecordLetterEvent(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		var eventRequest models.LetterEventRequest
+		if err := json.NewDecoder(req.Body).Decode(&eventRequest); err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		authCodeRequestID := mux.Vars(req)["auth_code_request_id"]
+		if authCodeRequestID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "auth code request ID missing from request")
+			return
+		}
+
+		if !models.LetterEventType(eventRequest.Type).IsValid() {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("invalid type [%s]", eventRequest.Type))
+			return
+		}
+
+		switch authCodeReqSvc.RecordLetterEvent(authCodeRequestID, &eventRequest, requester) {
+		case service.Success:
+		case service.NotFound:
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+			return
+		case service.Conflict:
+			utils.WriteErrorMessage(w, req, http.StatusConflict, "auth code request has not been submitted or its status has changed")
+			return
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error recording letter event")
+			return
+		}
+
+		log.InfoR(req, "letter event recorded", log.Data{"auth_code_request_id": authCodeRequestID, "type": eventRequest.Type})
+
+		writeAdminAuthCodeRequest(w, req, authCodeReqSvc, authCodeRequestID)
+	})
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRecordLetterEventHandler(t *testing.T) {
+	Convey("Record letter event", t, func() {
+		apiKeyContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		h := RecordLetterEvent(&service.AuthCodeRequestService{DAO: mockDaoReqService})
+
+		dispatched := &models.AuthCodeRequestResourceDao{
+			ID:   "123",
+			Data: models.AuthCodeRequestDataDao{Status: models.StatusDispatched},
+		}
+
+		Convey("invalid body", func() {
+			res := serveReviewHandler(apiKeyContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"failed to read request body"}`)
+		})
+
+		Convey("invalid event type", func() {
+			res := serveReviewHandler(apiKeyContext, h, `{"type":"lost"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid type [lost]"}`)
+		})
+
+		Convey("auth code request not found", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, nil)
+
+			res := serveReviewHandler(apiKeyContext, h, `{"type":"printed"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("auth code request not submitted", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&models.AuthCodeRequestResourceDao{
+				ID:   "123",
+				Data: models.AuthCodeRequestDataDao{Status: models.StatusPending},
+			}, nil)
+
+			res := serveReviewHandler(apiKeyContext, h, `{"type":"printed"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusConflict)
+		})
+
+		Convey("letter event recorded", func() {
+			printed := &models.AuthCodeRequestResourceDao{
+				ID: "123",
+				Data: models.AuthCodeRequestDataDao{
+					Status:       models.StatusPrinted,
+					LetterEvents: []models.LetterEventDao{{Type: models.LetterEventPrinted, ReportedBy: models.ActorDao{ID: testReviewerID}}},
+				},
+			}
+			gomock.InOrder(
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(dispatched, nil),
+				mockDaoReqService.EXPECT().RecordLetterEvent("123", models.StatusDispatched, models.StatusPrinted, gomock.Any()).Return(true, nil),
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(printed, nil),
+			)
+
+			res := serveReviewHandler(apiKeyContext, h, `{"type":"printed"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"status":"printed"`)
+			So(res.Body.String(), ShouldContainSubstring, `"letter_events":[{"type":"printed"`)
+		})
+	})
+}
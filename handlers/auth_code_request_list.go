@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+const (
+	defaultItemsPerPage = 15
+	maxItemsPerPage     = 100
+)
+
+// ListAuthCodeRequests returns a page of the auth code requests created by the authenticated user
+func ListAuthCodeRequests(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		requester, err := getRequester(req)
+		if err != nil || requester.UserID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		startIndex, itemsPerPage, err := getPagination(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		filter := models.AuthCodeRequestFilter{
+			Status:        models.RequestStatus(req.FormValue("status")),
+			CompanyNumber: req.FormValue("company_number"),
+		}
+		if filter.Status != "" && !filter.Status.IsValid() {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("invalid status [%s]", filter.Status))
+			return
+		}
+
+		authCodeRequests, responseType := authCodeReqSvc.ListAuthCodeRequests(requester, filter, startIndex, itemsPerPage)
+		if responseType != service.Success {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error listing auth code requests")
+			return
+		}
+
+		utils.WriteJSON(w, req, authCodeRequests)
+	})
+}
+
+// getPagination returns the start index and items per page requested in the query string, applying
+// the defaults when they are not supplied
+func getPagination(req *http.Request) (int, int, error) {
+	startIndex := 0
+	if value := req.FormValue("start_index"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid start_index [%s]", value)
+		}
+		startIndex = parsed
+	}
+
+	itemsPerPage, err := getItemsPerPage(req)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return startIndex, itemsPerPage, nil
+}
+
+// getItemsPerPage returns the items per page requested in the query string, applying the default when it
+// is not supplied
+func getItemsPerPage(req *http.Request) (int, error) {
+	itemsPerPage := defaultItemsPerPage
+	if value := req.FormValue("items_per_page"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxItemsPerPage {
+			return 0, fmt.Errorf("invalid items_per_page [%s], must be between 1 and %d", value, maxItemsPerPage)
+		}
+		itemsPerPage = parsed
+	}
+
+	return itemsPerPage, nil
+}
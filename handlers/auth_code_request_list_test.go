@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveListAuthCodeRequests(ctx context.Context, daoReqSvc dao.AuthcodeRequestDAOService, query string) *httptest.ResponseRecorder {
+	authCodeReqSvc := &service.AuthCodeRequestService{
+		DAO: daoReqSvc,
+	}
+
+	h := ListAuthCodeRequests(authCodeReqSvc)
+	req := httptest.NewRequest(http.MethodGet, "/auth-code-requests"+query, nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitListAuthCodeRequestsHandler(t *testing.T) {
+	Convey("List auth code requests", t, func() {
+		userContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID})
+
+		Convey("user details not in context", func() {
+			res := serveListAuthCodeRequests(context.Background(), nil, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user details not in request context"}`)
+		})
+
+		Convey("invalid start index", func() {
+			res := serveListAuthCodeRequests(userContext, nil, "?start_index=-1")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid start_index [-1]"}`)
+		})
+
+		Convey("invalid items per page", func() {
+			res := serveListAuthCodeRequests(userContext, nil, "?items_per_page=101")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid items_per_page [101], must be between 1 and 100"}`)
+		})
+
+		Convey("invalid status", func() {
+			res := serveListAuthCodeRequests(userContext, nil, "?status=unknown")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid status [unknown]"}`)
+		})
+
+		Convey("error listing requests", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().ListAuthCodeRequests(gomock.Any(), 0, 15).Return(nil, int64(0), fmt.Errorf("error"))
+
+			res := serveListAuthCodeRequests(userContext, mockDaoReqService, "")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error listing auth code requests"}`)
+		})
+
+		Convey("list requests - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			expectedFilter := models.AuthCodeRequestFilter{
+				UserID:        testUserID,
+				Status:        models.StatusSubmitted,
+				CompanyNumber: "SC123456",
+			}
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{Data: models.AuthCodeRequestDataDao{CompanyNumber: "SC123456", Status: models.StatusSubmitted}},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().ListAuthCodeRequests(expectedFilter, 5, 5).Return(authCodeRequests, int64(6), nil)
+
+			res := serveListAuthCodeRequests(userContext, mockDaoReqService, "?status=submitted&company_number=sc123456&start_index=5&items_per_page=5")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"items_per_page":5,"start_index":5,"total_results":6`)
+			So(res.Body.String(), ShouldContainSubstring, `"company_number":"SC123456"`)
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/transformers"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// GetPendingAuthCodeRequest returns the authenticated user's pending auth code request for a company, if
+// there is one which may be resumed
+func GetPendingAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		requester, err := getRequester(req)
+		if err != nil || requester.UserID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		companyNumber := req.FormValue("company_number")
+		if companyNumber == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "company number missing from request")
+			return
+		}
+
+		authCodeRequest, responseType := authCodeReqSvc.GetPendingAuthCodeRequest(requester, companyNumber)
+		switch responseType {
+		case service.Success:
+			utils.WriteJSON(w, req, transformers.AuthCodeRequestResourceDaoToResponse(authCodeRequest))
+		case service.NotFound:
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "no pending auth code request for company")
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error getting pending auth code request")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveGetPendingAuthCodeRequest(ctx context.Context, daoReqSvc dao.AuthcodeRequestDAOService, query string) *httptest.ResponseRecorder {
+	authCodeReqSvc := &service.AuthCodeRequestService{
+		DAO: daoReqSvc,
+	}
+
+	h := GetPendingAuthCodeRequest(authCodeReqSvc)
+	req := httptest.NewRequest(http.MethodGet, "/auth-code-requests/pending"+query, nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitGetPendingAuthCodeRequestHandler(t *testing.T) {
+	Convey("Get pending auth code request", t, func() {
+		userContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID})
+
+		Convey("user details not in context", func() {
+			res := serveGetPendingAuthCodeRequest(context.Background(), nil, "?company_number=87654321")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user details not in request context"}`)
+		})
+
+		Convey("company number missing", func() {
+			res := serveGetPendingAuthCodeRequest(userContext, nil, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"company number missing from request"}`)
+		})
+
+		Convey("with DAO", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+
+			Convey("error getting pending request", func() {
+				mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, "87654321", gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+				res := serveGetPendingAuthCodeRequest(userContext, mockDaoService, "?company_number=87654321")
+				So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			})
+
+			Convey("no pending request", func() {
+				mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, "SC123456", gomock.Any()).Return(nil, nil)
+
+				res := serveGetPendingAuthCodeRequest(userContext, mockDaoService, "?company_number=sc123456")
+				So(res.Code, ShouldEqual, http.StatusNotFound)
+				So(res.Body.String(), ShouldStartWith, `{"message":"no pending auth code request for company"}`)
+			})
+
+			Convey("pending request returned", func() {
+				pending := &models.AuthCodeRequestResourceDao{
+					ID: "pending123",
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						Status:        models.StatusPending,
+					},
+				}
+				mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, "87654321", gomock.Any()).Return(pending, nil)
+
+				res := serveGetPendingAuthCodeRequest(userContext, mockDaoService, "?company_number=87654321")
+				So(res.Code, ShouldEqual, http.StatusOK)
+				So(res.Body.String(), ShouldContainSubstring, `"status":"pending"`)
+			})
+		})
+	})
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// ResendAuthCodeRequest sends the letter for a dispatched auth code request again, on behalf of a member of
+// support staff. The eligibility checks are not applied, and the submission limits only if the member of
+// staff has not asked to override them.
+func ResendAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		var resendRequest models.ResendRequest
+		if err := json.NewDecoder(req.Body).Decode(&resendRequest); err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		authCodeRequestID := mux.Vars(req)["auth_code_request_id"]
+		if authCodeRequestID == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "auth code request ID missing from request")
+			return
+		}
+
+		if !models.ResendReason(resendRequest.ReasonCode).IsValid() {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("invalid reason_code [%s]", resendRequest.ReasonCode))
+			return
+		}
+
+		if resendRequest.Operator == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "operator missing from request")
+			return
+		}
+
+		authCodeReqDao, responseType := authCodeReqSvc.GetResendableAuthCodeRequest(authCodeRequestID)
+		if !writeResendFailure(w, req, responseType) {
+			return
+		}
+
+		companyHasAuthCode, err := authCodeSvc.CheckAuthCodeExists(authCodeReqDao.Data.CompanyNumber)
+		if err != nil {
+			log.ErrorR(req, fmt.Errorf("error retrieving Auth Code from DB: %v", err))
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error retrieving Auth Code from DB")
+			return
+		}
+
+		limitFailure, responseType := authCodeReqSvc.ResendAuthCodeRequest(authCodeReqDao, &resendRequest, requester, companyHasAuthCode)
+		if responseType == service.NotFound {
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "officer not found")
+			return
+		}
+		if responseType == service.Forbidden {
+			writeEligibilityFailure(w, req, limitFailure)
+			return
+		}
+		if !writeResendFailure(w, req, responseType) {
+			return
+		}
+
+		log.InfoR(req, "authcode request letter resent", log.Data{"auth_code_request_id": authCodeRequestID, "operator": resendRequest.Operator})
+
+		writeAdminAuthCodeRequest(w, req, authCodeReqSvc, authCodeRequestID)
+	})
+}
+
+// writeResendFailure writes the error response for a resend which did not succeed, returning whether the
+// resend succeeded
+func writeResendFailure(w http.ResponseWriter, req *http.Request, responseType service.ResponseType) bool {
+	switch responseType {
+	case service.Success:
+		return true
+	case service.NotFound:
+		utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+	case service.Conflict:
+		utils.WriteErrorMessage(w, req, http.StatusConflict, "auth code request letter has not been dispatched")
+	default:
+		utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error resending authcode request")
+	}
+	return false
+}
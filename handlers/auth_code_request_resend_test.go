@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const resendBody = `{"reason_code":"lost-in-post","operator":"support.agent"}`
+
+func TestUnitResendAuthCodeRequestHandler(t *testing.T) {
+	Convey("Resend auth code request", t, func() {
+		cfg, _ := config.Get()
+		operatorContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		h := ResendAuthCodeRequest(
+			&service.AuthCodeService{DAO: mockDaoAuthcodeService, Config: cfg},
+			&service.AuthCodeRequestService{DAO: mockDaoReqService, Config: cfg},
+		)
+
+		submitted := &models.AuthCodeRequestResourceDao{
+			ID: "123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: "87654321",
+				OfficerID:     "987",
+				Status:        models.StatusPosted,
+				CreatedBy:     models.CreatedByDao{ID: testUserID, Email: "test@test.com"},
+			},
+		}
+
+		Convey("invalid body", func() {
+			res := serveReviewHandler(operatorContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"failed to read request body"}`)
+		})
+
+		Convey("invalid reason code", func() {
+			res := serveReviewHandler(operatorContext, h, `{"reason_code":"impatient","operator":"support.agent"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid reason_code [impatient]"}`)
+		})
+
+		Convey("operator missing", func() {
+			res := serveReviewHandler(operatorContext, h, `{"reason_code":"lost-in-post"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"operator missing from request"}`)
+		})
+
+		Convey("auth code request not found", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, nil)
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("auth code request not submitted", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}}, nil)
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request letter has not been dispatched"}`)
+		})
+
+		Convey("auth code request letter waiting in the outbox", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitted}}, nil)
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request letter has not been dispatched"}`)
+		})
+
+		Convey("error recording resend", func() {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(submitted, nil)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			expectSubmissionLimitsChecked(mockDaoReqService)
+			mockDaoReqService.EXPECT().ResendAuthCodeRequest("123", gomock.Any(), gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error resending authcode request"}`)
+		})
+
+		Convey("submission limit reached", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(submitted, nil)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			mockDaoReqService.EXPECT().CountCorporateBodySubmissions("87654321", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1}, nil)
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusForbidden)
+			So(res.Body.String(), ShouldStartWith, `{"code":"company-recently-requested"`)
+		})
+
+		Convey("submission limits overridden", func() {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			var resend models.ResendDao
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(submitted, nil).Times(2)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			mockDaoReqService.EXPECT().ResendAuthCodeRequest("123", gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ models.RequestStatus, r models.ResendDao, _ []models.OutboxItemDao) error {
+					resend = r
+					return nil
+				})
+
+			res := serveReviewHandler(operatorContext, h, `{"reason_code":"lost-in-post","operator":"support.agent","override_limits":true}`, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(resend.OverrideLimits, ShouldBeTrue)
+		})
+
+		Convey("resend request - success", func() {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			resent := &models.AuthCodeRequestResourceDao{
+				ID: "123",
+				Data: models.AuthCodeRequestDataDao{
+					Status: models.StatusSubmitted,
+					Resends: []models.ResendDao{
+						{ReasonCode: models.ResendReasonLostInPost, Operator: "support.agent", RequestedBy: models.ActorDao{ID: testReviewerID}},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(submitted, nil),
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(resent, nil),
+			)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			expectSubmissionLimitsChecked(mockDaoReqService)
+			mockDaoReqService.EXPECT().ResendAuthCodeRequest("123", gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+			res := serveReviewHandler(operatorContext, h, resendBody, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"resends":[{"reason_code":"lost-in-post","operator":"support.agent"`)
+		})
+	})
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// ListHeldAuthCodeRequests returns a page of the auth code requests held for manual review
+func ListHeldAuthCodeRequests(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		startIndex, itemsPerPage, err := getPagination(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		authCodeRequests, responseType := authCodeReqSvc.ListHeldAuthCodeRequests(startIndex, itemsPerPage)
+		if responseType != service.Success {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error listing held auth code requests")
+			return
+		}
+
+		utils.WriteJSON(w, req, authCodeRequests)
+	})
+}
+
+// ApproveAuthCodeRequest approves an auth code request held for manual review, submitting it so that its
+// letter is sent unless a submission limit has since been reached
+func ApproveAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		reviewer, reviewRequest, authCodeRequestID, ok := readReviewRequest(w, req)
+		if !ok {
+			return
+		}
+
+		authCodeReqDao, responseType := authCodeReqSvc.GetHeldAuthCodeRequest(authCodeRequestID)
+		if !writeReviewFailure(w, req, responseType) {
+			return
+		}
+
+		companyHasAuthCode, err := authCodeSvc.CheckAuthCodeExists(authCodeReqDao.Data.CompanyNumber)
+		if err != nil {
+			log.ErrorR(req, fmt.Errorf("error retrieving Auth Code from DB: %v", err))
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error retrieving Auth Code from DB")
+			return
+		}
+
+		limitFailure, responseType := authCodeReqSvc.ApproveAuthCodeRequest(authCodeReqDao, reviewer, reviewRequest.Reason, companyHasAuthCode)
+		if responseType == service.NotFound {
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "officer not found")
+			return
+		}
+		if responseType == service.Forbidden {
+			writeEligibilityFailure(w, req, limitFailure)
+			return
+		}
+		if !writeReviewFailure(w, req, responseType) {
+			return
+		}
+
+		log.InfoR(req, "held authcode request approved", log.Data{"auth_code_request_id": authCodeRequestID, "reviewed_by": reviewer.UserID})
+
+		writeAdminAuthCodeRequest(w, req, authCodeReqSvc, authCodeRequestID)
+	})
+}
+
+// RejectAuthCodeRequest rejects an auth code request held for manual review, so that its letter is never
+// sent. A reason for the rejection must be supplied.
+func RejectAuthCodeRequest(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		reviewer, reviewRequest, authCodeRequestID, ok := readReviewRequest(w, req)
+		if !ok {
+			return
+		}
+
+		if reviewRequest.Reason == "" {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "reason missing from request")
+			return
+		}
+
+		if !writeReviewFailure(w, req, authCodeReqSvc.RejectAuthCodeRequest(authCodeRequestID, reviewer, reviewRequest.Reason)) {
+			return
+		}
+
+		log.InfoR(req, "held authcode request rejected", log.Data{"auth_code_request_id": authCodeRequestID, "reviewed_by": reviewer.UserID})
+
+		writeAdminAuthCodeRequest(w, req, authCodeReqSvc, authCodeRequestID)
+	})
+}
+
+// readReviewRequest reads the reviewer, the optional review body and the auth code request ID from a
+// request to review a held auth code request, writing an error response and returning false if any
+// cannot be read
+func readReviewRequest(w http.ResponseWriter, req *http.Request) (*service.Requester, *models.ReviewRequest, string, bool) {
+	reviewer, err := getRequester(req)
+	if err != nil {
+		utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+		return nil, nil, "", false
+	}
+
+	var reviewRequest models.ReviewRequest
+	if err := json.NewDecoder(req.Body).Decode(&reviewRequest); err != nil && err != io.EOF {
+		utils.WriteErrorMessage(w, req, http.StatusBadRequest, "failed to read request body")
+		return nil, nil, "", false
+	}
+
+	authCodeRequestID := mux.Vars(req)["auth_code_request_id"]
+	if authCodeRequestID == "" {
+		utils.WriteErrorMessage(w, req, http.StatusBadRequest, "auth code request ID missing from request")
+		return nil, nil, "", false
+	}
+
+	return reviewer, &reviewRequest, authCodeRequestID, true
+}
+
+// writeReviewFailure writes the error response for a review which did not succeed, returning whether
+// the review succeeded
+func writeReviewFailure(w http.ResponseWriter, req *http.Request, responseType service.ResponseType) bool {
+	switch responseType {
+	case service.Success:
+		return true
+	case service.NotFound:
+		utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+	case service.Conflict:
+		utils.WriteErrorMessage(w, req, http.StatusConflict, "auth code request is not held for review")
+	default:
+		utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reviewing authcode request")
+	}
+	return false
+}
+
+// writeAdminAuthCodeRequest writes the administrator's view of the auth code request as the response
+func writeAdminAuthCodeRequest(w http.ResponseWriter, req *http.Request, authCodeReqSvc *service.AuthCodeRequestService, authCodeRequestID string) {
+	response, responseType := authCodeReqSvc.GetAdminAuthCodeRequest(authCodeRequestID)
+	if responseType != service.Success {
+		utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reading authcode request")
+		return
+	}
+
+	utils.WriteJSONWithStatus(w, req, response, http.StatusOK)
+}
@@ -0,0 +1,315 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/jarcoal/httpmock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testReviewerID = "reviewer-key"
+
+func serveReviewHandler(ctx context.Context, h http.Handler, body, authCodeReqID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)).WithContext(ctx)
+	if authCodeReqID != "" {
+		req = mux.SetURLVars(req, map[string]string{"auth_code_request_id": authCodeReqID})
+	}
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitListHeldAuthCodeRequestsHandler(t *testing.T) {
+	Convey("List held auth code requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		serve := func(daoReqSvc dao.AuthcodeRequestDAOService, query string) *httptest.ResponseRecorder {
+			h := ListHeldAuthCodeRequests(&service.AuthCodeRequestService{DAO: daoReqSvc})
+			req := httptest.NewRequest(http.MethodGet, "/admin/auth-code-requests/held"+query, nil)
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+			return res
+		}
+
+		Convey("invalid items per page", func() {
+			res := serve(nil, "?items_per_page=0")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("error listing requests", func() {
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().ListAuthCodeRequests(gomock.Any(), 0, 15).Return(nil, int64(0), fmt.Errorf("error"))
+
+			res := serve(mockDaoReqService, "")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error listing held auth code requests"}`)
+		})
+
+		Convey("list held requests - success", func() {
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{
+					ID: "123",
+					Data: models.AuthCodeRequestDataDao{
+						Status:      models.StatusHeld,
+						HoldReasons: []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed},
+					},
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().ListAuthCodeRequests(models.AuthCodeRequestFilter{Status: models.StatusHeld}, 10, 5).Return(authCodeRequests, int64(11), nil)
+
+			res := serve(mockDaoReqService, "?start_index=10&items_per_page=5")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"items_per_page":5,"start_index":10,"total_results":11`)
+			So(res.Body.String(), ShouldContainSubstring, `"id":"123"`)
+			So(res.Body.String(), ShouldContainSubstring, `"hold_reasons":["officer-recently-appointed"]`)
+		})
+	})
+}
+
+func TestUnitApproveAuthCodeRequestHandler(t *testing.T) {
+	Convey("Approve auth code request", t, func() {
+		cfg, _ := config.Get()
+		reviewerContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		h := ApproveAuthCodeRequest(
+			&service.AuthCodeService{DAO: mockDaoAuthcodeService, Config: cfg},
+			&service.AuthCodeRequestService{DAO: mockDaoReqService, Config: cfg},
+		)
+
+		held := &models.AuthCodeRequestResourceDao{
+			ID: "123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: "87654321",
+				OfficerID:     "987",
+				Status:        models.StatusHeld,
+				CreatedBy:     models.CreatedByDao{ID: testUserID, Email: "test@test.com"},
+			},
+		}
+
+		Convey("user details not in context", func() {
+			res := serveReviewHandler(context.Background(), h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user details not in request context"}`)
+		})
+
+		Convey("invalid body", func() {
+			res := serveReviewHandler(reviewerContext, h, "{", "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"failed to read request body"}`)
+		})
+
+		Convey("auth code request ID missing", func() {
+			res := serveReviewHandler(reviewerContext, h, "", "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request ID missing from request"}`)
+		})
+
+		Convey("auth code request not found", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, nil)
+
+			res := serveReviewHandler(reviewerContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("auth code request not held", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}}, nil)
+
+			res := serveReviewHandler(reviewerContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request is not held for review"}`)
+		})
+
+		Convey("error checking for existing auth code", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(held, nil)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, fmt.Errorf("error"))
+
+			res := serveReviewHandler(reviewerContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("submission limit reached", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(held, nil)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			mockDaoReqService.EXPECT().ReviewAuthCodeRequest("123", models.StatusSubmitting, gomock.Any()).Return(true, nil)
+			mockDaoReqService.EXPECT().CountCorporateBodySubmissions("87654321", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1}, nil)
+			mockDaoReqService.EXPECT().HoldAuthCodeRequest("123", gomock.Any()).Return(true, nil)
+
+			res := serveReviewHandler(reviewerContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusForbidden)
+			So(res.Body.String(), ShouldStartWith, `{"code":"company-recently-requested"`)
+		})
+
+		Convey("approve request - success", func() {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			approved := &models.AuthCodeRequestResourceDao{
+				ID: "123",
+				Data: models.AuthCodeRequestDataDao{
+					Status: models.StatusSubmitted,
+					Reviews: []models.ReviewDao{
+						{Decision: models.ReviewApproved, ReviewedBy: models.ActorDao{ID: testReviewerID}},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(held, nil),
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(approved, nil),
+			)
+			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode("87654321").Return(true, nil)
+			mockDaoReqService.EXPECT().ReviewAuthCodeRequest("123", models.StatusSubmitting, gomock.Any()).Return(true, nil)
+			expectSubmissionLimitsChecked(mockDaoReqService)
+			mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(nil)
+
+			res := serveReviewHandler(reviewerContext, h, `{"reason":"officer verified"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"status":"submitted"`)
+			So(res.Body.String(), ShouldContainSubstring, `"decision":"approved"`)
+		})
+	})
+}
+
+func TestUnitRejectAuthCodeRequestHandler(t *testing.T) {
+	Convey("Reject auth code request", t, func() {
+		reviewerContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		h := RejectAuthCodeRequest(&service.AuthCodeRequestService{DAO: mockDaoReqService})
+
+		Convey("reason missing", func() {
+			res := serveReviewHandler(reviewerContext, h, "", "123")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"reason missing from request"}`)
+		})
+
+		Convey("auth code request not found", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(nil, nil)
+
+			res := serveReviewHandler(reviewerContext, h, `{"reason":"fraud"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("error recording review", func() {
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld}}, nil)
+			mockDaoReqService.EXPECT().ReviewAuthCodeRequest("123", models.StatusRejected, gomock.Any()).Return(false, fmt.Errorf("error"))
+
+			res := serveReviewHandler(reviewerContext, h, `{"reason":"fraud"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error reviewing authcode request"}`)
+		})
+
+		Convey("reject request - success", func() {
+			rejected := &models.AuthCodeRequestResourceDao{
+				ID: "123",
+				Data: models.AuthCodeRequestDataDao{
+					Status: models.StatusRejected,
+					Reviews: []models.ReviewDao{
+						{Decision: models.ReviewRejected, Reason: "fraud", ReviewedBy: models.ActorDao{ID: testReviewerID}},
+					},
+				},
+			}
+
+			gomock.InOrder(
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(
+					&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld}}, nil),
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(rejected, nil),
+			)
+			mockDaoReqService.EXPECT().ReviewAuthCodeRequest("123", models.StatusRejected, gomock.Any()).Return(true, nil)
+
+			res := serveReviewHandler(reviewerContext, h, `{"reason":"fraud"}`, "123")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"status":"rejected"`)
+			So(res.Body.String(), ShouldContainSubstring, `"reviewed_by":{"id":"reviewer-key"}`)
+		})
+	})
+}
+
+func TestUnitRequireElevatedAPIKey(t *testing.T) {
+	Convey("Require elevated API key", t, func() {
+		h := requireElevatedAPIKey(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+		Convey("user is rejected", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("ERIC-Identity-Type", authentication.Oauth2IdentityType)
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+			So(res.Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("elevated API key is permitted", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("ERIC-Identity-Type", authentication.APIKeyIdentityType)
+			req.Header.Set("ERIC-Authorised-Key-Roles", "*")
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, req)
+			So(res.Code, ShouldEqual, http.StatusOK)
+		})
+	})
+}
+
+func TestUnitGetRequester(t *testing.T) {
+	Convey("Get requester", t, func() {
+		userContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID, Email: "test@test.com"})
+
+		Convey("user details missing", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			_, err := getRequester(req)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("user", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(userContext)
+			req.Header.Set("ERIC-Identity", testUserID)
+			req.Header.Set("ERIC-Identity-Type", authentication.Oauth2IdentityType)
+			requester, err := getRequester(req)
+			So(err, ShouldBeNil)
+			So(requester.UserID, ShouldEqual, testUserID)
+			So(requester.APIKey, ShouldBeFalse)
+			So(requester.APIKeyID, ShouldBeEmpty)
+		})
+
+		Convey("API key is identified by the key", func() {
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(userContext)
+			req.Header.Set("ERIC-Identity", "key123")
+			req.Header.Set("ERIC-Identity-Type", authentication.APIKeyIdentityType)
+			requester, err := getRequester(req)
+			So(err, ShouldBeNil)
+			So(requester.APIKey, ShouldBeTrue)
+			So(requester.APIKeyID, ShouldEqual, "key123")
+		})
+	})
+}
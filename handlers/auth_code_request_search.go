@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// SearchAuthCodeRequests returns a page of the auth code requests created by any user which match the
+// search criteria in the query string
+func SearchAuthCodeRequests(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		itemsPerPage, err := getItemsPerPage(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		search, err := getAuthCodeRequestSearch(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		authCodeRequests, responseType := authCodeReqSvc.SearchAuthCodeRequests(*search, itemsPerPage)
+		if responseType != service.Success {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error searching auth code requests")
+			return
+		}
+
+		utils.WriteJSON(w, req, authCodeRequests)
+	})
+}
+
+// getAuthCodeRequestSearch returns the search criteria supplied in the query string
+func getAuthCodeRequestSearch(req *http.Request) (*models.AuthCodeRequestSearch, error) {
+	search := &models.AuthCodeRequestSearch{
+		CompanyNumber: req.FormValue("company_number"),
+		UserEmail:     req.FormValue("user_email"),
+		OfficerID:     req.FormValue("officer_id"),
+		Status:        models.RequestStatus(req.FormValue("status")),
+	}
+	if search.Status != "" && !search.Status.IsValid() {
+		return nil, fmt.Errorf("invalid status [%s]", search.Status)
+	}
+
+	var err error
+	if search.CreatedFrom, err = getDateParam(req, "created_from", false); err != nil {
+		return nil, err
+	}
+	if search.CreatedTo, err = getDateParam(req, "created_to", true); err != nil {
+		return nil, err
+	}
+	if search.SubmittedFrom, err = getDateParam(req, "submitted_from", false); err != nil {
+		return nil, err
+	}
+	if search.SubmittedTo, err = getDateParam(req, "submitted_to", true); err != nil {
+		return nil, err
+	}
+
+	if value := req.FormValue("cursor"); value != "" {
+		if search.After, err = models.ParseAuthCodeRequestCursor(value); err != nil {
+			return nil, err
+		}
+	}
+
+	return search, nil
+}
+
+// getDateParam returns the date or date-time supplied in the named query string parameter, or nil if it is
+// not supplied. A date which ends a range is taken to include the whole of that day.
+func getDateParam(req *http.Request, name string, endOfRange bool) (*time.Time, error) {
+	value := req.FormValue(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s [%s], must be a date or date-time", name, value)
+	}
+	if endOfRange {
+		parsed = parsed.AddDate(0, 0, 1)
+	}
+
+	return &parsed, nil
+}
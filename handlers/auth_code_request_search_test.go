@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveSearchAuthCodeRequests(daoReqSvc dao.AuthcodeRequestDAOService, query string) *httptest.ResponseRecorder {
+	h := SearchAuthCodeRequests(&service.AuthCodeRequestService{DAO: daoReqSvc})
+	req := httptest.NewRequest(http.MethodGet, "/admin/auth-code-requests"+query, nil)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitSearchAuthCodeRequestsHandler(t *testing.T) {
+	Convey("Search auth code requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		Convey("invalid items per page", func() {
+			res := serveSearchAuthCodeRequests(nil, "?items_per_page=101")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid items_per_page [101], must be between 1 and 100"}`)
+		})
+
+		Convey("invalid status", func() {
+			res := serveSearchAuthCodeRequests(nil, "?status=unknown")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid status [unknown]"}`)
+		})
+
+		Convey("invalid date", func() {
+			res := serveSearchAuthCodeRequests(nil, "?submitted_from=yesterday")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid submitted_from [yesterday], must be a date or date-time"}`)
+		})
+
+		Convey("invalid cursor", func() {
+			res := serveSearchAuthCodeRequests(nil, "?cursor=abc")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid cursor [abc]"}`)
+		})
+
+		Convey("error searching requests", func() {
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().SearchAuthCodeRequests(gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			res := serveSearchAuthCodeRequests(mockDaoReqService, "")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error searching auth code requests"}`)
+		})
+
+		Convey("search requests - success", func() {
+			createdAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+			cursor := &models.AuthCodeRequestCursor{CreatedAt: createdAt, ID: "456"}
+			createdFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			createdTo := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+			submittedFrom := time.Date(2024, 1, 5, 9, 30, 0, 0, time.UTC)
+
+			expectedSearch := models.AuthCodeRequestSearch{
+				CompanyNumber: "SC123456",
+				UserEmail:     "test@test.com",
+				OfficerID:     "987",
+				Status:        models.StatusSubmitted,
+				CreatedFrom:   &createdFrom,
+				CreatedTo:     &createdTo,
+				SubmittedFrom: &submittedFrom,
+				After:         cursor,
+				Limit:         6,
+			}
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{CompanyNumber: "SC123456", Status: models.StatusSubmitted}},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().SearchAuthCodeRequests(expectedSearch).Return(authCodeRequests, nil)
+
+			res := serveSearchAuthCodeRequests(mockDaoReqService, "?company_number=sc123456&user_email=test@test.com&officer_id=987&status=submitted"+
+				"&created_from=2024-01-01&created_to=2024-01-31&submitted_from=2024-01-05T09:30:00Z&items_per_page=5&cursor="+cursor.String())
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldStartWith, `{"items_per_page":5,"items":[{"id":"123"`)
+			So(res.Body.String(), ShouldNotContainSubstring, `next_cursor`)
+		})
+	})
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 
-	"github.com/companieshouse/chs.go/authentication"
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/emergency-auth-code-api/models"
 	"github.com/companieshouse/emergency-auth-code-api/service"
@@ -13,8 +12,6 @@ import (
 	"github.com/gorilla/mux"
 )
 
-const submitted = "submitted"
-
 // UpdateAuthCodeRequest updates an auth code request for a specified auth-code-request ID
 func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
@@ -28,8 +25,8 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 			return
 		}
 
-		userDetails := req.Context().Value(authentication.ContextKeyUserDetails)
-		if userDetails == nil {
+		requester, err := getRequester(req)
+		if err != nil {
 			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
 			return
 		}
@@ -47,22 +44,57 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 			return
 		}
 
-		if request.OfficerID == "" && request.Status != submitted {
+		requestedStatus := models.RequestStatus(request.Status)
+
+		if request.OfficerID == "" && requestedStatus != models.StatusSubmitted {
 			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "no valid changes supplied")
 			return
 		}
 
-		authCodeReqDao, authCodeReqStatus := authCodeReqSvc.GetAuthCodeReqDao(authCodeRequestID, request.CompanyNumber)
+		authCodeReqDao, authCodeReqStatus := authCodeReqSvc.GetAuthCodeReqDao(authCodeRequestID, request.CompanyNumber, requester)
+		if authCodeReqStatus == service.NotFound {
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
+			return
+		}
 		if authCodeReqStatus != service.Success {
 			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reading auth code request")
 			return
 		}
 
-		if authCodeReqDao.Data.Status == submitted {
+		// Reject changes made to a version of the request other than the current one
+		if ifMatch := req.Header.Get("If-Match"); ifMatch != "" && !utils.EtagMatches(ifMatch, authCodeReqDao.Data.Etag) {
+			utils.WriteErrorMessage(w, req, http.StatusPreconditionFailed, "auth code request has been modified")
+			return
+		}
+
+		currentStatus := authCodeReqDao.Data.Status
+
+		if currentStatus.IsSubmitted() {
 			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "request already submitted")
 			return
 		}
 
+		if currentStatus == models.StatusSubmitting {
+			utils.WriteErrorMessage(w, req, http.StatusConflict, "request submission already in progress")
+			return
+		}
+
+		if currentStatus == models.StatusExpired {
+			utils.WriteErrorMessage(w, req, http.StatusGone, "auth code request has expired")
+			return
+		}
+
+		if currentStatus != models.StatusPending {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("request is %s and can no longer be updated", currentStatus))
+			return
+		}
+
+		// The only status change which can be requested is submission
+		if requestedStatus != "" && requestedStatus != currentStatus && requestedStatus != models.StatusSubmitted {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("invalid status transition from [%s] to [%s]", currentStatus, requestedStatus))
+			return
+		}
+
 		// Update officer details in Request if supplied
 		if request.OfficerID != "" {
 
@@ -84,6 +116,7 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 				authCodeReqDao,
 				authCodeRequestID,
 				officer,
+				requester,
 			)
 
 			if responseType != service.Success {
@@ -92,6 +125,10 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 					utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error updating officer details in authcode request")
 				case service.InvalidData:
 					utils.WriteErrorMessage(w, req, http.StatusBadRequest, "error updating officer details in authcode request")
+				case service.PreconditionFailed:
+					utils.WriteErrorMessage(w, req, http.StatusPreconditionFailed, "auth code request has been modified")
+				case service.NotFound:
+					utils.WriteErrorMessage(w, req, http.StatusNotFound, "auth code request not found")
 				default:
 					utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error updating officer details in authcode request")
 				}
@@ -101,7 +138,7 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 			log.InfoR(req, "officer details updated in authcode request", log.Data{"company_number": request.CompanyNumber})
 		}
 
-		if request.Status == submitted {
+		if requestedStatus == models.StatusSubmitted {
 
 			if authCodeReqDao.Data.OfficerID == "" {
 				utils.WriteErrorMessage(w, req, http.StatusBadRequest, "officer details not supplied")
@@ -115,41 +152,56 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 				return
 			}
 
-			responseType := authCodeReqSvc.SendAuthCodeRequest(
+			// Claim the request for submission, so that concurrent submissions cannot send a second letter and
+			// a request modified since it was read, and checked against If-Match, is not submitted
+			submissionResponseType := authCodeReqSvc.StartAuthCodeRequestSubmission(authCodeRequestID, authCodeReqDao.Data.Etag, requester)
+			if submissionResponseType == service.Conflict {
+				utils.WriteErrorMessage(w, req, http.StatusConflict, "request submission already in progress")
+				return
+			}
+			if submissionResponseType == service.PreconditionFailed {
+				utils.WriteErrorMessage(w, req, http.StatusPreconditionFailed, "auth code request has been modified")
+				return
+			}
+			if submissionResponseType != service.Success {
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error updating status")
+				return
+			}
+
+			// Mark the request as submitted, recording the letter and confirmation email for dispatch, or
+			// hold it for review, unless a submission limit has been reached
+			limitFailure, responseType := authCodeReqSvc.SendAuthCodeRequest(
 				authCodeReqDao,
 				request.CompanyNumber,
-				userDetails.(authentication.AuthUserDetails).Email,
+				requester.Email,
 				authCodeRequestID,
 				companyHasAuthCode,
+				requester,
 			)
 
-			if responseType == service.NotFound {
-				utils.WriteErrorMessage(w, req, http.StatusNotFound, "officer not found")
-				return
-			}
-
 			if responseType != service.Success {
-				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error sending queue item")
-				return
-			}
+				// Nothing has been recorded for dispatch, so allow the request to be submitted again
+				authCodeReqSvc.AbortAuthCodeRequestSubmission(authCodeRequestID, requester)
 
-			err = sendConfirmationEmail(userDetails.(authentication.AuthUserDetails).Email, req)
-			if err != nil {
-				log.ErrorR(req, err)
-			}
-
-			authCodeStatusResponseType := authCodeReqSvc.UpdateAuthCodeRequestStatusSubmitted(authCodeReqDao, authCodeRequestID, companyHasAuthCode)
+				if responseType == service.NotFound {
+					utils.WriteErrorMessage(w, req, http.StatusNotFound, "officer not found")
+					return
+				}
+				if responseType == service.Forbidden {
+					log.InfoR(req, fmt.Sprintf("submission not permitted for company number [%s]: %s", request.CompanyNumber, limitFailure.Code))
+					writeEligibilityFailure(w, req, limitFailure)
+					return
+				}
 
-			if authCodeStatusResponseType != service.Success {
-				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error updating status")
+				utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error submitting authcode request")
 				return
 			}
 
-			log.InfoR(req, "status updated in authcode request; queue item submitted.", log.Data{"company_number": request.CompanyNumber})
+			log.InfoR(req, "authcode request submitted; letter and confirmation email queued for dispatch unless held for review", log.Data{"company_number": request.CompanyNumber, "status": authCodeReqDao.Data.Status})
 
 		}
 
-		response, responseType := authCodeReqSvc.GetAuthCodeRequest(authCodeRequestID)
+		response, responseType := authCodeReqSvc.GetAuthCodeRequest(authCodeRequestID, requester)
 
 		if responseType != http.StatusOK {
 			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error reading authcode request")
@@ -160,16 +212,3 @@ func UpdateAuthCodeRequest(authCodeSvc *service.AuthCodeService, authCodeReqSvc
 
 	})
 }
-
-func sendConfirmationEmail(emailAddress string, r *http.Request) error {
-	// Send confirmation email
-	if err := service.SendEmail(emailAddress); err != nil {
-		return fmt.Errorf("error sending email to %s: %v", emailAddress, err)
-	}
-
-	log.InfoR(r, "confirmation email sent to customer", log.Data{
-		"email_address": emailAddress,
-	})
-
-	return nil
-}
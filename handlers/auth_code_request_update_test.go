@@ -33,6 +33,19 @@ func serveUpdateAuthCodeRequestHandler(
 	daoReqSvc dao.AuthcodeRequestDAOService,
 	cfg *config.Config) *httptest.ResponseRecorder {
 
+	return serveUpdateAuthCodeRequestHandlerWithHeaders(ctx, t, reqBody, authCodeReqID, daoSvc, daoReqSvc, cfg, nil)
+}
+
+func serveUpdateAuthCodeRequestHandlerWithHeaders(
+	ctx context.Context,
+	t *testing.T,
+	reqBody *models.AuthCodeRequest,
+	authCodeReqID string,
+	daoSvc dao.AuthcodeDAOService,
+	daoReqSvc dao.AuthcodeRequestDAOService,
+	cfg *config.Config,
+	headers map[string]string) *httptest.ResponseRecorder {
+
 	authCodeSvc := &service.AuthCodeService{
 		Config: cfg,
 	}
@@ -60,6 +73,9 @@ func serveUpdateAuthCodeRequestHandler(
 
 	h := UpdateAuthCodeRequest(authCodeSvc, authCodeReqSvc)
 	req := httptest.NewRequest(http.MethodPost, "/", body).WithContext(ctx)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
 
 	if authCodeReqID != "" {
 		req = mux.SetURLVars(req, map[string]string{"auth_code_request_id": authCodeReqID})
@@ -72,6 +88,16 @@ func serveUpdateAuthCodeRequestHandler(
 	return res
 }
 
+const testUserID = "user123"
+
+// expectSubmissionLimitsChecked expects the company, user and officer submission limits to be checked when a
+// request is submitted, none of which have been reached
+func expectSubmissionLimitsChecked(mockDaoReqService *mocks.MockAuthcodeRequestDAOService) {
+	mockDaoReqService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+	mockDaoReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+	mockDaoReqService.EXPECT().CountOfficerSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+}
+
 // Mock function for successful preparing and sending of kafka message
 func mockSendEmailKafkaMessage(emailAddress string) error {
 	return nil
@@ -97,19 +123,19 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 		})
 
 		Convey("authcode request ID missing from request", func() {
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{}, "", nil, nil, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{}, "", nil, nil, cfg)
 			So(res.Code, ShouldEqual, http.StatusBadRequest)
 			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request ID missing from request"}`)
 		})
 
 		Convey("company number missing from request", func() {
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{}, "123", nil, nil, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{}, "123", nil, nil, cfg)
 			So(res.Code, ShouldEqual, http.StatusBadRequest)
 			So(res.Body.String(), ShouldStartWith, `{"message":"company number missing from request"}`)
 		})
 
 		Convey("no valid changes", func() {
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321"}, "123", nil, nil, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321"}, "123", nil, nil, cfg)
 			So(res.Code, ShouldEqual, http.StatusBadRequest)
 			So(res.Body.String(), ShouldStartWith, `{"message":"no valid changes supplied"}`)
 		})
@@ -121,18 +147,39 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
 					CompanyNumber: "87654321",
-					Status:        "submitted",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusSubmitted,
 				},
 			}
 
 			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, fmt.Errorf("error"))
 
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 			So(res.Code, ShouldEqual, http.StatusInternalServerError)
 			So(res.Body.String(), ShouldStartWith, `{"message":"error reading auth code request"}`)
 		})
 
+		Convey("request created by another user", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: "otherUser"},
+					Status:        models.StatusPending,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", nil, mockDaoReqService, cfg)
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request not found"}`)
+		})
+
 		Convey("request already submitted", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
@@ -140,18 +187,99 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
 					CompanyNumber: "87654321",
-					Status:        "submitted",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusSubmitted,
 				},
 			}
 
 			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
 
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 			So(res.Code, ShouldEqual, http.StatusBadRequest)
 			So(res.Body.String(), ShouldStartWith, `{"message":"request already submitted"}`)
 		})
 
+		Convey("request already dispatched", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusDispatched,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", nil, mockDaoReqService, cfg)
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"request already submitted"}`)
+		})
+
+		Convey("request no longer pending", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusCancelled,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"request is cancelled and can no longer be updated"}`)
+		})
+
+		Convey("request expired", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusExpired,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+			So(res.Code, ShouldEqual, http.StatusGone)
+			So(res.Body.String(), ShouldStartWith, `{"message":"auth code request has expired"}`)
+		})
+
+		Convey("invalid status transition", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusPending,
+				},
+			}
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432", Status: "dispatched"}, "123", nil, mockDaoReqService, cfg)
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid status transition from [pending] to [dispatched]"}`)
+		})
+
 		Convey("officer update", func() {
 
 			Convey("error calling Oracle API", func() {
@@ -161,13 +289,15 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusInternalServerError)
 				So(res.Body.String(), ShouldStartWith, `{"message":"there was a problem communicating with the Oracle API"}`)
 			})
@@ -179,6 +309,8 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 					},
 				}
 
@@ -190,7 +322,7 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				responder := httpmock.NewStringResponder(http.StatusNotFound, "")
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusNotFound)
 				So(res.Body.String(), ShouldStartWith, `{"message":"No officer found"}`)
 			})
@@ -202,23 +334,126 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
-				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any()).Return(fmt.Errorf("error"))
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
 
 				httpmock.Activate()
 				defer httpmock.DeactivateAndReset()
 				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusInternalServerError)
 				So(res.Body.String(), ShouldStartWith, `{"message":"error updating officer details in authcode request"}`)
 			})
 
+			Convey("stale etag supplied", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						Etag:          "etag2",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+				res := serveUpdateAuthCodeRequestHandlerWithHeaders(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg, map[string]string{"If-Match": `"etag1"`})
+				So(res.Code, ShouldEqual, http.StatusPreconditionFailed)
+				So(res.Body.String(), ShouldStartWith, `{"message":"auth code request has been modified"}`)
+			})
+
+			Convey("authcode request modified during officer update", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						Etag:          "etag1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(dao.ErrEtagMismatch)
+
+				httpmock.Activate()
+				defer httpmock.DeactivateAndReset()
+				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
+				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
+
+				res := serveUpdateAuthCodeRequestHandlerWithHeaders(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg, map[string]string{"If-Match": `"etag1"`})
+				So(res.Code, ShouldEqual, http.StatusPreconditionFailed)
+				So(res.Body.String(), ShouldStartWith, `{"message":"auth code request has been modified"}`)
+			})
+
+			Convey("authcode request deleted during officer update", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						Etag:          "etag1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(dao.ErrNotFound)
+
+				httpmock.Activate()
+				defer httpmock.DeactivateAndReset()
+				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
+				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
+
+				res := serveUpdateAuthCodeRequestHandlerWithHeaders(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg, map[string]string{"If-Match": `"etag1"`})
+				So(res.Code, ShouldEqual, http.StatusNotFound)
+				So(res.Body.String(), ShouldStartWith, `{"message":"auth code request not found"}`)
+			})
+
+			Convey("successful officer update with current etag", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						Etag:          "etag1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil).AnyTimes()
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(nil)
+
+				httpmock.Activate()
+				defer httpmock.DeactivateAndReset()
+				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
+				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
+
+				res := serveUpdateAuthCodeRequestHandlerWithHeaders(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg, map[string]string{"If-Match": `"etag1"`})
+				So(res.Code, ShouldEqual, http.StatusOK)
+			})
+
 			Convey("successful officer update", func() {
 				mockCtrl := gomock.NewController(t)
 				defer mockCtrl.Finish()
@@ -226,19 +461,21 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil).AnyTimes()
-				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any()).Return(nil)
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), gomock.Any()).Return(nil)
 
 				httpmock.Activate()
 				defer httpmock.DeactivateAndReset()
 				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/98765432", responder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", OfficerID: "98765432"}, "123", nil, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusOK)
 				So(res.Body.String(), ShouldContainSubstring, `"company_number":"87654321"`)
 			})
@@ -253,17 +490,80 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", nil, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", nil, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusBadRequest)
 				So(res.Body.String(), ShouldStartWith, `{"message":"officer details not supplied"}`)
 			})
 
+			Convey("officer limit exceeded", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						OfficerID:     "321",
+						OfficerUraID:  "ura1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				mockDaoReqService.EXPECT().CountCorporateBodySubmissions("87654321", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+				mockDaoReqService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+				mockDaoReqService.EXPECT().CountOfficerSubmissions("321", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3}, nil)
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusSubmitting, models.StatusPending).Return(true, nil)
+
+				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
+				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
+
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				So(res.Code, ShouldEqual, http.StatusForbidden)
+				So(res.Body.String(), ShouldStartWith, `{"code":"officer-limit-exceeded","message":"the maximum number of auth code requests has been reached for this officer"`)
+			})
+
+			Convey("error checking officer submissions", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						OfficerID:     "321",
+						OfficerUraID:  "ura1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				expectSubmissionLimitsChecked(mockDaoReqService)
+				mockDaoReqService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusSubmitting, models.StatusPending).Return(true, nil)
+
+				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
+				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
+
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				So(res.Code, ShouldEqual, http.StatusInternalServerError)
+				So(res.Body.String(), ShouldStartWith, `{"message":"error submitting authcode request"}`)
+			})
+
 			Convey("error retrieving authcode", func() {
 				mockCtrl := gomock.NewController(t)
 				defer mockCtrl.Finish()
@@ -271,6 +571,8 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 						OfficerID:     "321",
 					},
 				}
@@ -282,32 +584,37 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, fmt.Errorf("error"))
 				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusInternalServerError)
 				So(res.Body.String(), ShouldStartWith, `{"message":"error retrieving Auth Code from DB"}`)
 			})
 
-			Convey("error sending status queue item", func() {
+			Convey("error getting officer details for letter", func() {
 				mockCtrl := gomock.NewController(t)
 				defer mockCtrl.Finish()
 
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 						OfficerID:     "321",
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				expectSubmissionLimitsChecked(mockDaoReqService)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusSubmitting, models.StatusPending).Return(true, nil)
 
 				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
 				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusInternalServerError)
-				So(res.Body.String(), ShouldStartWith, `{"message":"error sending queue item"}`)
+				So(res.Body.String(), ShouldStartWith, `{"message":"error submitting authcode request"}`)
 			})
 
 			Convey("officer not found", func() {
@@ -317,12 +624,17 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 						OfficerID:     "321",
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				expectSubmissionLimitsChecked(mockDaoReqService)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusSubmitting, models.StatusPending).Return(true, nil)
 
 				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
@@ -333,12 +645,86 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				responder := httpmock.NewStringResponder(http.StatusNotFound, "")
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/321", responder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusNotFound)
 				So(res.Body.String(), ShouldStartWith, `{"message":"officer not found"}`)
 
 			})
 
+			Convey("request already being submitted", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						OfficerID:     "321",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(false, nil)
+
+				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
+				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
+
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				So(res.Code, ShouldEqual, http.StatusConflict)
+				So(res.Body.String(), ShouldStartWith, `{"message":"request submission already in progress"}`)
+			})
+
+			Convey("request modified since it was read", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
+						OfficerID:     "321",
+						Etag:          "etag1",
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", "etag1").Return(false, dao.ErrEtagMismatch)
+
+				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
+				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
+				mockDaoAuthcodeService.EXPECT().UpsertEmptyAuthCode(gomock.Any()).Return(nil)
+
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				So(res.Code, ShouldEqual, http.StatusPreconditionFailed)
+				So(res.Body.String(), ShouldStartWith, `{"message":"auth code request has been modified"}`)
+			})
+
+			Convey("request status is submitting", func() {
+				mockCtrl := gomock.NewController(t)
+				defer mockCtrl.Finish()
+
+				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
+					Data: models.AuthCodeRequestDataDao{
+						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						OfficerID:     "321",
+						Status:        models.StatusSubmitting,
+					},
+				}
+
+				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
+
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", nil, mockDaoReqService, cfg)
+				So(res.Code, ShouldEqual, http.StatusConflict)
+				So(res.Body.String(), ShouldStartWith, `{"message":"request submission already in progress"}`)
+			})
+
 			Convey("error updating status", func() {
 				mockCtrl := gomock.NewController(t)
 				defer mockCtrl.Finish()
@@ -346,13 +732,19 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 						OfficerID:     "321",
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil)
-				mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any()).Return(fmt.Errorf("error"))
+				expectSubmissionLimitsChecked(mockDaoReqService)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				mockDaoReqService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(1, nil)
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(fmt.Errorf("error"))
+				mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("123", models.StatusSubmitting, models.StatusPending).Return(true, nil)
 
 				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
@@ -362,12 +754,10 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				defer httpmock.DeactivateAndReset()
 				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/321", responder)
-				queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{}`)
-				httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusInternalServerError)
-				So(res.Body.String(), ShouldStartWith, `{"message":"error updating status"}`)
+				So(res.Body.String(), ShouldStartWith, `{"message":"error submitting authcode request"}`)
 			})
 
 			Convey("successful status update", func() {
@@ -377,13 +767,18 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 					Data: models.AuthCodeRequestDataDao{
 						CompanyNumber: "87654321",
+						CreatedBy:     models.CreatedByDao{ID: testUserID},
+						Status:        models.StatusPending,
 						OfficerID:     "321",
 					},
 				}
 
 				mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 				mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil).AnyTimes()
-				mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any()).Return(nil)
+				expectSubmissionLimitsChecked(mockDaoReqService)
+				mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+				mockDaoReqService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(1, nil)
+				mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(nil)
 
 				mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 				mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
@@ -393,12 +788,8 @@ func TestUnitUpdateAuthCodeRequestHandler(t *testing.T) {
 				defer httpmock.DeactivateAndReset()
 				responder := httpmock.NewStringResponder(http.StatusOK, `{"total_results":1}`)
 				httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/321", responder)
-				queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{}`)
-				httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
-				kafkaAPIResponder := httpmock.NewStringResponder(http.StatusOK, ``)
-				httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/send-email", cfg.ChsKafkaApiURL), kafkaAPIResponder)
 
-				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+				res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 				So(res.Code, ShouldEqual, http.StatusOK)
 				So(res.Body.String(), ShouldContainSubstring, `"company_number":"87654321"`)
 			})
@@ -424,13 +815,18 @@ func TestUnitUpdateAuthCodeRequestHandlerAuthCodeAPIAuthCodeFlow(t *testing.T) {
 			authCodeDaoResponse := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
 					CompanyNumber: "87654321",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+					Status:        models.StatusPending,
 					OfficerID:     "321",
 				},
 			}
 
 			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
 			mockDaoReqService.EXPECT().GetAuthCodeRequest("123").Return(&authCodeDaoResponse, nil).AnyTimes()
-			mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any()).Return(nil)
+			expectSubmissionLimitsChecked(mockDaoReqService)
+			mockDaoReqService.EXPECT().StartAuthCodeRequestSubmission("123", gomock.Any()).Return(true, nil)
+			mockDaoReqService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(1, nil)
+			mockDaoReqService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(nil)
 
 			mockDaoAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 			mockDaoAuthcodeService.EXPECT().CompanyHasAuthCode(gomock.Any()).Return(false, nil)
@@ -443,7 +839,7 @@ func TestUnitUpdateAuthCodeRequestHandlerAuthCodeAPIAuthCodeFlow(t *testing.T) {
 			queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{}`)
 			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf(cfg.AuthCodeAPILocalPath, authCodeDaoResponse.Data.CompanyNumber), queueAPIResponder)
 
-			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
+			res := serveUpdateAuthCodeRequestHandler(context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID}), t, &models.AuthCodeRequest{CompanyNumber: "87654321", Status: "submitted"}, "123", mockDaoAuthcodeService, mockDaoReqService, cfg)
 			So(res.Code, ShouldEqual, http.StatusOK)
 			So(res.Body.String(), ShouldContainSubstring, `"company_number":"87654321"`)
 		})
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// ExportDataSubject returns every auth code request created by the user identified in the query string,
+// along with its audit trail, to answer their subject access request
+func ExportDataSubject(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		subject, err := getDataSubject(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		export, responseType := authCodeReqSvc.ExportDataSubject(*subject)
+		if responseType != service.Success {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error exporting auth code requests")
+			return
+		}
+
+		utils.WriteJSON(w, req, export)
+	})
+}
+
+// EraseDataSubject erases every auth code request created by the user identified in the query string, to
+// answer their erasure request. The requests are deleted unless anonymisation is requested using the mode
+// parameter. Nothing is erased while any of the requests are in flight, and if a pending request is
+// submitted while the others are being erased, those erased are listed along with it in the conflict.
+func EraseDataSubject(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		subject, err := getDataSubject(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		mode := models.ErasureMode(req.FormValue("mode"))
+		if mode == "" {
+			mode = models.ErasureModeDelete
+		}
+		if !mode.IsValid() {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, fmt.Sprintf("invalid mode [%s]", mode))
+			return
+		}
+
+		erasure, responseType := authCodeReqSvc.EraseDataSubject(*subject, mode, requester)
+		switch responseType {
+		case service.Success:
+			utils.WriteJSON(w, req, erasure)
+		case service.Conflict:
+			utils.WriteJSONWithStatus(w, req, erasure, http.StatusConflict)
+		default:
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error erasing auth code requests")
+		}
+	})
+}
+
+// getDataSubject returns the user identified in the query string by their user ID or email
+func getDataSubject(req *http.Request) (*models.DataSubject, error) {
+	subject := &models.DataSubject{
+		UserID:    req.FormValue("user_id"),
+		UserEmail: req.FormValue("user_email"),
+	}
+	if subject.UserID == "" && subject.UserEmail == "" {
+		return nil, fmt.Errorf("user_id or user_email must be supplied")
+	}
+
+	return subject, nil
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveDataSubjectHandler(ctx context.Context, h http.Handler, method, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/admin/data-subject/auth-code-requests"+query, nil).WithContext(ctx)
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitExportDataSubjectHandler(t *testing.T) {
+	Convey("Export data subject", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		h := ExportDataSubject(&service.AuthCodeRequestService{DAO: mockDaoReqService, AuditDAO: mockAuditService})
+
+		Convey("user not identified", func() {
+			res := serveDataSubjectHandler(context.Background(), h, http.MethodGet, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user_id or user_email must be supplied"}`)
+		})
+
+		Convey("error listing auth code requests", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(models.DataSubject{UserEmail: "test@test.com"}).Return(nil, fmt.Errorf("error"))
+
+			res := serveDataSubjectHandler(context.Background(), h, http.MethodGet, "?user_email=test@test.com")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("auth code requests exported", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(models.DataSubject{UserID: testUserID}).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitted, CreatedBy: models.CreatedByDao{ID: testUserID}}},
+			}, nil)
+			mockAuditService.EXPECT().ListAuditEntries("123").Return([]models.AuditEntryDao{
+				{ID: "1", Action: models.AuditActionCreated, Actor: models.ActorDao{ID: testUserID}, ActorType: models.ActorTypeUser},
+			}, nil)
+
+			res := serveDataSubjectHandler(context.Background(), h, http.MethodGet, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"user_id":"`+testUserID+`"`)
+			So(res.Body.String(), ShouldContainSubstring, `"id":"123"`)
+			So(res.Body.String(), ShouldContainSubstring, `"audit_trail":[{"id":"1","action":"created"`)
+		})
+	})
+}
+
+func TestUnitEraseDataSubjectHandler(t *testing.T) {
+	Convey("Erase data subject", t, func() {
+		adminContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testReviewerID})
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		h := EraseDataSubject(&service.AuthCodeRequestService{DAO: mockDaoReqService, AuditDAO: mockAuditService})
+
+		subject := models.DataSubject{UserID: testUserID}
+
+		Convey("requester not in context", func() {
+			res := serveDataSubjectHandler(context.Background(), h, http.MethodDelete, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("user not identified", func() {
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("invalid mode", func() {
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID+"&mode=shred")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"invalid mode [shred]"}`)
+		})
+
+		Convey("request in flight", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusPosted}},
+				{ID: "456", Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld}},
+			}, nil)
+			mockDaoReqService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123", "456"}).Return(nil, nil)
+
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldContainSubstring, `"mode":"delete","auth_code_requests":[],"in_flight":["456"]`)
+		})
+
+		Convey("requests erased other than one submitted while being erased", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusPosted}},
+				{ID: "456", Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}},
+			}, nil)
+			mockDaoReqService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123", "456"}).Return(nil, nil)
+			mockDaoReqService.EXPECT().TransitionAuthCodeRequestStatus("456", models.StatusPending, models.StatusCancelled).Return(false, nil)
+			mockDaoReqService.EXPECT().DeleteAuthCodeRequests([]string{"123", "456"}).Return([]string{"123"}, nil)
+			mockAuditService.EXPECT().DeleteAuditEntries([]string{"123"}).Return(int64(2), nil)
+
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusConflict)
+			So(res.Body.String(), ShouldContainSubstring, `"mode":"delete","auth_code_requests":["123"],"in_flight":["456"]`)
+		})
+
+		Convey("error erasing", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(nil, fmt.Errorf("error"))
+
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+		Convey("requests deleted by default", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusDispatched}},
+			}, nil)
+			mockDaoReqService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123"}).Return(nil, nil)
+			mockDaoReqService.EXPECT().DeleteAuthCodeRequests([]string{"123"}).Return([]string{"123"}, nil)
+			mockAuditService.EXPECT().DeleteAuditEntries([]string{"123"}).Return(int64(2), nil)
+
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID)
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"mode":"delete","auth_code_requests":["123"]`)
+		})
+
+		Convey("requests anonymised", func() {
+			mockDaoReqService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusDispatched}},
+			}, nil)
+			mockDaoReqService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123"}).Return(nil, nil)
+			mockDaoReqService.EXPECT().AnonymiseAuthCodeRequests([]string{"123"}).Return([]string{"123"}, nil)
+			mockAuditService.EXPECT().AnonymiseAuditEntries([]string{"123"}).Return(int64(2), nil)
+
+			res := serveDataSubjectHandler(adminContext, h, http.MethodDelete, "?user_id="+testUserID+"&mode=anonymise")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"mode":"anonymise","auth_code_requests":["123"]`)
+		})
+	})
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+	"github.com/gorilla/mux"
+)
+
+// GetCompanyEligibility reports whether the authenticated user may request an auth code for a company, and
+// the outcome of each of the checks made, without creating an auth code request
+func GetCompanyEligibility(authCodeReqSvc *service.AuthCodeRequestService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		companyNumber, err := utils.GetValueFromVars(mux.Vars(req), "company_number")
+		if err != nil {
+			log.ErrorR(req, err)
+			utils.WriteResponseMessage(w, req, http.StatusBadRequest, "company number is not in request context")
+			return
+		}
+
+		requester, err := getRequester(req)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusBadRequest, "user details not in request context")
+			return
+		}
+
+		eligibility, err := authCodeReqSvc.CheckEligibility(companyNumber, requester.UserID, requester.Email)
+		if err != nil {
+			utils.WriteErrorMessage(w, req, http.StatusInternalServerError, "error checking corporate body")
+			return
+		}
+
+		utils.WriteJSON(w, req, eligibility)
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func serveGetCompanyEligibility(ctx context.Context, daoReqSvc dao.AuthcodeRequestDAOService, companyNumber string) *httptest.ResponseRecorder {
+	authCodeReqSvc := &service.AuthCodeRequestService{
+		DAO: daoReqSvc,
+	}
+
+	h := GetCompanyEligibility(authCodeReqSvc)
+	req := httptest.NewRequest(http.MethodGet, "/company/"+companyNumber+"/eligibility", nil).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{"company_number": companyNumber})
+	res := httptest.NewRecorder()
+
+	h.ServeHTTP(res, req)
+
+	return res
+}
+
+func TestUnitGetCompanyEligibility(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	Convey("Get company eligibility", t, func() {
+		userContext := context.WithValue(context.Background(), authentication.ContextKeyUserDetails, authentication.AuthUserDetails{ID: testUserID, Email: "test@test.com"})
+
+		Convey("company number missing from request", func() {
+			res := serveGetCompanyEligibility(userContext, nil, "")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"company number is not in request context"}`)
+		})
+
+		Convey("user details not in context", func() {
+			res := serveGetCompanyEligibility(context.Background(), nil, "87654321")
+			So(res.Code, ShouldEqual, http.StatusBadRequest)
+			So(res.Body.String(), ShouldStartWith, `{"message":"user details not in request context"}`)
+		})
+
+		Convey("error checking eligibility", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().CountCorporateBodySubmissions("87654321", gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			res := serveGetCompanyEligibility(userContext, mockDaoReqService, "87654321")
+			So(res.Code, ShouldEqual, http.StatusInternalServerError)
+			So(res.Body.String(), ShouldStartWith, `{"message":"error checking corporate body"}`)
+		})
+
+		Convey("each check is reported", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			defer httpmock.Reset()
+
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/SC123456/efiling-status",
+				httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/SC123456/eligible-officers",
+				httpmock.NewStringResponder(http.StatusOK, `{"total_results":3}`))
+
+			mockDaoReqService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoReqService.EXPECT().CountCorporateBodySubmissions("SC123456", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{retryAfter.Add(-72 * time.Hour)}}, nil)
+			mockDaoReqService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+
+			res := serveGetCompanyEligibility(userContext, mockDaoReqService, "sc123456")
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldStartWith, `{"company_number":"SC123456","eligible":false,"checks":[`+
+				`{"check":"company-submissions","eligible":false,"failure":{"code":"company-recently-requested","message":"an auth code has recently been requested for this company","retry_after":"2024-01-02T15:04:05Z"}},`+
+				`{"check":"user-submissions","eligible":true},`+
+				`{"check":"filing-history","eligible":true},`+
+				`{"check":"eligible-officers","eligible":true}]}`)
+		})
+	})
+}
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// GetShadowLetterCounts returns the number of letters compared with the shadow letter backend since the
+// service started, and of those the number for which the outcome differed from the letter backend
+func GetShadowLetterCounts(letterDispatcher service.LetterDispatcher) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+
+		shadowDispatcher, ok := letterDispatcher.(*service.ShadowLetterDispatcher)
+		if !ok {
+			utils.WriteErrorMessage(w, req, http.StatusNotFound, "no shadow letter backend configured")
+			return
+		}
+
+		counts := shadowDispatcher.Counts()
+		utils.WriteJSON(w, req, models.ShadowLetterCountsResponse{
+			Backend:       string(shadowDispatcher.Primary.Backend()),
+			ShadowBackend: string(shadowDispatcher.Shadow.Backend()),
+			Compared:      counts.Compared,
+			Mismatched:    counts.Mismatched,
+		})
+	})
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/service"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetShadowLetterCountsHandler(t *testing.T) {
+	Convey("Get shadow letter counts", t, func() {
+		serve := func(letterDispatcher service.LetterDispatcher) *httptest.ResponseRecorder {
+			res := httptest.NewRecorder()
+			GetShadowLetterCounts(letterDispatcher).ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+			return res
+		}
+
+		Convey("no shadow backend configured", func() {
+			res := serve(&service.QueueAPILetterDispatcher{})
+			So(res.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("counts returned", func() {
+			res := serve(&service.ShadowLetterDispatcher{Primary: &service.QueueAPILetterDispatcher{}, Shadow: &service.FileLetterDispatcher{}})
+			So(res.Code, ShouldEqual, http.StatusOK)
+			So(res.Body.String(), ShouldContainSubstring, `"backend":"queue-api"`)
+			So(res.Body.String(), ShouldContainSubstring, `"shadow_backend":"file"`)
+			So(res.Body.String(), ShouldContainSubstring, `"compared":0`)
+			So(res.Body.String(), ShouldContainSubstring, `"mismatched":0`)
+		})
+	})
+}
@@ -15,7 +15,7 @@ var authCodeService *service.AuthCodeService
 var authCodeRequestService *service.AuthCodeRequestService
 
 // Register defines the endpoints for the API
-func Register(mainRouter *mux.Router, cfg *config.Config, authCodeDao dao.AuthcodeDAOService, authCodeRequestDao dao.AuthcodeRequestDAOService) {
+func Register(mainRouter *mux.Router, cfg *config.Config, authCodeDao dao.AuthcodeDAOService, authCodeRequestDao dao.AuthcodeRequestDAOService, authCodeAuditDao dao.AuthcodeAuditDAOService, letterDispatcher service.LetterDispatcher) {
 
 	authCodeService = &service.AuthCodeService{
 		Config: cfg,
@@ -23,8 +23,10 @@ func Register(mainRouter *mux.Router, cfg *config.Config, authCodeDao dao.Authco
 	}
 
 	authCodeRequestService = &service.AuthCodeRequestService{
-		Config: cfg,
-		DAO:    authCodeRequestDao,
+		Config:   cfg,
+		DAO:      authCodeRequestDao,
+		AuditDAO: authCodeAuditDao,
+		Policy:   service.NewSubmissionPolicy(cfg),
 	}
 
 	userAuthInterceptor := &authentication.UserAuthenticationInterceptor{
@@ -34,6 +36,26 @@ func Register(mainRouter *mux.Router, cfg *config.Config, authCodeDao dao.Authco
 
 	mainRouter.HandleFunc("/emergency-auth-code-service/healthcheck", healthCheck).Methods(http.MethodGet).Name("healthcheck")
 
+	adminAuthInterceptor := &authentication.UserAuthenticationInterceptor{
+		AllowAPIKeyUser:                true,
+		RequireElevatedAPIKeyPrivilege: true,
+	}
+
+	// Create a router for administrative requests, which may only be made using elevated API keys. This is
+	// registered first so that its paths are not matched by the routes for users.
+	adminRouter := mainRouter.PathPrefix("/emergency-auth-code-service/admin").Subrouter()
+	adminRouter.Use(adminAuthInterceptor.UserAuthenticationIntercept, requireElevatedAPIKey)
+
+	adminRouter.Handle("/auth-code-requests", SearchAuthCodeRequests(authCodeRequestService)).Methods(http.MethodGet).Name("search-auth-code-requests")
+	adminRouter.Handle("/auth-code-requests/held", ListHeldAuthCodeRequests(authCodeRequestService)).Methods(http.MethodGet).Name("list-held-auth-code-requests")
+	adminRouter.Handle("/auth-code-requests/{auth_code_request_id}/approve", ApproveAuthCodeRequest(authCodeService, authCodeRequestService)).Methods(http.MethodPost).Name("approve-auth-code-request")
+	adminRouter.Handle("/auth-code-requests/{auth_code_request_id}/reject", RejectAuthCodeRequest(authCodeRequestService)).Methods(http.MethodPost).Name("reject-auth-code-request")
+	adminRouter.Handle("/auth-code-requests/{auth_code_request_id}/resend", ResendAuthCodeRequest(authCodeService, authCodeRequestService)).Methods(http.MethodPost).Name("resend-auth-code-request")
+	adminRouter.Handle("/auth-code-requests/{auth_code_request_id}/audit", GetAuthCodeRequestAudit(authCodeRequestService)).Methods(http.MethodGet).Name("get-auth-code-request-audit")
+	adminRouter.Handle("/data-subject/auth-code-requests", ExportDataSubject(authCodeRequestService)).Methods(http.MethodGet).Name("export-data-subject")
+	adminRouter.Handle("/data-subject/auth-code-requests", EraseDataSubject(authCodeRequestService)).Methods(http.MethodDelete).Name("erase-data-subject")
+	adminRouter.Handle("/letter-dispatch/shadow", GetShadowLetterCounts(letterDispatcher)).Methods(http.MethodGet).Name("get-shadow-letter-counts")
+
 	// Create a router that requires all users to be authenticated when making requests
 	appRouter := mainRouter.PathPrefix("/emergency-auth-code-service").Subrouter()
 	appRouter.Use(userAuthInterceptor.UserAuthenticationIntercept)
@@ -41,9 +63,16 @@ func Register(mainRouter *mux.Router, cfg *config.Config, authCodeDao dao.Authco
 	// Declare endpoint URIs
 	appRouter.HandleFunc("/company/{company_number}/officers", GetCompanyOfficers).Methods(http.MethodGet).Name("get-company-officers")
 	appRouter.HandleFunc("/company/{company_number}/officers/{officer_id}", GetCompanyOfficer).Methods(http.MethodGet).Name("get-company-officer")
+	appRouter.Handle("/company/{company_number}/eligibility", GetCompanyEligibility(authCodeRequestService)).Methods(http.MethodGet).Name("get-company-eligibility")
 	appRouter.Handle("/auth-code-requests", CreateAuthCodeRequest(authCodeRequestService)).Methods(http.MethodPost).Name("create-auth-code-request")
+	appRouter.Handle("/auth-code-requests", ListAuthCodeRequests(authCodeRequestService)).Methods(http.MethodGet).Name("list-auth-code-requests")
+	appRouter.Handle("/auth-code-requests/pending", GetPendingAuthCodeRequest(authCodeRequestService)).Methods(http.MethodGet).Name("get-pending-auth-code-request")
 	appRouter.Handle("/auth-code-requests/{auth_code_request_id}", GetAuthCodeRequest(authCodeRequestService)).Methods(http.MethodGet).Name("get-auth-code-request")
 	appRouter.Handle("/auth-code-requests/{auth_code_request_id}", UpdateAuthCodeRequest(authCodeService, authCodeRequestService)).Methods(http.MethodPut).Name("update-auth-code-request")
+	appRouter.Handle("/auth-code-requests/{auth_code_request_id}", CancelAuthCodeRequest(authCodeRequestService)).Methods(http.MethodDelete).Name("cancel-auth-code-request")
+
+	// Letter events are reported by the AuthCode API, so may only be made using internal API keys
+	appRouter.Handle("/auth-code-requests/{auth_code_request_id}/letter-events", requireElevatedAPIKey(RecordLetterEvent(authCodeRequestService))).Methods(http.MethodPost).Name("create-letter-event")
 
 	mainRouter.Use(log.Handler)
 }
@@ -3,10 +3,13 @@ package handlers
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/companieshouse/chs.go/authentication"
 	"github.com/companieshouse/emergency-auth-code-api/config"
 	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/service"
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 
@@ -20,14 +23,81 @@ func TestUnitRegisterRoutes(t *testing.T) {
 		defer mockCtrl.Finish()
 		mockAuthcodeService := mocks.NewMockAuthcodeDAOService(mockCtrl)
 		mockAuthcodeRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-		Register(router, &config.Config{}, mockAuthcodeService, mockAuthcodeRequestService)
+		mockAuthcodeAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		Register(router, &config.Config{}, mockAuthcodeService, mockAuthcodeRequestService, mockAuthcodeAuditService, &service.QueueAPILetterDispatcher{})
 
 		So(router.GetRoute("healthcheck"), ShouldNotBeNil)
 		So(router.GetRoute("get-company-officers"), ShouldNotBeNil)
 		So(router.GetRoute("get-company-officer"), ShouldNotBeNil)
+		So(router.GetRoute("get-company-eligibility"), ShouldNotBeNil)
 		So(router.GetRoute("create-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("list-auth-code-requests"), ShouldNotBeNil)
 		So(router.GetRoute("get-auth-code-request"), ShouldNotBeNil)
 		So(router.GetRoute("update-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("cancel-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("search-auth-code-requests"), ShouldNotBeNil)
+		So(router.GetRoute("list-held-auth-code-requests"), ShouldNotBeNil)
+		So(router.GetRoute("approve-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("reject-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("resend-auth-code-request"), ShouldNotBeNil)
+		So(router.GetRoute("get-auth-code-request-audit"), ShouldNotBeNil)
+		So(router.GetRoute("export-data-subject"), ShouldNotBeNil)
+		So(router.GetRoute("erase-data-subject"), ShouldNotBeNil)
+		So(router.GetRoute("get-shadow-letter-counts"), ShouldNotBeNil)
+		So(router.GetRoute("create-letter-event"), ShouldNotBeNil)
+	})
+}
+
+func TestUnitAdminRoutesRequireElevatedAPIKey(t *testing.T) {
+	Convey("Admin routes", t, func() {
+		router := mux.NewRouter()
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		Register(router, &config.Config{}, mocks.NewMockAuthcodeDAOService(mockCtrl), mocks.NewMockAuthcodeRequestDAOService(mockCtrl), mocks.NewMockAuthcodeAuditDAOService(mockCtrl), &service.QueueAPILetterDispatcher{})
+
+		serve := func(identityType, keyRoles string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodGet, "/emergency-auth-code-service/admin/auth-code-requests", nil)
+			req.Header.Set("ERIC-Identity-Type", identityType)
+			req.Header.Set("ERIC-Authorised-Key-Roles", keyRoles)
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+			return res
+		}
+
+		Convey("user is forbidden", func() {
+			So(serve(authentication.Oauth2IdentityType, "").Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("API key without elevated privileges is forbidden", func() {
+			So(serve(authentication.APIKeyIdentityType, "").Code, ShouldEqual, http.StatusForbidden)
+		})
+	})
+}
+
+func TestUnitLetterEventRouteRequiresElevatedAPIKey(t *testing.T) {
+	Convey("Letter event route", t, func() {
+		router := mux.NewRouter()
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		Register(router, &config.Config{}, mocks.NewMockAuthcodeDAOService(mockCtrl), mocks.NewMockAuthcodeRequestDAOService(mockCtrl), mocks.NewMockAuthcodeAuditDAOService(mockCtrl), &service.QueueAPILetterDispatcher{})
+
+		serve := func(identityType, keyRoles string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(http.MethodPost, "/emergency-auth-code-service/auth-code-requests/123/letter-events", strings.NewReader(`{"type":"printed"}`))
+			req.Header.Set("ERIC-Identity", "identity")
+			req.Header.Set("ERIC-Identity-Type", identityType)
+			req.Header.Set("ERIC-Authorised-Key-Roles", keyRoles)
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+			return res
+		}
+
+		Convey("user is forbidden", func() {
+			So(serve(authentication.Oauth2IdentityType, "").Code, ShouldEqual, http.StatusForbidden)
+		})
+
+		Convey("API key without elevated privileges is forbidden", func() {
+			So(serve(authentication.APIKeyIdentityType, "").Code, ShouldEqual, http.StatusForbidden)
+		})
 	})
 }
 
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/companieshouse/chs.go/authentication"
+	"github.com/companieshouse/emergency-auth-code-api/service"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// getRequester builds the service requester for the authenticated caller of the supplied request
+func getRequester(req *http.Request) (*service.Requester, error) {
+	userDetails, ok := req.Context().Value(authentication.ContextKeyUserDetails).(authentication.AuthUserDetails)
+	if !ok {
+		return nil, fmt.Errorf("user details not in request context")
+	}
+
+	requester := &service.Requester{
+		UserID:    userDetails.ID,
+		Email:     userDetails.Email,
+		Elevated:  isElevatedAPIKey(req),
+		APIKey:    authentication.GetAuthorisedIdentityType(req) == authentication.APIKeyIdentityType,
+		RequestID: req.Header.Get("X-Request-Id"),
+	}
+	if requester.APIKey {
+		requester.APIKeyID = authentication.GetAuthorisedIdentity(req)
+	}
+
+	return requester, nil
+}
+
+// isElevatedAPIKey returns whether the request has been authenticated using an API key with elevated privileges
+func isElevatedAPIKey(req *http.Request) bool {
+	return authentication.GetAuthorisedIdentityType(req) == authentication.APIKeyIdentityType &&
+		authentication.IsKeyElevatedPrivilegesAuthorised(req)
+}
+
+// requireElevatedAPIKey rejects requests which have not been authenticated using an API key with
+// elevated privileges, so that the wrapped handlers are only available to internal administrators
+func requireElevatedAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !isElevatedAPIKey(req) {
+			utils.WriteErrorMessage(w, req, http.StatusForbidden, "elevated API key privileges required")
+			return
+		}
+		next.ServeHTTP(w, req)
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/companieshouse/emergency-auth-code-api/config"
 	"github.com/companieshouse/emergency-auth-code-api/dao"
 	"github.com/companieshouse/emergency-auth-code-api/handlers"
+	"github.com/companieshouse/emergency-auth-code-api/service"
 	"github.com/gorilla/mux"
 )
 
@@ -31,8 +32,56 @@ func main() {
 	mainRouter := mux.NewRouter()
 	authCodeSvc := dao.NewAuthCodeDAOService(cfg)
 	authCodeRequestSvc := dao.NewAuthCodeRequestDAOService(cfg)
+	authCodeAuditSvc := dao.NewAuthCodeAuditDAOService(cfg)
 
-	handlers.Register(mainRouter, cfg, authCodeSvc, authCodeRequestSvc)
+	if err := authCodeRequestSvc.CheckTransactionSupport(); err != nil {
+		log.Error(fmt.Errorf("error checking mongodb deployment: %s. Exiting", err), nil)
+		return
+	}
+
+	if err := authCodeRequestSvc.EnsureAuthCodeRequestIndexes(); err != nil {
+		log.Error(fmt.Errorf("error creating auth code request indexes: %s. Exiting", err), nil)
+		return
+	}
+
+	letterDispatcher, err := service.NewLetterDispatcher(cfg)
+	if err != nil {
+		log.Error(fmt.Errorf("error configuring letter dispatcher: %s. Exiting", err), nil)
+		return
+	}
+
+	handlers.Register(mainRouter, cfg, authCodeSvc, authCodeRequestSvc, authCodeAuditSvc, letterDispatcher)
+
+	// Start background jobs, which run until the server is shut down
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	retentionJob := &service.RetentionJob{
+		Config:   cfg,
+		DAO:      authCodeRequestSvc,
+		AuditDAO: authCodeAuditSvc,
+	}
+	if err := retentionJob.CheckConfig(); err != nil {
+		log.Error(fmt.Errorf("error configuring retention job: %s. Exiting", err), nil)
+		return
+	}
+
+	outboxDispatcher := &service.OutboxDispatcher{
+		Config:           cfg,
+		DAO:              dao.NewAuthCodeOutboxDAOService(cfg),
+		RequestDAO:       authCodeRequestSvc,
+		AuditDAO:         authCodeAuditSvc,
+		LetterDispatcher: letterDispatcher,
+	}
+	go outboxDispatcher.Start(jobsCtx)
+
+	expirySweeper := &service.ExpirySweeper{
+		Config:   cfg,
+		DAO:      authCodeRequestSvc,
+		AuditDAO: authCodeAuditSvc,
+	}
+	go expirySweeper.Start(jobsCtx)
+	go retentionJob.Start(jobsCtx)
 
 	log.Info("Starting " + namespace)
 
@@ -59,6 +108,8 @@ func main() {
 	<-stop
 
 	log.Info("shutting down server...")
+	stopJobs()
+
 	timeout := time.Duration(5) * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
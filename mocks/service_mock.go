@@ -7,6 +7,7 @@ import (
 	models "github.com/companieshouse/emergency-auth-code-api/models"
 	gomock "github.com/golang/mock/gomock"
 	reflect "reflect"
+	time "time"
 )
 
 // MockAuthcodeDAOService is a mock of AuthcodeDAOService interface
@@ -105,52 +106,532 @@ func (mr *MockAuthcodeRequestDAOServiceMockRecorder) GetAuthCodeRequest(authCode
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).GetAuthCodeRequest), authCodeRequestID)
 }
 
+// GetAuthCodeRequestByIdempotencyKey mocks base method
+func (m *MockAuthcodeRequestDAOService) GetAuthCodeRequestByIdempotencyKey(userID, idempotencyKey string) (*models.AuthCodeRequestResourceDao, error) {
+	ret := m.ctrl.Call(m, "GetAuthCodeRequestByIdempotencyKey", userID, idempotencyKey)
+	ret0, _ := ret[0].(*models.AuthCodeRequestResourceDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuthCodeRequestByIdempotencyKey indicates an expected call of GetAuthCodeRequestByIdempotencyKey
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) GetAuthCodeRequestByIdempotencyKey(userID, idempotencyKey interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuthCodeRequestByIdempotencyKey", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).GetAuthCodeRequestByIdempotencyKey), userID, idempotencyKey)
+}
+
+// GetLatestPendingAuthCodeRequest mocks base method
+func (m *MockAuthcodeRequestDAOService) GetLatestPendingAuthCodeRequest(userID, companyNumber string, createdSince time.Time) (*models.AuthCodeRequestResourceDao, error) {
+	ret := m.ctrl.Call(m, "GetLatestPendingAuthCodeRequest", userID, companyNumber, createdSince)
+	ret0, _ := ret[0].(*models.AuthCodeRequestResourceDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestPendingAuthCodeRequest indicates an expected call of GetLatestPendingAuthCodeRequest
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) GetLatestPendingAuthCodeRequest(userID, companyNumber, createdSince interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestPendingAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).GetLatestPendingAuthCodeRequest), userID, companyNumber, createdSince)
+}
+
+// ListStalePendingAuthCodeRequestIDs mocks base method
+func (m *MockAuthcodeRequestDAOService) ListStalePendingAuthCodeRequestIDs(createdBefore time.Time, limit int) ([]string, error) {
+	ret := m.ctrl.Call(m, "ListStalePendingAuthCodeRequestIDs", createdBefore, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStalePendingAuthCodeRequestIDs indicates an expected call of ListStalePendingAuthCodeRequestIDs
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ListStalePendingAuthCodeRequestIDs(createdBefore, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStalePendingAuthCodeRequestIDs", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ListStalePendingAuthCodeRequestIDs), createdBefore, limit)
+}
+
+// ListStaleSubmittingAuthCodeRequestIDs mocks base method
+func (m *MockAuthcodeRequestDAOService) ListStaleSubmittingAuthCodeRequestIDs(submittingBefore time.Time, limit int) ([]string, error) {
+	ret := m.ctrl.Call(m, "ListStaleSubmittingAuthCodeRequestIDs", submittingBefore, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStaleSubmittingAuthCodeRequestIDs indicates an expected call of ListStaleSubmittingAuthCodeRequestIDs
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ListStaleSubmittingAuthCodeRequestIDs(submittingBefore, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStaleSubmittingAuthCodeRequestIDs", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ListStaleSubmittingAuthCodeRequestIDs), submittingBefore, limit)
+}
+
+// CountClosedAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) CountClosedAuthCodeRequests(before time.Time, excludeAnonymised bool) (int64, error) {
+	ret := m.ctrl.Call(m, "CountClosedAuthCodeRequests", before, excludeAnonymised)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountClosedAuthCodeRequests indicates an expected call of CountClosedAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountClosedAuthCodeRequests(before, excludeAnonymised interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountClosedAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountClosedAuthCodeRequests), before, excludeAnonymised)
+}
+
+// AnonymiseClosedAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) AnonymiseClosedAuthCodeRequests(before time.Time, limit int) ([]string, error) {
+	ret := m.ctrl.Call(m, "AnonymiseClosedAuthCodeRequests", before, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymiseClosedAuthCodeRequests indicates an expected call of AnonymiseClosedAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) AnonymiseClosedAuthCodeRequests(before, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymiseClosedAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).AnonymiseClosedAuthCodeRequests), before, limit)
+}
+
+// DeleteClosedAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) DeleteClosedAuthCodeRequests(before time.Time, limit int) ([]string, error) {
+	ret := m.ctrl.Call(m, "DeleteClosedAuthCodeRequests", before, limit)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteClosedAuthCodeRequests indicates an expected call of DeleteClosedAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) DeleteClosedAuthCodeRequests(before, limit interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClosedAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).DeleteClosedAuthCodeRequests), before, limit)
+}
+
+// ListDataSubjectAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) ListDataSubjectAuthCodeRequests(subject models.DataSubject) ([]models.AuthCodeRequestResourceDao, error) {
+	ret := m.ctrl.Call(m, "ListDataSubjectAuthCodeRequests", subject)
+	ret0, _ := ret[0].([]models.AuthCodeRequestResourceDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDataSubjectAuthCodeRequests indicates an expected call of ListDataSubjectAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ListDataSubjectAuthCodeRequests(subject interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDataSubjectAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ListDataSubjectAuthCodeRequests), subject)
+}
+
+// ListUndeliveredAuthCodeRequestIDs mocks base method
+func (m *MockAuthcodeRequestDAOService) ListUndeliveredAuthCodeRequestIDs(authCodeRequestIDs []string) ([]string, error) {
+	ret := m.ctrl.Call(m, "ListUndeliveredAuthCodeRequestIDs", authCodeRequestIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUndeliveredAuthCodeRequestIDs indicates an expected call of ListUndeliveredAuthCodeRequestIDs
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ListUndeliveredAuthCodeRequestIDs(authCodeRequestIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUndeliveredAuthCodeRequestIDs", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ListUndeliveredAuthCodeRequestIDs), authCodeRequestIDs)
+}
+
+// AnonymiseAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) AnonymiseAuthCodeRequests(authCodeRequestIDs []string) ([]string, error) {
+	ret := m.ctrl.Call(m, "AnonymiseAuthCodeRequests", authCodeRequestIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymiseAuthCodeRequests indicates an expected call of AnonymiseAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) AnonymiseAuthCodeRequests(authCodeRequestIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymiseAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).AnonymiseAuthCodeRequests), authCodeRequestIDs)
+}
+
+// DeleteAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) DeleteAuthCodeRequests(authCodeRequestIDs []string) ([]string, error) {
+	ret := m.ctrl.Call(m, "DeleteAuthCodeRequests", authCodeRequestIDs)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuthCodeRequests indicates an expected call of DeleteAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) DeleteAuthCodeRequests(authCodeRequestIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).DeleteAuthCodeRequests), authCodeRequestIDs)
+}
+
+// CheckTransactionSupport mocks base method
+func (m *MockAuthcodeRequestDAOService) CheckTransactionSupport() error {
+	ret := m.ctrl.Call(m, "CheckTransactionSupport")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CheckTransactionSupport indicates an expected call of CheckTransactionSupport
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CheckTransactionSupport() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckTransactionSupport", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CheckTransactionSupport))
+}
+
+// EnsureAuthCodeRequestIndexes mocks base method
+func (m *MockAuthcodeRequestDAOService) EnsureAuthCodeRequestIndexes() error {
+	ret := m.ctrl.Call(m, "EnsureAuthCodeRequestIndexes")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureAuthCodeRequestIndexes indicates an expected call of EnsureAuthCodeRequestIndexes
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) EnsureAuthCodeRequestIndexes() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureAuthCodeRequestIndexes", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).EnsureAuthCodeRequestIndexes))
+}
+
 // UpdateAuthCodeRequestOfficer mocks base method
-func (m *MockAuthcodeRequestDAOService) UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao) error {
-	ret := m.ctrl.Call(m, "UpdateAuthCodeRequestOfficer", dao)
+func (m *MockAuthcodeRequestDAOService) UpdateAuthCodeRequestOfficer(dao *models.AuthCodeRequestResourceDao, expectedEtag string) error {
+	ret := m.ctrl.Call(m, "UpdateAuthCodeRequestOfficer", dao, expectedEtag)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateAuthCodeRequestOfficer indicates an expected call of UpdateAuthCodeRequestOfficer
-func (mr *MockAuthcodeRequestDAOServiceMockRecorder) UpdateAuthCodeRequestOfficer(dao interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthCodeRequestOfficer", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).UpdateAuthCodeRequestOfficer), dao)
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) UpdateAuthCodeRequestOfficer(dao, expectedEtag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthCodeRequestOfficer", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).UpdateAuthCodeRequestOfficer), dao, expectedEtag)
+}
+
+// ResumeAuthCodeRequest mocks base method
+func (m *MockAuthcodeRequestDAOService) ResumeAuthCodeRequest(dao *models.AuthCodeRequestResourceDao, expectedEtag string, idempotencyKey *models.IdempotencyKeyDao) error {
+	ret := m.ctrl.Call(m, "ResumeAuthCodeRequest", dao, expectedEtag, idempotencyKey)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeAuthCodeRequest indicates an expected call of ResumeAuthCodeRequest
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ResumeAuthCodeRequest(dao, expectedEtag, idempotencyKey interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ResumeAuthCodeRequest), dao, expectedEtag, idempotencyKey)
 }
 
 // UpdateAuthCodeRequestStatus mocks base method
-func (m *MockAuthcodeRequestDAOService) UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao) error {
-	ret := m.ctrl.Call(m, "UpdateAuthCodeRequestStatus", dao)
+func (m *MockAuthcodeRequestDAOService) UpdateAuthCodeRequestStatus(dao *models.AuthCodeRequestResourceDao, fromStatus models.RequestStatus, outboxItems []models.OutboxItemDao) error {
+	ret := m.ctrl.Call(m, "UpdateAuthCodeRequestStatus", dao, fromStatus, outboxItems)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateAuthCodeRequestStatus indicates an expected call of UpdateAuthCodeRequestStatus
-func (mr *MockAuthcodeRequestDAOServiceMockRecorder) UpdateAuthCodeRequestStatus(dao interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthCodeRequestStatus", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).UpdateAuthCodeRequestStatus), dao)
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) UpdateAuthCodeRequestStatus(dao, fromStatus, outboxItems interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAuthCodeRequestStatus", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).UpdateAuthCodeRequestStatus), dao, fromStatus, outboxItems)
 }
 
-// CheckMultipleCorporateBodySubmissions mocks base method
-func (m *MockAuthcodeRequestDAOService) CheckMultipleCorporateBodySubmissions(companyNumber string) (bool, error) {
-	ret := m.ctrl.Call(m, "CheckMultipleCorporateBodySubmissions", companyNumber)
+// StartAuthCodeRequestSubmission mocks base method
+func (m *MockAuthcodeRequestDAOService) StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag string) (bool, error) {
+	ret := m.ctrl.Call(m, "StartAuthCodeRequestSubmission", authCodeRequestID, expectedEtag)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckMultipleCorporateBodySubmissions indicates an expected call of CheckMultipleCorporateBodySubmissions
-func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CheckMultipleCorporateBodySubmissions(companyNumber interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMultipleCorporateBodySubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CheckMultipleCorporateBodySubmissions), companyNumber)
+// StartAuthCodeRequestSubmission indicates an expected call of StartAuthCodeRequestSubmission
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartAuthCodeRequestSubmission", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).StartAuthCodeRequestSubmission), authCodeRequestID, expectedEtag)
 }
 
-// CheckMultipleUserSubmissions mocks base method
-func (m *MockAuthcodeRequestDAOService) CheckMultipleUserSubmissions(email string) (bool, error) {
-	ret := m.ctrl.Call(m, "CheckMultipleUserSubmissions", email)
+// TransitionAuthCodeRequestStatus mocks base method
+func (m *MockAuthcodeRequestDAOService) TransitionAuthCodeRequestStatus(authCodeRequestID string, fromStatus, toStatus models.RequestStatus) (bool, error) {
+	ret := m.ctrl.Call(m, "TransitionAuthCodeRequestStatus", authCodeRequestID, fromStatus, toStatus)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckMultipleUserSubmissions indicates an expected call of CheckMultipleUserSubmissions
-func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CheckMultipleUserSubmissions(email interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckMultipleUserSubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CheckMultipleUserSubmissions), email)
+// TransitionAuthCodeRequestStatus indicates an expected call of TransitionAuthCodeRequestStatus
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) TransitionAuthCodeRequestStatus(authCodeRequestID, fromStatus, toStatus interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionAuthCodeRequestStatus", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).TransitionAuthCodeRequestStatus), authCodeRequestID, fromStatus, toStatus)
+}
+
+// ListAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) ListAuthCodeRequests(filter models.AuthCodeRequestFilter, startIndex, itemsPerPage int) ([]models.AuthCodeRequestResourceDao, int64, error) {
+	ret := m.ctrl.Call(m, "ListAuthCodeRequests", filter, startIndex, itemsPerPage)
+	ret0, _ := ret[0].([]models.AuthCodeRequestResourceDao)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListAuthCodeRequests indicates an expected call of ListAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ListAuthCodeRequests(filter, startIndex, itemsPerPage interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ListAuthCodeRequests), filter, startIndex, itemsPerPage)
+}
+
+// CountCorporateBodySubmissions mocks base method
+func (m *MockAuthcodeRequestDAOService) CountCorporateBodySubmissions(companyNumber string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	ret := m.ctrl.Call(m, "CountCorporateBodySubmissions", companyNumber, since, recent)
+	ret0, _ := ret[0].(*models.SubmissionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCorporateBodySubmissions indicates an expected call of CountCorporateBodySubmissions
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountCorporateBodySubmissions(companyNumber, since, recent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCorporateBodySubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountCorporateBodySubmissions), companyNumber, since, recent)
+}
+
+// CountUserSubmissions mocks base method
+func (m *MockAuthcodeRequestDAOService) CountUserSubmissions(userID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	ret := m.ctrl.Call(m, "CountUserSubmissions", userID, since, recent)
+	ret0, _ := ret[0].(*models.SubmissionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserSubmissions indicates an expected call of CountUserSubmissions
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountUserSubmissions(userID, since, recent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserSubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountUserSubmissions), userID, since, recent)
+}
+
+// CountOfficerSubmissions mocks base method
+func (m *MockAuthcodeRequestDAOService) CountOfficerSubmissions(officerID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	ret := m.ctrl.Call(m, "CountOfficerSubmissions", officerID, since, recent)
+	ret0, _ := ret[0].(*models.SubmissionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOfficerSubmissions indicates an expected call of CountOfficerSubmissions
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountOfficerSubmissions(officerID, since, recent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOfficerSubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountOfficerSubmissions), officerID, since, recent)
+}
+
+// CountOfficerAddressSubmissions mocks base method
+func (m *MockAuthcodeRequestDAOService) CountOfficerAddressSubmissions(officerUraID string, since time.Time, recent int) (*models.SubmissionCount, error) {
+	ret := m.ctrl.Call(m, "CountOfficerAddressSubmissions", officerUraID, since, recent)
+	ret0, _ := ret[0].(*models.SubmissionCount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOfficerAddressSubmissions indicates an expected call of CountOfficerAddressSubmissions
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountOfficerAddressSubmissions(officerUraID, since, recent interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOfficerAddressSubmissions", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountOfficerAddressSubmissions), officerUraID, since, recent)
+}
+
+// CountUserCompanies mocks base method
+func (m *MockAuthcodeRequestDAOService) CountUserCompanies(userID string, since time.Time) (int, error) {
+	ret := m.ctrl.Call(m, "CountUserCompanies", userID, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserCompanies indicates an expected call of CountUserCompanies
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) CountUserCompanies(userID, since interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserCompanies", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).CountUserCompanies), userID, since)
+}
+
+// HoldAuthCodeRequest mocks base method
+func (m *MockAuthcodeRequestDAOService) HoldAuthCodeRequest(authCodeRequestID string, reasons []models.HoldReason) (bool, error) {
+	ret := m.ctrl.Call(m, "HoldAuthCodeRequest", authCodeRequestID, reasons)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HoldAuthCodeRequest indicates an expected call of HoldAuthCodeRequest
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) HoldAuthCodeRequest(authCodeRequestID, reasons interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HoldAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).HoldAuthCodeRequest), authCodeRequestID, reasons)
+}
+
+// ReviewAuthCodeRequest mocks base method
+func (m *MockAuthcodeRequestDAOService) ReviewAuthCodeRequest(authCodeRequestID string, toStatus models.RequestStatus, review models.ReviewDao) (bool, error) {
+	ret := m.ctrl.Call(m, "ReviewAuthCodeRequest", authCodeRequestID, toStatus, review)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReviewAuthCodeRequest indicates an expected call of ReviewAuthCodeRequest
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ReviewAuthCodeRequest(authCodeRequestID, toStatus, review interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReviewAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ReviewAuthCodeRequest), authCodeRequestID, toStatus, review)
+}
+
+// SearchAuthCodeRequests mocks base method
+func (m *MockAuthcodeRequestDAOService) SearchAuthCodeRequests(search models.AuthCodeRequestSearch) ([]models.AuthCodeRequestResourceDao, error) {
+	ret := m.ctrl.Call(m, "SearchAuthCodeRequests", search)
+	ret0, _ := ret[0].([]models.AuthCodeRequestResourceDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchAuthCodeRequests indicates an expected call of SearchAuthCodeRequests
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) SearchAuthCodeRequests(search interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchAuthCodeRequests", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).SearchAuthCodeRequests), search)
+}
+
+// ResendAuthCodeRequest mocks base method
+func (m *MockAuthcodeRequestDAOService) ResendAuthCodeRequest(authCodeRequestID string, fromStatus models.RequestStatus, resend models.ResendDao, outboxItems []models.OutboxItemDao) error {
+	ret := m.ctrl.Call(m, "ResendAuthCodeRequest", authCodeRequestID, fromStatus, resend, outboxItems)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendAuthCodeRequest indicates an expected call of ResendAuthCodeRequest
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) ResendAuthCodeRequest(authCodeRequestID, fromStatus, resend, outboxItems interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendAuthCodeRequest", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).ResendAuthCodeRequest), authCodeRequestID, fromStatus, resend, outboxItems)
+}
+
+// RecordLetterDispatch mocks base method
+func (m *MockAuthcodeRequestDAOService) RecordLetterDispatch(authCodeRequestID string, dispatch models.LetterDispatchDao) error {
+	ret := m.ctrl.Call(m, "RecordLetterDispatch", authCodeRequestID, dispatch)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLetterDispatch indicates an expected call of RecordLetterDispatch
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) RecordLetterDispatch(authCodeRequestID, dispatch interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLetterDispatch", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).RecordLetterDispatch), authCodeRequestID, dispatch)
+}
+
+// RecordLetterEvent mocks base method
+func (m *MockAuthcodeRequestDAOService) RecordLetterEvent(authCodeRequestID string, fromStatus, toStatus models.RequestStatus, event models.LetterEventDao) (bool, error) {
+	ret := m.ctrl.Call(m, "RecordLetterEvent", authCodeRequestID, fromStatus, toStatus, event)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLetterEvent indicates an expected call of RecordLetterEvent
+func (mr *MockAuthcodeRequestDAOServiceMockRecorder) RecordLetterEvent(authCodeRequestID, fromStatus, toStatus, event interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLetterEvent", reflect.TypeOf((*MockAuthcodeRequestDAOService)(nil).RecordLetterEvent), authCodeRequestID, fromStatus, toStatus, event)
+}
+
+// MockAuthcodeOutboxDAOService is a mock of AuthcodeOutboxDAOService interface
+type MockAuthcodeOutboxDAOService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthcodeOutboxDAOServiceMockRecorder
+}
+
+// MockAuthcodeOutboxDAOServiceMockRecorder is the mock recorder for MockAuthcodeOutboxDAOService
+type MockAuthcodeOutboxDAOServiceMockRecorder struct {
+	mock *MockAuthcodeOutboxDAOService
+}
+
+// NewMockAuthcodeOutboxDAOService creates a new mock instance
+func NewMockAuthcodeOutboxDAOService(ctrl *gomock.Controller) *MockAuthcodeOutboxDAOService {
+	mock := &MockAuthcodeOutboxDAOService{ctrl: ctrl}
+	mock.recorder = &MockAuthcodeOutboxDAOServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuthcodeOutboxDAOService) EXPECT() *MockAuthcodeOutboxDAOServiceMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutboxItem mocks base method
+func (m *MockAuthcodeOutboxDAOService) ClaimOutboxItem(leaseDuration time.Duration) (*models.OutboxItemDao, error) {
+	ret := m.ctrl.Call(m, "ClaimOutboxItem", leaseDuration)
+	ret0, _ := ret[0].(*models.OutboxItemDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutboxItem indicates an expected call of ClaimOutboxItem
+func (mr *MockAuthcodeOutboxDAOServiceMockRecorder) ClaimOutboxItem(leaseDuration interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutboxItem", reflect.TypeOf((*MockAuthcodeOutboxDAOService)(nil).ClaimOutboxItem), leaseDuration)
+}
+
+// CompleteOutboxItem mocks base method
+func (m *MockAuthcodeOutboxDAOService) CompleteOutboxItem(outboxItemID string) error {
+	ret := m.ctrl.Call(m, "CompleteOutboxItem", outboxItemID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteOutboxItem indicates an expected call of CompleteOutboxItem
+func (mr *MockAuthcodeOutboxDAOServiceMockRecorder) CompleteOutboxItem(outboxItemID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteOutboxItem", reflect.TypeOf((*MockAuthcodeOutboxDAOService)(nil).CompleteOutboxItem), outboxItemID)
+}
+
+// RetryOutboxItem mocks base method
+func (m *MockAuthcodeOutboxDAOService) RetryOutboxItem(outboxItemID, lastError string, nextAttemptAt time.Time) error {
+	ret := m.ctrl.Call(m, "RetryOutboxItem", outboxItemID, lastError, nextAttemptAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RetryOutboxItem indicates an expected call of RetryOutboxItem
+func (mr *MockAuthcodeOutboxDAOServiceMockRecorder) RetryOutboxItem(outboxItemID, lastError, nextAttemptAt interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryOutboxItem", reflect.TypeOf((*MockAuthcodeOutboxDAOService)(nil).RetryOutboxItem), outboxItemID, lastError, nextAttemptAt)
+}
+
+// FailOutboxItem mocks base method
+func (m *MockAuthcodeOutboxDAOService) FailOutboxItem(outboxItemID, lastError string) error {
+	ret := m.ctrl.Call(m, "FailOutboxItem", outboxItemID, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailOutboxItem indicates an expected call of FailOutboxItem
+func (mr *MockAuthcodeOutboxDAOServiceMockRecorder) FailOutboxItem(outboxItemID, lastError interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailOutboxItem", reflect.TypeOf((*MockAuthcodeOutboxDAOService)(nil).FailOutboxItem), outboxItemID, lastError)
+}
+
+// MockAuthcodeAuditDAOService is a mock of AuthcodeAuditDAOService interface
+type MockAuthcodeAuditDAOService struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuthcodeAuditDAOServiceMockRecorder
+}
+
+// MockAuthcodeAuditDAOServiceMockRecorder is the mock recorder for MockAuthcodeAuditDAOService
+type MockAuthcodeAuditDAOServiceMockRecorder struct {
+	mock *MockAuthcodeAuditDAOService
+}
+
+// NewMockAuthcodeAuditDAOService creates a new mock instance
+func NewMockAuthcodeAuditDAOService(ctrl *gomock.Controller) *MockAuthcodeAuditDAOService {
+	mock := &MockAuthcodeAuditDAOService{ctrl: ctrl}
+	mock.recorder = &MockAuthcodeAuditDAOServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockAuthcodeAuditDAOService) EXPECT() *MockAuthcodeAuditDAOServiceMockRecorder {
+	return m.recorder
+}
+
+// InsertAuditEntry mocks base method
+func (m *MockAuthcodeAuditDAOService) InsertAuditEntry(entry *models.AuditEntryDao) error {
+	ret := m.ctrl.Call(m, "InsertAuditEntry", entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertAuditEntry indicates an expected call of InsertAuditEntry
+func (mr *MockAuthcodeAuditDAOServiceMockRecorder) InsertAuditEntry(entry interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertAuditEntry", reflect.TypeOf((*MockAuthcodeAuditDAOService)(nil).InsertAuditEntry), entry)
+}
+
+// ListAuditEntries mocks base method
+func (m *MockAuthcodeAuditDAOService) ListAuditEntries(authCodeRequestID string) ([]models.AuditEntryDao, error) {
+	ret := m.ctrl.Call(m, "ListAuditEntries", authCodeRequestID)
+	ret0, _ := ret[0].([]models.AuditEntryDao)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuditEntries indicates an expected call of ListAuditEntries
+func (mr *MockAuthcodeAuditDAOServiceMockRecorder) ListAuditEntries(authCodeRequestID interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuditEntries", reflect.TypeOf((*MockAuthcodeAuditDAOService)(nil).ListAuditEntries), authCodeRequestID)
+}
+
+// AnonymiseAuditEntries mocks base method
+func (m *MockAuthcodeAuditDAOService) AnonymiseAuditEntries(authCodeRequestIDs []string) (int64, error) {
+	ret := m.ctrl.Call(m, "AnonymiseAuditEntries", authCodeRequestIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymiseAuditEntries indicates an expected call of AnonymiseAuditEntries
+func (mr *MockAuthcodeAuditDAOServiceMockRecorder) AnonymiseAuditEntries(authCodeRequestIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymiseAuditEntries", reflect.TypeOf((*MockAuthcodeAuditDAOService)(nil).AnonymiseAuditEntries), authCodeRequestIDs)
+}
+
+// DeleteAuditEntries mocks base method
+func (m *MockAuthcodeAuditDAOService) DeleteAuditEntries(authCodeRequestIDs []string) (int64, error) {
+	ret := m.ctrl.Call(m, "DeleteAuditEntries", authCodeRequestIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAuditEntries indicates an expected call of DeleteAuditEntries
+func (mr *MockAuthcodeAuditDAOServiceMockRecorder) DeleteAuditEntries(authCodeRequestIDs interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAuditEntries", reflect.TypeOf((*MockAuthcodeAuditDAOService)(nil).DeleteAuditEntries), authCodeRequestIDs)
 }
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditAction is the change made to an auth code request which is recorded in its audit trail
+type AuditAction string
+
+// The changes to auth code requests which are recorded in their audit trails
+const (
+	AuditActionCreated           AuditAction = "created"
+	AuditActionOfficerUpdated    AuditAction = "officer-updated"
+	AuditActionSubmissionStarted AuditAction = "submission-started"
+	AuditActionSubmissionAborted AuditAction = "submission-aborted"
+	AuditActionSubmitted         AuditAction = "submitted"
+	AuditActionHeld              AuditAction = "held"
+	AuditActionApproved          AuditAction = "approved"
+	AuditActionRejected          AuditAction = "rejected"
+	AuditActionCancelled         AuditAction = "cancelled"
+	AuditActionResent            AuditAction = "resent"
+	AuditActionDispatched        AuditAction = "dispatched"
+	AuditActionDispatchFailed    AuditAction = "dispatch-failed"
+	AuditActionLetterEvent       AuditAction = "letter-event"
+	AuditActionExpired           AuditAction = "expired"
+)
+
+// ActorType is the kind of caller which made a change to an auth code request
+type ActorType string
+
+// The kinds of caller which may change an auth code request
+const (
+	ActorTypeUser   ActorType = "user"
+	ActorTypeAPIKey ActorType = "api-key"
+	// ActorTypeSystem is used for changes made by the service itself, such as by background jobs
+	ActorTypeSystem ActorType = "system"
+)
+
+// AuditStateDao is the state of the audited fields of an auth code request before or after a change
+type AuditStateDao struct {
+	Status          RequestStatus `bson:"status,omitempty"`
+	OfficerID       string        `bson:"officer_id,omitempty"`
+	OfficerUraID    string        `bson:"officer_ura_id,omitempty"`
+	OfficerForename string        `bson:"officer_forename,omitempty"`
+	OfficerSurname  string        `bson:"officer_surname,omitempty"`
+}
+
+// AuditEntryDao records a single change to an auth code request. Entries are only ever appended to the
+// audit trail, and are only updated or removed to erase personal data.
+type AuditEntryDao struct {
+	ID                string         `bson:"_id"`
+	AuthCodeRequestID string         `bson:"auth_code_request_id"`
+	Action            AuditAction    `bson:"action"`
+	Actor             ActorDao       `bson:"actor"`
+	ActorType         ActorType      `bson:"actor_type"`
+	Before            *AuditStateDao `bson:"before,omitempty"`
+	After             *AuditStateDao `bson:"after,omitempty"`
+	At                *time.Time     `bson:"at"`
+	RequestID         string         `bson:"request_id,omitempty"`
+}
+
+// AuditState is the state of the audited fields of an auth code request before or after a change
+type AuditState struct {
+	Status          string `json:"status,omitempty"`
+	OfficerID       string `json:"officer_id,omitempty"`
+	OfficerUraID    string `json:"officer_ura_id,omitempty"`
+	OfficerForename string `json:"officer_forename,omitempty"`
+	OfficerSurname  string `json:"officer_surname,omitempty"`
+}
+
+// AuditEntry is a single change to an auth code request, as seen by an administrator
+type AuditEntry struct {
+	ID        string      `json:"id"`
+	Action    string      `json:"action"`
+	Actor     Actor       `json:"actor"`
+	ActorType string      `json:"actor_type"`
+	Before    *AuditState `json:"before,omitempty"`
+	After     *AuditState `json:"after,omitempty"`
+	At        *time.Time  `json:"at"`
+	RequestID string      `json:"request_id,omitempty"`
+}
+
+// AuditTrailResponse is the audit trail of an auth code request, oldest change first
+type AuditTrailResponse struct {
+	AuthCodeRequestID string       `json:"auth_code_request_id"`
+	Items             []AuditEntry `json:"items"`
+}
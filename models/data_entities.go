@@ -12,20 +12,30 @@ type AuthCodeRequestResourceDao struct {
 
 // AuthCodeRequestDataDao is the data of an auth code request resource
 type AuthCodeRequestDataDao struct {
-	CompanyNumber   string       `bson:"company_number"`
-	CompanyName     string       `bson:"company_name"`
-	OfficerID       string       `bson:"officer_id"`
-	OfficerUraID    string       `bson:"officer_ura_id"`
-	OfficerForename string       `bson:"officer_forename"`
-	OfficerSurname  string       `bson:"officer_surname"`
-	Status          string       `bson:"status"`
-	CreatedAt       *time.Time   `bson:"created_at"`
-	SubmittedAt     *time.Time   `bson:"submitted_at"`
-	Kind            string       `bson:"kind"`
-	Etag            string       `bson:"etag"`
-	CreatedBy       CreatedByDao `bson:"created_by"`
-	Type            string
-	Links           AuthCodeResourceLinksDao `bson:"links"`
+	CompanyNumber    string                `bson:"company_number"`
+	CompanyName      string                `bson:"company_name"`
+	OfficerID        string                `bson:"officer_id"`
+	OfficerUraID     string                `bson:"officer_ura_id"`
+	OfficerUraHash   string                `bson:"officer_ura_hash,omitempty"`
+	OfficerForename  string                `bson:"officer_forename"`
+	OfficerSurname   string                `bson:"officer_surname"`
+	Status           RequestStatus         `bson:"status"`
+	StatusHistory    []StatusTransitionDao `bson:"status_history,omitempty"`
+	HoldReasons      []HoldReason          `bson:"hold_reasons,omitempty"`
+	Reviews          []ReviewDao           `bson:"reviews,omitempty"`
+	Resends          []ResendDao           `bson:"resends,omitempty"`
+	LetterEvents     []LetterEventDao      `bson:"letter_events,omitempty"`
+	LetterDispatches []LetterDispatchDao   `bson:"letter_dispatches,omitempty"`
+	CreatedAt        *time.Time            `bson:"created_at"`
+	SubmittingAt     *time.Time            `bson:"submitting_at,omitempty"`
+	SubmittedAt      *time.Time            `bson:"submitted_at"`
+	Kind             string                `bson:"kind"`
+	Etag             string                `bson:"etag"`
+	CreatedBy        CreatedByDao          `bson:"created_by"`
+	IdempotencyKeys  []IdempotencyKeyDao   `bson:"idempotency_keys,omitempty"`
+	AnonymisedAt     *time.Time            `bson:"anonymised_at,omitempty"`
+	Type             string
+	Links            AuthCodeResourceLinksDao `bson:"links"`
 }
 
 // CreatedByDao is the object relating to who created the resource
@@ -36,7 +46,30 @@ type CreatedByDao struct {
 	Surname  string `bson:"surname"`
 }
 
+// IdempotencyKeyDao is an idempotency key supplied by the user when creating or resuming an auth code
+// request, along with the hash of the request body, so that a retry of the request can be replayed
+type IdempotencyKeyDao struct {
+	Key     string `bson:"key"`
+	Hash    string `bson:"hash"`
+	Resumed bool   `bson:"resumed,omitempty"`
+}
+
 // AuthCodeResourceLinksDao is the links object of the auth code resource
 type AuthCodeResourceLinksDao struct {
 	Self string `bson:"self"`
 }
+
+// AuthCodeRequestFilter restricts the auth code requests returned when listing requests. Empty
+// fields are not used to filter.
+type AuthCodeRequestFilter struct {
+	UserID        string
+	Status        RequestStatus
+	CompanyNumber string
+}
+
+// SubmissionCount is the number of auth code requests submitted within a period, along with the
+// submission times of the most recent of them, newest first
+type SubmissionCount struct {
+	Count       int64
+	SubmittedAt []time.Time
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+)
+
+// DataSubject identifies the user whose personal data is exported or erased on their request. The auth
+// code requests created by the user are matched on either their user ID or their email, ignoring case.
+type DataSubject struct {
+	UserID    string
+	UserEmail string
+}
+
+// ErasureMode is how the auth code requests of a data subject are erased
+type ErasureMode string
+
+// The ways in which the auth code requests of a data subject may be erased
+const (
+	// ErasureModeDelete removes the requests along with their outbox items and audit trails
+	ErasureModeDelete ErasureMode = "delete"
+	// ErasureModeAnonymise keeps the requests but removes their personal data, as the retention schedule does
+	ErasureModeAnonymise ErasureMode = "anonymise"
+)
+
+// IsValid returns whether the mode is a supported way of erasing auth code requests
+func (m ErasureMode) IsValid() bool {
+	return m == ErasureModeDelete || m == ErasureModeAnonymise
+}
+
+// DataSubjectExport is every auth code request created by a data subject, along with its audit trail,
+// oldest request first
+type DataSubjectExport struct {
+	UserID           string                       `json:"user_id,omitempty"`
+	UserEmail        string                       `json:"user_email,omitempty"`
+	ExportedAt       *time.Time                   `json:"exported_at"`
+	AuthCodeRequests []DataSubjectAuthCodeRequest `json:"auth_code_requests"`
+}
+
+// DataSubjectAuthCodeRequest is an auth code request created by a data subject, as exported to them. The
+// staff who reviewed, resent or reported on it, and the reasons it was held for review, are left out.
+type DataSubjectAuthCodeRequest struct {
+	ID string `json:"id"`
+	AuthCodeRequestResourceResponse
+	Reviews      []DataSubjectReview      `json:"reviews,omitempty"`
+	Resends      []DataSubjectResend      `json:"resends,omitempty"`
+	LetterEvents []DataSubjectLetterEvent `json:"letter_events,omitempty"`
+	AuditTrail   []DataSubjectAuditEntry  `json:"audit_trail"`
+}
+
+// DataSubjectReview is a decision made on a held auth code request, as exported to its data subject
+type DataSubjectReview struct {
+	Decision   string     `json:"decision"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+// DataSubjectResend is the letter for an auth code request being sent again, as exported to its data subject
+type DataSubjectResend struct {
+	ReasonCode string     `json:"reason_code"`
+	ResentAt   *time.Time `json:"resent_at"`
+}
+
+// DataSubjectLetterEvent is a change to the letter for an auth code request, as exported to its data subject
+type DataSubjectLetterEvent struct {
+	Type       string     `json:"type"`
+	Reference  string     `json:"reference,omitempty"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
+
+// DataSubjectAuditEntry is a single change to an auth code request, as exported to its data subject. The
+// actor is only given for changes made by a user.
+type DataSubjectAuditEntry struct {
+	ID        string      `json:"id"`
+	Action    string      `json:"action"`
+	Actor     *Actor      `json:"actor,omitempty"`
+	ActorType string      `json:"actor_type"`
+	Before    *AuditState `json:"before,omitempty"`
+	After     *AuditState `json:"after,omitempty"`
+	At        *time.Time  `json:"at"`
+}
+
+// DataSubjectErasureResponse lists the auth code requests of a data subject which have been erased, along
+// with any which could not be as they are in flight
+type DataSubjectErasureResponse struct {
+	UserID           string   `json:"user_id,omitempty"`
+	UserEmail        string   `json:"user_email,omitempty"`
+	Mode             string   `json:"mode"`
+	AuthCodeRequests []string `json:"auth_code_requests"`
+	InFlight         []string `json:"in_flight,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// EligibilityReason is a machine readable reason why a corporate body may not request an auth code
+type EligibilityReason string
+
+// The reasons a request for an auth code may be refused
+const (
+	ReasonCompanyRecentlyRequested EligibilityReason = "company-recently-requested"
+	ReasonUserLimitExceeded        EligibilityReason = "user-limit-exceeded"
+	ReasonCompanyFiledRecently     EligibilityReason = "company-filed-recently"
+	ReasonNoEligibleOfficers       EligibilityReason = "no-eligible-officers"
+	ReasonOfficerLimitExceeded     EligibilityReason = "officer-limit-exceeded"
+	ReasonAddressLimitExceeded     EligibilityReason = "address-limit-exceeded"
+)
+
+// EligibilityFailureResponse is returned when a request for an auth code is refused, with the time after
+// which it may be retried if that is known
+type EligibilityFailureResponse struct {
+	Code       EligibilityReason `json:"code"`
+	Message    string            `json:"message"`
+	RetryAfter *time.Time        `json:"retry_after,omitempty"`
+}
+
+// NewEligibilityFailureResponse - convenience function for creating an eligibility failure response
+func NewEligibilityFailureResponse(code EligibilityReason, message string, retryAfter *time.Time) *EligibilityFailureResponse {
+	return &EligibilityFailureResponse{Code: code, Message: message, RetryAfter: retryAfter}
+}
+
+// EligibilityCheck is the outcome of a single check on whether an auth code may be requested
+type EligibilityCheck struct {
+	Check    string                      `json:"check"`
+	Eligible bool                        `json:"eligible"`
+	Failure  *EligibilityFailureResponse `json:"failure,omitempty"`
+}
+
+// EligibilityResponse reports whether an auth code may be requested for a company, along with the outcome
+// of each of the checks made
+type EligibilityResponse struct {
+	CompanyNumber string             `json:"company_number"`
+	Eligible      bool               `json:"eligible"`
+	Checks        []EligibilityCheck `json:"checks"`
+}
@@ -2,12 +2,12 @@ package models
 
 // EmailSend represents the json request body expected by chs-kafka-api
 type EmailSend struct {
-	AppID        string `json:"app_id"`
-	MessageID    string `json:"message_id"`
-	MessageType  string `json:"message_type"`
-	Data         string `json:"json_data"`
-	EmailAddress string `json:"email_address"`
-	CreatedAt    string `json:"created_at"`
+	AppID        string `json:"app_id"        bson:"app_id"`
+	MessageID    string `json:"message_id"    bson:"message_id"`
+	MessageType  string `json:"message_type"  bson:"message_type"`
+	Data         string `json:"json_data"     bson:"json_data"`
+	EmailAddress string `json:"email_address" bson:"email_address"`
+	CreatedAt    string `json:"created_at"    bson:"created_at"`
 }
 
 // DataField represents the data that will eventually be displayed in the email
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+)
+
+// LetterBackend is the API to which the letter for an auth code request is sent
+type LetterBackend string
+
+// The APIs to which letters may be sent
+const (
+	// LetterBackendQueueAPI is used when NewAuthCodeAPIFlow is false
+	LetterBackendQueueAPI LetterBackend = "queue-api"
+	// LetterBackendAuthCodeAPI is used when NewAuthCodeAPIFlow is true
+	LetterBackendAuthCodeAPI LetterBackend = "authcode-api"
+	// LetterBackendFile writes letters to a local file, for development and tests
+	LetterBackendFile LetterBackend = "file"
+)
+
+// LetterDispatchDao records the letter for an auth code request being handed to a letter backend, along
+// with the acknowledgement the backend returned, so that it can be reconciled with the letter system
+type LetterDispatchDao struct {
+	Backend            LetterBackend `bson:"backend"`
+	OutboxItemID       string        `bson:"outbox_item_id"`
+	StatusCode         int           `bson:"status_code"`
+	LetterReference    string        `bson:"letter_reference,omitempty"`
+	QueueItemID        string        `bson:"queue_item_id,omitempty"`
+	AcceptedAt         *time.Time    `bson:"accepted_at,omitempty"`
+	ExpectedDispatchAt *time.Time    `bson:"expected_dispatch_at,omitempty"`
+	DispatchedAt       *time.Time    `bson:"dispatched_at"`
+}
+
+// NewLetterDispatch returns the record of a letter handed now to the supplied backend, which acknowledged
+// it with the supplied response
+func NewLetterDispatch(backend LetterBackend, resp *AuthCodeItemResponse) *LetterDispatchDao {
+	dispatchedAt := time.Now().Truncate(time.Millisecond)
+
+	dispatch := &LetterDispatchDao{
+		Backend:      backend,
+		DispatchedAt: &dispatchedAt,
+	}
+	if resp != nil {
+		dispatch.StatusCode = resp.StatusCode
+		dispatch.LetterReference = resp.LetterReference
+		dispatch.QueueItemID = resp.QueueItemID
+		dispatch.AcceptedAt = resp.CreatedAt
+		dispatch.ExpectedDispatchAt = resp.ExpectedDispatchAt
+	}
+
+	return dispatch
+}
+
+// LetterDispatch is the letter for an auth code request being handed to a letter backend
+type LetterDispatch struct {
+	Backend            string     `json:"backend"`
+	StatusCode         int        `json:"status_code"`
+	LetterReference    string     `json:"letter_reference,omitempty"`
+	QueueItemID        string     `json:"queue_item_id,omitempty"`
+	AcceptedAt         *time.Time `json:"accepted_at,omitempty"`
+	ExpectedDispatchAt *time.Time `json:"expected_dispatch_at,omitempty"`
+	DispatchedAt       *time.Time `json:"dispatched_at"`
+}
+
+// ShadowLetterCountsResponse is the number of letters validated by the shadow letter backend, and of those
+// the number for which its outcome differed from the letter backend
+type ShadowLetterCountsResponse struct {
+	Backend       string `json:"backend"`
+	ShadowBackend string `json:"shadow_backend"`
+	Compared      int64  `json:"compared"`
+	Mismatched    int64  `json:"mismatched"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// LetterEventType is a stage reached by the letter for a submitted auth code request, as reported by the
+// AuthCode API
+type LetterEventType string
+
+// The stages reported by the AuthCode API once it has been sent the letter for an auth code request
+const (
+	LetterEventPrinted             LetterEventType = "printed"
+	LetterEventDispatched          LetterEventType = "dispatched"
+	LetterEventReturnedUndelivered LetterEventType = "returned-undelivered"
+)
+
+// letterEventStatuses maps each letter event to the status of an auth code request whose letter has
+// reached it
+var letterEventStatuses = map[LetterEventType]RequestStatus{
+	LetterEventPrinted:             StatusPrinted,
+	LetterEventDispatched:          StatusPosted,
+	LetterEventReturnedUndelivered: StatusReturned,
+}
+
+// IsValid returns whether the event is one which may be reported for a letter
+func (t LetterEventType) IsValid() bool {
+	_, ok := letterEventStatuses[t]
+	return ok
+}
+
+// Status returns the status of an auth code request whose letter has reached this event
+func (t LetterEventType) Status() RequestStatus {
+	return letterEventStatuses[t]
+}
+
+// LetterEventDao records a stage reached by the letter for a submitted auth code request
+type LetterEventDao struct {
+	Type       LetterEventType `bson:"type"`
+	Reference  string          `bson:"reference,omitempty"`
+	Detail     string          `bson:"detail,omitempty"`
+	OccurredAt *time.Time      `bson:"occurred_at"`
+	ReceivedAt *time.Time      `bson:"received_at"`
+	ReportedBy ActorDao        `bson:"reported_by"`
+}
+
+// LetterEventRequest is the body supplied when the AuthCode API reports a stage reached by the letter for
+// an auth code request. OccurredAt defaults to the time the event is received.
+type LetterEventRequest struct {
+	Type       string     `json:"type"`
+	Reference  string     `json:"reference"`
+	Detail     string     `json:"detail"`
+	OccurredAt *time.Time `json:"occurred_at"`
+}
+
+// LetterEvent is a stage reached by the letter for a submitted auth code request
+type LetterEvent struct {
+	Type       string     `json:"type"`
+	Reference  string     `json:"reference,omitempty"`
+	Detail     string     `json:"detail,omitempty"`
+	OccurredAt *time.Time `json:"occurred_at"`
+	ReceivedAt *time.Time `json:"received_at"`
+	ReportedBy Actor      `json:"reported_by"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Outbox item types
+const (
+	OutboxTypeLetter = "letter"
+	OutboxTypeEmail  = "email"
+)
+
+// Outbox item statuses
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusDone    = "done"
+	OutboxStatusFailed  = "failed"
+)
+
+// OutboxItemDao is a side effect of submitting an auth code request, such as sending the letter or
+// the confirmation email. It is recorded in the same write as the status change, and delivered later
+// by the outbox dispatcher.
+type OutboxItemDao struct {
+	ID                string        `bson:"_id"`
+	AuthCodeRequestID string        `bson:"auth_code_request_id"`
+	Type              string        `bson:"type"`
+	AuthCodeItem      *AuthCodeItem `bson:"auth_code_item,omitempty"`
+	EmailSend         *EmailSend    `bson:"email_send,omitempty"`
+	Status            string        `bson:"status"`
+	Attempts          int           `bson:"attempts"`
+	LastError         string        `bson:"last_error,omitempty"`
+	CreatedAt         *time.Time    `bson:"created_at"`
+	NextAttemptAt     *time.Time    `bson:"next_attempt_at"`
+	CompletedAt       *time.Time    `bson:"completed_at,omitempty"`
+}
@@ -1,23 +1,37 @@
 package models
 
+import (
+	"time"
+)
+
 // AuthCodeItem is authcode data to be sent to chs-queue-api
 type AuthCodeItem struct {
-	Type          string  `json:"type"`
-	Email         string  `json:"email"`
-	CompanyNumber string  `json:"company_number"`
-	CompanyName   string  `json:"company_name"`
-	Address       Address `json:"ro_address"`
-	Status        string  `json:"status"`
+	Type          string  `json:"type"           bson:"type"`
+	Email         string  `json:"email"          bson:"email"`
+	CompanyNumber string  `json:"company_number" bson:"company_number"`
+	CompanyName   string  `json:"company_name"   bson:"company_name"`
+	Address       Address `json:"ro_address"     bson:"ro_address"`
+	Status        string  `json:"status"         bson:"status"`
 }
 
 // Address is the address to which the authcode letter should be posted
 type Address struct {
-	POBox        string `json:"po_box,omitempty"`
-	Premises     string `json:"premises,omitempty"`
-	AddressLine1 string `json:"address_line_1,omitempty"`
-	AddressLine2 string `json:"address_line_2,omitempty"`
-	Locality     string `json:"locality,omitempty"`
-	Region       string `json:"region,omitempty"`
-	PostalCode   string `json:"postal_code,omitempty"`
-	Country      string `json:"country,omitempty"`
+	POBox        string `json:"po_box,omitempty"         bson:"po_box,omitempty"`
+	Premises     string `json:"premises,omitempty"       bson:"premises,omitempty"`
+	AddressLine1 string `json:"address_line_1,omitempty" bson:"address_line_1,omitempty"`
+	AddressLine2 string `json:"address_line_2,omitempty" bson:"address_line_2,omitempty"`
+	Locality     string `json:"locality,omitempty"       bson:"locality,omitempty"`
+	Region       string `json:"region,omitempty"         bson:"region,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"    bson:"postal_code,omitempty"`
+	Country      string `json:"country,omitempty"        bson:"country,omitempty"`
+}
+
+// AuthCodeItemResponse is the acknowledgement returned by the queue API or AuthCode API when it accepts an
+// AuthCodeItem. Fields which the API does not supply are left empty.
+type AuthCodeItemResponse struct {
+	StatusCode         int        `json:"-"`
+	LetterReference    string     `json:"letter_reference"`
+	QueueItemID        string     `json:"queue_item_id"`
+	CreatedAt          *time.Time `json:"created_at"`
+	ExpectedDispatchAt *time.Time `json:"expected_dispatch_at"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"time"
+)
+
+// ResendReason is a machine readable reason why the letter for a submitted auth code request was sent again
+type ResendReason string
+
+// The reasons for which support staff may send the letter for a submitted auth code request again
+const (
+	ResendReasonNotReceived         ResendReason = "not-received"
+	ResendReasonLostInPost          ResendReason = "lost-in-post"
+	ResendReasonReturnedUndelivered ResendReason = "returned-undelivered"
+	ResendReasonDamaged             ResendReason = "damaged"
+)
+
+// resendReasons lists every reason for which a letter may be sent again
+var resendReasons = []ResendReason{
+	ResendReasonNotReceived,
+	ResendReasonLostInPost,
+	ResendReasonReturnedUndelivered,
+	ResendReasonDamaged,
+}
+
+// IsValid returns whether the reason is one for which a letter may be sent again
+func (r ResendReason) IsValid() bool {
+	for _, reason := range resendReasons {
+		if reason == r {
+			return true
+		}
+	}
+	return false
+}
+
+// ResendDao records the letter for a submitted auth code request being sent again
+type ResendDao struct {
+	ReasonCode     ResendReason `bson:"reason_code"`
+	Operator       string       `bson:"operator"`
+	Note           string       `bson:"note,omitempty"`
+	OverrideLimits bool         `bson:"override_limits,omitempty"`
+	RequestedBy    ActorDao     `bson:"requested_by"`
+	ResentAt       *time.Time   `bson:"resent_at"`
+}
+
+// ResendRequest is the body supplied when asking for the letter for a submitted auth code request to be
+// sent again. Operator identifies the member of staff acting on behalf of the customer, who must set
+// OverrideLimits to send the letter when the submission limits have been reached.
+type ResendRequest struct {
+	ReasonCode     string `json:"reason_code"`
+	Operator       string `json:"operator"`
+	Note           string `json:"note"`
+	OverrideLimits bool   `json:"override_limits"`
+}
+
+// Resend is the letter for a submitted auth code request being sent again
+type Resend struct {
+	ReasonCode     string     `json:"reason_code"`
+	Operator       string     `json:"operator"`
+	Note           string     `json:"note,omitempty"`
+	OverrideLimits bool       `json:"override_limits,omitempty"`
+	RequestedBy    Actor      `json:"requested_by"`
+	ResentAt       *time.Time `json:"resent_at"`
+}
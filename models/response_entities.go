@@ -23,6 +23,7 @@ type AuthCodeRequestResourceResponse struct {
 	OfficerUraID  string                       `json:"officer_ura_id"`
 	OfficerName   string                       `json:"officer_name"`
 	Status        string                       `json:"status"`
+	StatusHistory []StatusTransition           `json:"status_history,omitempty"`
 	CreatedAt     *time.Time                   `json:"created_at"`
 	SubmittedAt   *time.Time                   `json:"submitted_at"`
 	Etag          string                       `json:"etag"`
@@ -30,6 +31,26 @@ type AuthCodeRequestResourceResponse struct {
 	Links         AuthCodeRequestResourceLinks `json:"links"`
 }
 
+// ResourceEtag returns the etag of the auth code request, which is sent as the ETag of responses returning it
+func (r *AuthCodeRequestResourceResponse) ResourceEtag() string {
+	return r.Etag
+}
+
+// AuthCodeRequestListResponse is a page of auth code requests
+type AuthCodeRequestListResponse struct {
+	ItemsPerPage int                               `json:"items_per_page"`
+	StartIndex   int                               `json:"start_index"`
+	TotalResults int                               `json:"total_results"`
+	Items        []AuthCodeRequestResourceResponse `json:"items"`
+}
+
+// StatusTransition is a change in the status of an auth code request
+type StatusTransition struct {
+	From string     `json:"from"`
+	To   string     `json:"to"`
+	At   *time.Time `json:"at"`
+}
+
 // AuthCodeRequestResourceLinks is the links object of the auth code resource
 type AuthCodeRequestResourceLinks struct {
 	Self string `json:"self"`
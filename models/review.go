@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+)
+
+// HoldReason is a machine readable reason why a submitted auth code request has been held for review
+type HoldReason string
+
+// The reasons a submitted auth code request may be held for review
+const (
+	HoldReasonOfficerRecentlyAppointed HoldReason = "officer-recently-appointed"
+	HoldReasonUserMultipleCompanies    HoldReason = "user-multiple-companies"
+)
+
+// ReviewDecision is the outcome of the review of a held auth code request
+type ReviewDecision string
+
+// The decisions a reviewer may make on a held auth code request
+const (
+	ReviewApproved ReviewDecision = "approved"
+	ReviewRejected ReviewDecision = "rejected"
+)
+
+// ActorDao identifies the user, or API key, which made a change to an auth code request
+type ActorDao struct {
+	ID    string `bson:"id"`
+	Email string `bson:"email,omitempty"`
+}
+
+// ReviewDao records a decision made by a reviewer on a held auth code request
+type ReviewDao struct {
+	Decision   ReviewDecision `bson:"decision"`
+	Reason     string         `bson:"reason,omitempty"`
+	ReviewedBy ActorDao       `bson:"reviewed_by"`
+	ReviewedAt *time.Time     `bson:"reviewed_at"`
+}
+
+// ReviewRequest is the body supplied when approving or rejecting a held auth code request
+type ReviewRequest struct {
+	Reason string `json:"reason"`
+}
+
+// Actor is the user, or API key, which made a change to an auth code request
+type Actor struct {
+	ID    string `json:"id"`
+	Email string `json:"email,omitempty"`
+}
+
+// Review is a decision made by a reviewer on a held auth code request
+type Review struct {
+	Decision   string     `json:"decision"`
+	Reason     string     `json:"reason,omitempty"`
+	ReviewedBy Actor      `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+}
+
+// AdminAuthCodeRequestResponse is an auth code request as seen by an administrator, including the reasons
+// it was held for review and the decisions made on it
+type AdminAuthCodeRequestResponse struct {
+	ID string `json:"id"`
+	AuthCodeRequestResourceResponse
+	HoldReasons      []string         `json:"hold_reasons,omitempty"`
+	Reviews          []Review         `json:"reviews,omitempty"`
+	Resends          []Resend         `json:"resends,omitempty"`
+	LetterEvents     []LetterEvent    `json:"letter_events,omitempty"`
+	LetterDispatches []LetterDispatch `json:"letter_dispatches,omitempty"`
+}
+
+// AdminAuthCodeRequestListResponse is a page of auth code requests as seen by an administrator
+type AdminAuthCodeRequestListResponse struct {
+	ItemsPerPage int                            `json:"items_per_page"`
+	StartIndex   int                            `json:"start_index"`
+	TotalResults int                            `json:"total_results"`
+	Items        []AdminAuthCodeRequestResponse `json:"items"`
+}
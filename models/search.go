@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AuthCodeRequestSearch restricts the auth code requests returned when administrators search across all
+// requests. Empty fields are not used to filter. Dates ranges include their from time and exclude their
+// to time. Only requests after the supplied cursor, in newest first order, are returned.
+type AuthCodeRequestSearch struct {
+	CompanyNumber string
+	UserEmail     string
+	OfficerID     string
+	Status        RequestStatus
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+	After         *AuthCodeRequestCursor
+	Limit         int
+}
+
+// AuthCodeRequestCursor marks a position in the newest first ordering of auth code requests, which is by
+// creation time and then ID
+type AuthCodeRequestCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// NewAuthCodeRequestCursor returns the cursor positioned at the supplied auth code request
+func NewAuthCodeRequestCursor(dao *AuthCodeRequestResourceDao) *AuthCodeRequestCursor {
+	cursor := &AuthCodeRequestCursor{ID: dao.ID}
+	if dao.Data.CreatedAt != nil {
+		cursor.CreatedAt = *dao.Data.CreatedAt
+	}
+	return cursor
+}
+
+// String encodes the cursor as an opaque token which may be supplied to fetch the next page of results
+func (c *AuthCodeRequestCursor) String() string {
+	value := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(value))
+}
+
+// ParseAuthCodeRequestCursor decodes a cursor previously encoded by String
+func ParseAuthCodeRequestCursor(token string) (*AuthCodeRequestCursor, error) {
+	value, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor [%s]", token)
+	}
+
+	parts := strings.SplitN(string(value), "|", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, fmt.Errorf("invalid cursor [%s]", token)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor [%s]", token)
+	}
+
+	return &AuthCodeRequestCursor{CreatedAt: createdAt, ID: parts[1]}, nil
+}
+
+// AdminAuthCodeRequestSearchResponse is a page of the auth code requests matching an administrator's
+// search. NextCursor is supplied when there are further results.
+type AdminAuthCodeRequestSearchResponse struct {
+	ItemsPerPage int                            `json:"items_per_page"`
+	NextCursor   string                         `json:"next_cursor,omitempty"`
+	Items        []AdminAuthCodeRequestResponse `json:"items"`
+}
@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitAuthCodeRequestCursor(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 15, 4, 5, 123000000, time.UTC)
+
+	Convey("cursor round trip", t, func() {
+		cursor := NewAuthCodeRequestCursor(&AuthCodeRequestResourceDao{ID: "abc|123", Data: AuthCodeRequestDataDao{CreatedAt: &createdAt}})
+
+		parsed, err := ParseAuthCodeRequestCursor(cursor.String())
+		So(err, ShouldBeNil)
+		So(parsed.CreatedAt, ShouldEqual, createdAt)
+		So(parsed.ID, ShouldEqual, "abc|123")
+	})
+
+	Convey("invalid cursors", t, func() {
+		for _, token := range []string{"!!!", "bm90LWEtY3Vyc29y", "MjAyNC0wMS0wMlQxNTowNDowNVp8"} {
+			cursor, err := ParseAuthCodeRequestCursor(token)
+			So(cursor, ShouldBeNil)
+			So(err.Error(), ShouldEqual, "invalid cursor ["+token+"]")
+		}
+	})
+}
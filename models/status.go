@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RequestStatus is a stage in the lifecycle of an auth code request
+type RequestStatus string
+
+// The lifecycle of an auth code request. A request is created as pending, and moves through submitting
+// to submitted once its letter has been recorded for dispatch, then to dispatched once the letter has
+// been handed to the AuthCode API. The AuthCode API then reports the letter as printed, posted and, if it
+// could not be delivered, returned. A returned request only moves on when its letter is sent again, which
+// moves it back to submitted. A suspicious submission is held until a reviewer either approves it,
+// returning it to submitting, or rejects it. Cancelled, expired, failed and rejected are final.
+const (
+	StatusPending    RequestStatus = "pending"
+	StatusSubmitting RequestStatus = "submitting"
+	StatusHeld       RequestStatus = "held"
+	StatusSubmitted  RequestStatus = "submitted"
+	StatusDispatched RequestStatus = "dispatched"
+	StatusPrinted    RequestStatus = "printed"
+	StatusPosted     RequestStatus = "posted"
+	StatusReturned   RequestStatus = "returned"
+	StatusCancelled  RequestStatus = "cancelled"
+	StatusExpired    RequestStatus = "expired"
+	StatusFailed     RequestStatus = "failed"
+	StatusRejected   RequestStatus = "rejected"
+)
+
+// statuses lists every stage in the lifecycle of an auth code request
+var statuses = []RequestStatus{
+	StatusPending,
+	StatusSubmitting,
+	StatusHeld,
+	StatusSubmitted,
+	StatusDispatched,
+	StatusPrinted,
+	StatusPosted,
+	StatusReturned,
+	StatusCancelled,
+	StatusExpired,
+	StatusFailed,
+	StatusRejected,
+}
+
+// statusTransitions lists the statuses which a request in each status may move to
+var statusTransitions = map[RequestStatus][]RequestStatus{
+	StatusPending:    {StatusSubmitting, StatusCancelled, StatusExpired},
+	StatusSubmitting: {StatusPending, StatusHeld, StatusSubmitted, StatusFailed},
+	StatusHeld:       {StatusSubmitting, StatusRejected},
+	StatusSubmitted:  {StatusDispatched, StatusFailed, StatusPrinted, StatusPosted, StatusReturned},
+	StatusDispatched: {StatusPrinted, StatusPosted, StatusReturned},
+	StatusPrinted:    {StatusPosted, StatusReturned},
+	StatusPosted:     {StatusReturned},
+	// late or repeated letter events never move a returned request on, only sending the letter again does
+	StatusReturned: {StatusSubmitted},
+}
+
+// SubmittedStatuses are the statuses of requests for which a letter has been requested, and which
+// therefore count towards the submission limits
+var SubmittedStatuses = []RequestStatus{StatusSubmitted, StatusDispatched, StatusPrinted, StatusPosted, StatusReturned}
+
+// ResendableStatuses are the statuses of requests whose letter has been handed to the AuthCode API, so
+// may be sent again. The letter of a submitted request is still waiting in the outbox, so it is not.
+var ResendableStatuses = []RequestStatus{StatusDispatched, StatusPrinted, StatusPosted, StatusReturned}
+
+// OpenStatuses are the statuses of requests which may still be submitted, are being submitted or
+// reviewed, or whose letter is waiting in the outbox to be dispatched. The personal data of open requests
+// is still needed, so is not subject to data retention.
+var OpenStatuses = []RequestStatus{StatusPending, StatusSubmitting, StatusHeld, StatusSubmitted}
+
+// InFlightStatuses are the statuses of requests which are being submitted or reviewed, or whose letter is
+// waiting in the outbox to be dispatched, so whose letter may yet be sent. Their personal data cannot be
+// erased until they are closed.
+var InFlightStatuses = []RequestStatus{StatusSubmitting, StatusHeld, StatusSubmitted}
+
+// IsValid returns whether the status is a stage in the lifecycle of an auth code request
+func (s RequestStatus) IsValid() bool {
+	for _, status := range statuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// CanTransitionTo returns whether a request in this status may move to the supplied status
+func (s RequestStatus) CanTransitionTo(to RequestStatus) bool {
+	for _, status := range statusTransitions[s] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSubmitted returns whether a letter has been requested for a request in this status
+func (s RequestStatus) IsSubmitted() bool {
+	for _, status := range SubmittedStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsResendable returns whether the letter for a request in this status may be sent again
+func (s RequestStatus) IsResendable() bool {
+	for _, status := range ResendableStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// IsInFlight returns whether a request in this status is being submitted or reviewed, or has a letter
+// waiting to be dispatched
+func (s RequestStatus) IsInFlight() bool {
+	for _, status := range InFlightStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransitionDao records a change in the status of an auth code request
+type StatusTransitionDao struct {
+	From RequestStatus `bson:"from"`
+	To   RequestStatus `bson:"to"`
+	At   *time.Time    `bson:"at"`
+}
+
+// NewStatusTransition returns a transition between the supplied statuses, timestamped now, or an
+// error if the request lifecycle does not permit it
+func NewStatusTransition(from, to RequestStatus) (*StatusTransitionDao, error) {
+	if !from.CanTransitionTo(to) {
+		return nil, fmt.Errorf("invalid status transition from [%s] to [%s]", from, to)
+	}
+
+	at := time.Now().Truncate(time.Millisecond)
+
+	return &StatusTransitionDao{
+		From: from,
+		To:   to,
+		At:   &at,
+	}, nil
+}
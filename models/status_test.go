@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitIsValid(t *testing.T) {
+	Convey("request statuses", t, func() {
+		So(StatusPending.IsValid(), ShouldBeTrue)
+		So(StatusFailed.IsValid(), ShouldBeTrue)
+		So(RequestStatus("unknown").IsValid(), ShouldBeFalse)
+		So(RequestStatus("").IsValid(), ShouldBeFalse)
+	})
+}
+
+func TestUnitCanTransitionTo(t *testing.T) {
+	Convey("request lifecycle transitions", t, func() {
+		So(StatusPending.CanTransitionTo(StatusSubmitting), ShouldBeTrue)
+		So(StatusPending.CanTransitionTo(StatusCancelled), ShouldBeTrue)
+		So(StatusSubmitting.CanTransitionTo(StatusPending), ShouldBeTrue)
+		So(StatusSubmitting.CanTransitionTo(StatusSubmitted), ShouldBeTrue)
+		So(StatusSubmitted.CanTransitionTo(StatusDispatched), ShouldBeTrue)
+		So(StatusSubmitting.CanTransitionTo(StatusHeld), ShouldBeTrue)
+		So(StatusHeld.CanTransitionTo(StatusSubmitting), ShouldBeTrue)
+		So(StatusHeld.CanTransitionTo(StatusRejected), ShouldBeTrue)
+		So(StatusDispatched.CanTransitionTo(StatusPrinted), ShouldBeTrue)
+		So(StatusSubmitted.CanTransitionTo(StatusPosted), ShouldBeTrue)
+		So(StatusPrinted.CanTransitionTo(StatusReturned), ShouldBeTrue)
+		So(StatusReturned.CanTransitionTo(StatusSubmitted), ShouldBeTrue)
+
+		So(StatusPending.CanTransitionTo(StatusSubmitted), ShouldBeFalse)
+		So(StatusPending.CanTransitionTo(StatusPending), ShouldBeFalse)
+		So(StatusSubmitted.CanTransitionTo(StatusCancelled), ShouldBeFalse)
+		So(StatusDispatched.CanTransitionTo(StatusPending), ShouldBeFalse)
+		So(StatusCancelled.CanTransitionTo(StatusSubmitting), ShouldBeFalse)
+		So(StatusHeld.CanTransitionTo(StatusCancelled), ShouldBeFalse)
+		So(StatusRejected.CanTransitionTo(StatusSubmitting), ShouldBeFalse)
+		So(StatusPosted.CanTransitionTo(StatusPrinted), ShouldBeFalse)
+		So(StatusReturned.CanTransitionTo(StatusCancelled), ShouldBeFalse)
+		So(StatusReturned.CanTransitionTo(StatusPrinted), ShouldBeFalse)
+		So(StatusReturned.CanTransitionTo(StatusPosted), ShouldBeFalse)
+		So(RequestStatus("unknown").CanTransitionTo(StatusSubmitting), ShouldBeFalse)
+	})
+}
+
+func TestUnitIsResendable(t *testing.T) {
+	Convey("resendable statuses", t, func() {
+		So(StatusDispatched.IsResendable(), ShouldBeTrue)
+		So(StatusPrinted.IsResendable(), ShouldBeTrue)
+		So(StatusPosted.IsResendable(), ShouldBeTrue)
+		So(StatusReturned.IsResendable(), ShouldBeTrue)
+
+		So(StatusSubmitted.IsResendable(), ShouldBeFalse)
+		So(StatusPending.IsResendable(), ShouldBeFalse)
+		So(StatusFailed.IsResendable(), ShouldBeFalse)
+	})
+}
+
+func TestUnitIsInFlight(t *testing.T) {
+	Convey("in flight statuses", t, func() {
+		So(StatusSubmitting.IsInFlight(), ShouldBeTrue)
+		So(StatusHeld.IsInFlight(), ShouldBeTrue)
+		So(StatusSubmitted.IsInFlight(), ShouldBeTrue)
+
+		So(StatusPending.IsInFlight(), ShouldBeFalse)
+		So(StatusDispatched.IsInFlight(), ShouldBeFalse)
+		So(StatusCancelled.IsInFlight(), ShouldBeFalse)
+	})
+}
+
+func TestUnitNewStatusTransition(t *testing.T) {
+	Convey("invalid transition", t, func() {
+		transition, err := NewStatusTransition(StatusExpired, StatusSubmitting)
+		So(transition, ShouldBeNil)
+		So(err.Error(), ShouldEqual, "invalid status transition from [expired] to [submitting]")
+	})
+
+	Convey("valid transition", t, func() {
+		transition, err := NewStatusTransition(StatusSubmitted, StatusDispatched)
+		So(err, ShouldBeNil)
+		So(transition.From, ShouldEqual, StatusSubmitted)
+		So(transition.To, ShouldEqual, StatusDispatched)
+		So(transition.At, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,102 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/transformers"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// systemActorID identifies the service itself as the actor for changes made by background jobs
+const systemActorID = "emergency-auth-code-api"
+
+// GetAuditTrail returns the changes made to an authcode request, oldest first. NotFound is returned if
+// there is no such request.
+func (s *AuthCodeRequestService) GetAuditTrail(authCodeRequestID string) (*models.AuditTrailResponse, ResponseType) {
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID}
+
+	entries, err := s.AuditDAO.ListAuditEntries(authCodeRequestID)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing authcode request audit entries: %v", err), logContext)
+		return nil, Error
+	}
+
+	// requests created before the audit trail was introduced have no entries
+	if len(entries) == 0 {
+		authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestID)
+		if err != nil {
+			log.Error(fmt.Errorf("error getting authcode request: %v", err), logContext)
+			return nil, Error
+		}
+		if authCodeRequest == nil {
+			return nil, NotFound
+		}
+	}
+
+	return transformers.AuditEntryDaoListToResponse(authCodeRequestID, entries), Success
+}
+
+// recordAudit appends a change made by the requester to the audit trail of an authcode request
+func (s *AuthCodeRequestService) recordAudit(authCodeRequestID string, requester *Requester, action models.AuditAction, before, after *models.AuditStateDao) {
+	recordAuditEntry(s.AuditDAO, newAuditEntry(authCodeRequestID, requester, action, before, after))
+}
+
+// recordAuditEntry appends an entry to the audit trail. The change being audited has already been made,
+// so a failure to record it is logged rather than returned.
+func recordAuditEntry(auditDAO dao.AuthcodeAuditDAOService, entry *models.AuditEntryDao) {
+	if auditDAO == nil {
+		return
+	}
+
+	if err := auditDAO.InsertAuditEntry(entry); err != nil {
+		log.Error(fmt.Errorf("error recording authcode request audit entry: %v", err), log.Data{
+			"auth_code_request_id": entry.AuthCodeRequestID,
+			"action":               entry.Action,
+		})
+	}
+}
+
+// newAuditEntry returns the audit entry for a change made now by the requester. Changes made without a
+// requester are attributed to the service itself.
+func newAuditEntry(authCodeRequestID string, requester *Requester, action models.AuditAction, before, after *models.AuditStateDao) *models.AuditEntryDao {
+	at := time.Now().Truncate(time.Millisecond)
+
+	entry := &models.AuditEntryDao{
+		ID:                utils.GenerateID(),
+		AuthCodeRequestID: authCodeRequestID,
+		Action:            action,
+		Actor:             requester.actor(),
+		ActorType:         requester.actorType(),
+		Before:            before,
+		After:             after,
+		At:                &at,
+	}
+
+	if requester == nil {
+		entry.Actor.ID = systemActorID
+	} else {
+		entry.RequestID = requester.RequestID
+	}
+
+	return entry
+}
+
+// auditState returns the audited fields of an authcode request
+func auditState(authCodeReqDao *models.AuthCodeRequestResourceDao) *models.AuditStateDao {
+	return &models.AuditStateDao{
+		Status:          authCodeReqDao.Data.Status,
+		OfficerID:       authCodeReqDao.Data.OfficerID,
+		OfficerUraID:    authCodeReqDao.Data.OfficerUraID,
+		OfficerForename: authCodeReqDao.Data.OfficerForename,
+		OfficerSurname:  authCodeReqDao.Data.OfficerSurname,
+	}
+}
+
+// statusState returns the audited state of an authcode request whose status alone has changed
+func statusState(status models.RequestStatus) *models.AuditStateDao {
+	return &models.AuditStateDao{Status: status}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/oracle"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRecordAudit(t *testing.T) {
+	Convey("Record audit of authcode request changes", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, AuditDAO: mockAuditService}
+
+		requester := &Requester{UserID: testUserID, Email: "test@test.com", RequestID: "request-id"}
+
+		var auditEntry *models.AuditEntryDao
+		recordEntry := func(entry *models.AuditEntryDao) error {
+			auditEntry = entry
+			return nil
+		}
+
+		Convey("officer updated", func() {
+			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(nil)
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(recordEntry)
+
+			authCodeReq := models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{
+				Status:          models.StatusPending,
+				Etag:            "etag1",
+				OfficerID:       "old",
+				OfficerForename: "Old",
+				OfficerSurname:  "Officer",
+			}}
+			officer := oracle.Officer{ID: "new", Forename: "New", Surname: "Officer"}
+
+			So(svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer, requester), ShouldEqual, Success)
+			So(auditEntry.AuthCodeRequestID, ShouldEqual, authCodeRequestID)
+			So(auditEntry.Action, ShouldEqual, models.AuditActionOfficerUpdated)
+			So(auditEntry.Actor, ShouldResemble, models.ActorDao{ID: testUserID, Email: "test@test.com"})
+			So(auditEntry.ActorType, ShouldEqual, models.ActorTypeUser)
+			So(auditEntry.RequestID, ShouldEqual, "request-id")
+			So(auditEntry.At, ShouldNotBeNil)
+			So(auditEntry.Before, ShouldResemble, &models.AuditStateDao{Status: models.StatusPending, OfficerID: "old", OfficerForename: "Old", OfficerSurname: "Officer"})
+			So(auditEntry.After, ShouldResemble, &models.AuditStateDao{Status: models.StatusPending, OfficerID: "new", OfficerForename: "New", OfficerSurname: "Officer"})
+		})
+
+		Convey("submission started by API key", func() {
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(true, nil)
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(recordEntry)
+
+			requester.APIKey = true
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", requester), ShouldEqual, Success)
+			So(auditEntry.Action, ShouldEqual, models.AuditActionSubmissionStarted)
+			So(auditEntry.ActorType, ShouldEqual, models.ActorTypeAPIKey)
+			So(auditEntry.Before, ShouldResemble, &models.AuditStateDao{Status: models.StatusPending})
+			So(auditEntry.After, ShouldResemble, &models.AuditStateDao{Status: models.StatusSubmitting})
+		})
+
+		Convey("change not made is not audited", func() {
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(false, nil)
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", requester), ShouldEqual, Conflict)
+		})
+
+		Convey("error recording audit entry does not fail the change", func() {
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitting, models.StatusPending).Return(true, nil)
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).Return(fmt.Errorf("error"))
+
+			So(svc.AbortAuthCodeRequestSubmission(authCodeRequestID, requester), ShouldEqual, Success)
+		})
+	})
+}
+
+func TestUnitGetAuditTrail(t *testing.T) {
+	Convey("Get authcode request audit trail", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, AuditDAO: mockAuditService}
+
+		Convey("error listing audit entries", func() {
+			mockAuditService.EXPECT().ListAuditEntries(authCodeRequestID).Return(nil, fmt.Errorf("error"))
+
+			_, responseType := svc.GetAuditTrail(authCodeRequestID)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("no audit entries and no request", func() {
+			mockAuditService.EXPECT().ListAuditEntries(authCodeRequestID).Return([]models.AuditEntryDao{}, nil)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(nil, nil)
+
+			_, responseType := svc.GetAuditTrail(authCodeRequestID)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("no audit entries for existing request", func() {
+			mockAuditService.EXPECT().ListAuditEntries(authCodeRequestID).Return([]models.AuditEntryDao{}, nil)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(&models.AuthCodeRequestResourceDao{ID: authCodeRequestID}, nil)
+
+			auditTrail, responseType := svc.GetAuditTrail(authCodeRequestID)
+			So(responseType, ShouldEqual, Success)
+			So(auditTrail.Items, ShouldBeEmpty)
+		})
+
+		Convey("audit entries returned", func() {
+			mockAuditService.EXPECT().ListAuditEntries(authCodeRequestID).Return([]models.AuditEntryDao{
+				{ID: "1", Action: models.AuditActionCreated, Actor: models.ActorDao{ID: testUserID}, ActorType: models.ActorTypeUser, After: &models.AuditStateDao{Status: models.StatusPending}},
+				{ID: "2", Action: models.AuditActionCancelled, Actor: models.ActorDao{ID: testUserID}, ActorType: models.ActorTypeUser, Before: &models.AuditStateDao{Status: models.StatusPending}, After: &models.AuditStateDao{Status: models.StatusCancelled}},
+			}, nil)
+
+			auditTrail, responseType := svc.GetAuditTrail(authCodeRequestID)
+			So(responseType, ShouldEqual, Success)
+			So(auditTrail.AuthCodeRequestID, ShouldEqual, authCodeRequestID)
+			So(auditTrail.Items, ShouldHaveLength, 2)
+			So(auditTrail.Items[0].Action, ShouldEqual, "created")
+			So(auditTrail.Items[0].Before, ShouldBeNil)
+			So(auditTrail.Items[1].Before.Status, ShouldEqual, "pending")
+			So(auditTrail.Items[1].After.Status, ShouldEqual, "cancelled")
+		})
+	})
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/companieshouse/chs.go/log"
-	"github.com/companieshouse/emergency-auth-code-api/authcodeapi"
 	"github.com/companieshouse/emergency-auth-code-api/config"
 	"github.com/companieshouse/emergency-auth-code-api/dao"
 	"github.com/companieshouse/emergency-auth-code-api/models"
@@ -14,27 +13,37 @@ import (
 	"github.com/companieshouse/emergency-auth-code-api/transformers"
 )
 
-const submitted = "submitted"
-
-// AuthCodeRequestService contains the DAO for db access
+// AuthCodeRequestService contains the DAO for db access. Changes made to auth code requests are recorded
+// in the audit trail, if an audit DAO is supplied.
 type AuthCodeRequestService struct {
-	DAO    dao.AuthcodeRequestDAOService
-	Config *config.Config
+	DAO      dao.AuthcodeRequestDAOService
+	AuditDAO dao.AuthcodeAuditDAOService
+	Config   *config.Config
+	Policy   *SubmissionPolicy
 }
 
-// CreateAuthCodeRequest insert an auth code request into the database
-func (s *AuthCodeRequestService) CreateAuthCodeRequest(requestDao *models.AuthCodeRequestResourceDao) error {
+// CreateAuthCodeRequest insert an auth code request into the database. ErrIdempotencyKeyUsed is returned
+// if the request has an idempotency key which the user has already used.
+func (s *AuthCodeRequestService) CreateAuthCodeRequest(requestDao *models.AuthCodeRequestResourceDao, requester *Requester) error {
+	requestDao.Data.CompanyNumber = NormaliseCompanyNumber(requestDao.Data.CompanyNumber)
 
 	err := s.DAO.InsertAuthCodeRequest(requestDao)
+	if err == dao.ErrDuplicateIdempotencyKey {
+		return ErrIdempotencyKeyUsed
+	}
 	if err != nil {
-		err = fmt.Errorf("error creating AuthCode request: [%v]", err)
+		return fmt.Errorf("error creating AuthCode request: [%v]", err)
 	}
 
-	return err
+	s.recordAudit(requestDao.ID, requester, models.AuditActionCreated, nil, auditState(requestDao))
+
+	return nil
 }
 
-// GetAuthCodeRequest returns an auth code request from the database
-func (s *AuthCodeRequestService) GetAuthCodeRequest(authCodeRequestId string) (*models.AuthCodeRequestResourceResponse, int) {
+// GetAuthCodeRequest returns an auth code request from the database. A request
+// which the requester is not permitted to access is reported as not found, and
+// one which has expired as gone.
+func (s *AuthCodeRequestService) GetAuthCodeRequest(authCodeRequestId string, requester *Requester) (*models.AuthCodeRequestResourceResponse, int) {
 	authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestId)
 	if err != nil {
 		return nil, http.StatusInternalServerError
@@ -43,12 +52,41 @@ func (s *AuthCodeRequestService) GetAuthCodeRequest(authCodeRequestId string) (*
 		return nil, http.StatusNotFound
 	}
 
+	if !requester.CanAccess(authCodeRequest) {
+		log.Info("auth code request not accessible by requester", log.Data{"auth_code_request_id": authCodeRequestId})
+		return nil, http.StatusNotFound
+	}
+
+	if authCodeRequest.Data.Status == models.StatusExpired {
+		return nil, http.StatusGone
+	}
+
 	return transformers.AuthCodeRequestResourceDaoToResponse(authCodeRequest), http.StatusOK
 }
 
-// UpdateAuthCodeRequestOfficer updates the officer details in an authcode request
+// ListAuthCodeRequests returns a page of the auth code requests created by the requester, newest first,
+// restricted by the status and company number in the supplied filter
+func (s *AuthCodeRequestService) ListAuthCodeRequests(requester *Requester, filter models.AuthCodeRequestFilter, startIndex, itemsPerPage int) (*models.AuthCodeRequestListResponse, ResponseType) {
+	if requester == nil || requester.UserID == "" {
+		return nil, InvalidData
+	}
+	filter.UserID = requester.UserID
+	filter.CompanyNumber = NormaliseCompanyNumber(filter.CompanyNumber)
+
+	authCodeRequests, totalResults, err := s.DAO.ListAuthCodeRequests(filter, startIndex, itemsPerPage)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing authcode requests: %v", err), log.Data{"user_id": requester.UserID})
+		return nil, Error
+	}
+
+	return transformers.AuthCodeRequestResourceDaoListToResponse(authCodeRequests, startIndex, itemsPerPage, totalResults), Success
+}
+
+// UpdateAuthCodeRequestOfficer updates the officer details in an authcode request. The update is only
+// applied if the request has not been modified since the supplied dao was read, and PreconditionFailed
+// is returned if it has. NotFound is returned if the request has since been deleted.
 func (s *AuthCodeRequestService) UpdateAuthCodeRequestOfficer(
-	authCodeReqDao *models.AuthCodeRequestResourceDao, authCodeRequestID string, officer *oracle.Officer) ResponseType {
+	authCodeReqDao *models.AuthCodeRequestResourceDao, authCodeRequestID string, officer *oracle.Officer, requester *Requester) ResponseType {
 
 	requestDao := models.AuthCodeRequestResourceDao{
 		ID: authCodeRequestID,
@@ -60,55 +98,224 @@ func (s *AuthCodeRequestService) UpdateAuthCodeRequestOfficer(
 		},
 	}
 
-	err := s.DAO.UpdateAuthCodeRequestOfficer(&requestDao)
+	err := s.DAO.UpdateAuthCodeRequestOfficer(&requestDao, authCodeReqDao.Data.Etag)
+	if err == dao.ErrEtagMismatch {
+		log.Info("authcode request modified since it was read so officer not updated", log.Data{"auth_code_request_id": authCodeRequestID})
+		return PreconditionFailed
+	}
+	if err == dao.ErrNotFound {
+		log.Info("authcode request deleted since it was read so officer not updated", log.Data{"auth_code_request_id": authCodeRequestID})
+		return NotFound
+	}
 	if err != nil {
 		return Error
 	}
 
+	before := auditState(authCodeReqDao)
+
+	authCodeReqDao.Data.Etag = requestDao.Data.Etag
 	authCodeReqDao.Data.OfficerID = officer.ID
 	authCodeReqDao.Data.OfficerUraID = officer.UsualResidentialAddress.ID
 	authCodeReqDao.Data.OfficerForename = officer.Forename
 	authCodeReqDao.Data.OfficerSurname = officer.Surname
 
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionOfficerUpdated, before, auditState(authCodeReqDao))
+
 	return Success
 }
 
-// UpdateAuthCodeRequestStatusSubmitted updates the status in an submitted authcode request
-func (s *AuthCodeRequestService) UpdateAuthCodeRequestStatusSubmitted(authCodeReqDao *models.AuthCodeRequestResourceDao, authCodeRequestID string, companyHasAuthCode bool) ResponseType {
+// UpdateAuthCodeRequestStatusSubmitted updates the status in an submitted authcode request. The supplied
+// outbox items are recorded in the same write, so they are only dispatched if the status is updated.
+func (s *AuthCodeRequestService) UpdateAuthCodeRequestStatusSubmitted(authCodeReqDao *models.AuthCodeRequestResourceDao, authCodeRequestID string, companyHasAuthCode bool, outboxItems []models.OutboxItemDao, requester *Requester) ResponseType {
 
 	submittedAt := time.Now().Truncate(time.Millisecond)
 
 	requestDao := models.AuthCodeRequestResourceDao{
 		ID: authCodeRequestID,
 		Data: models.AuthCodeRequestDataDao{
-			Status:      submitted,
+			Status:      models.StatusSubmitted,
 			Type:        getLetterType(companyHasAuthCode),
 			SubmittedAt: &submittedAt,
 		},
 	}
 
-	err := s.DAO.UpdateAuthCodeRequestStatus(&requestDao)
+	err := s.DAO.UpdateAuthCodeRequestStatus(&requestDao, models.StatusSubmitting, outboxItems)
+	if err != nil {
+		log.Error(fmt.Errorf("error updating authcode request status: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+		return Error
+	}
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionSubmitted, statusState(models.StatusSubmitting), statusState(models.StatusSubmitted))
+
+	return Success
+}
+
+// StartAuthCodeRequestSubmission moves a pending authcode request into the submitting status before
+// a letter is sent, provided it still has the etag it was read with. Conflict is returned if the request
+// is no longer pending, which will be the case when another caller has already started submitting it,
+// and PreconditionFailed if it is but has been modified since it was read.
+func (s *AuthCodeRequestService) StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag string, requester *Requester) ResponseType {
+	transitioned, err := s.DAO.StartAuthCodeRequestSubmission(authCodeRequestID, expectedEtag)
+	if err == dao.ErrEtagMismatch {
+		log.Info("authcode request modified since it was read so not submitted", log.Data{"auth_code_request_id": authCodeRequestID})
+		return PreconditionFailed
+	}
+	if err != nil {
+		log.Error(fmt.Errorf("error starting submission of authcode request: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+		return Error
+	}
+
+	if !transitioned {
+		log.Info("authcode request is not pending so cannot be submitted", log.Data{"auth_code_request_id": authCodeRequestID})
+		return Conflict
+	}
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionSubmissionStarted, statusState(models.StatusPending), statusState(models.StatusSubmitting))
+
+	return Success
+}
+
+// AbortAuthCodeRequestSubmission returns a submitting authcode request to pending, so that it can be
+// submitted again once a failure sending the letter has been resolved
+func (s *AuthCodeRequestService) AbortAuthCodeRequestSubmission(authCodeRequestID string, requester *Requester) ResponseType {
+	transitioned, err := s.DAO.TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitting, models.StatusPending)
+	if err != nil {
+		log.Error(fmt.Errorf("error aborting submission of authcode request: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+		return Error
+	}
+
+	if !transitioned {
+		return Conflict
+	}
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionSubmissionAborted, statusState(models.StatusSubmitting), statusState(models.StatusPending))
+
+	return Success
+}
+
+// CancelAuthCodeRequest withdraws a pending authcode request, so that it can no longer be submitted.
+// A request which the requester is not permitted to access is reported as not found, and Conflict is
+// returned if the request has already moved beyond pending. Cancelling a cancelled request succeeds.
+func (s *AuthCodeRequestService) CancelAuthCodeRequest(authCodeRequestID string, requester *Requester) ResponseType {
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID}
+
+	authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestID)
+	if err != nil {
+		log.Error(fmt.Errorf("error getting authcode request to cancel: %v", err), logContext)
+		return Error
+	}
+	if authCodeRequest == nil || !requester.CanAccess(authCodeRequest) {
+		return NotFound
+	}
+
+	if authCodeRequest.Data.Status == models.StatusCancelled {
+		return Success
+	}
+
+	if !authCodeRequest.Data.Status.CanTransitionTo(models.StatusCancelled) {
+		log.Info(fmt.Sprintf("authcode request is %s so cannot be cancelled", authCodeRequest.Data.Status), logContext)
+		return Conflict
+	}
+
+	transitioned, err := s.DAO.TransitionAuthCodeRequestStatus(authCodeRequestID, authCodeRequest.Data.Status, models.StatusCancelled)
 	if err != nil {
+		log.Error(fmt.Errorf("error cancelling authcode request: %v", err), logContext)
 		return Error
 	}
 
+	// the request has been submitted since it was read
+	if !transitioned {
+		log.Info("authcode request status changed so cannot be cancelled", logContext)
+		return Conflict
+	}
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionCancelled, statusState(authCodeRequest.Data.Status), statusState(models.StatusCancelled))
+
 	return Success
 }
 
-// SendAuthCodeRequest sends a letter item to the AuthCode API
-func (s *AuthCodeRequestService) SendAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, companyNumber, userEmail, authCodeRequestID string, companyHasAuthCode bool) ResponseType {
+// SendAuthCodeRequest submits an authcode request. The letter item for the AuthCode API and the
+// confirmation email are recorded in the outbox in the same write that marks the request as
+// submitted, and are then delivered by the OutboxDispatcher. A submission which the submission
+// policy considers suspicious is instead held for manual review, and nothing is recorded for dispatch
+// until it is approved. Forbidden is returned, along with the reason, if a submission limit has been
+// reached.
+func (s *AuthCodeRequestService) SendAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, companyNumber, userEmail, authCodeRequestID string, companyHasAuthCode bool, requester *Requester) (*models.EligibilityFailureResponse, ResponseType) {
+	return s.sendAuthCodeRequest(authCodeReqDao, companyNumber, userEmail, authCodeRequestID, companyHasAuthCode, requester, sendOptions{reviewable: true})
+}
+
+// sendOptions vary how a letter is sent for an authcode request
+type sendOptions struct {
+	// reviewable submissions are held for review if the submission policy requires it
+	reviewable bool
+	// resend, if supplied, sends the letter again for a request which has already been submitted,
+	// recording the resend in its history instead of submitting it
+	resend *models.ResendDao
+	// overrideLimits sends the letter even if a submission limit has been reached, which an operator
+	// must have explicitly asked for
+	overrideLimits bool
+}
+
+// sendAuthCodeRequest records the letter for an authcode request for dispatch, on behalf of the requester.
+// Unless they are overridden, the submission limits are checked first, and Forbidden is returned along with
+// the reason if one has been reached.
+func (s *AuthCodeRequestService) sendAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, companyNumber, userEmail, authCodeRequestID string, companyHasAuthCode bool, requester *Requester, opts sendOptions) (*models.EligibilityFailureResponse, ResponseType) {
+	if !opts.overrideLimits {
+		limitFailure, err := s.CheckSubmissionLimits(authCodeReqDao)
+		if err != nil {
+			return nil, Error
+		}
+		if limitFailure != nil {
+			log.Info("submission limit reached so letter not sent", log.Data{"auth_code_request_id": authCodeRequestID, "reason": limitFailure.Code})
+			return limitFailure, Forbidden
+		}
+	}
+
 	// get Officer residential address
 	companyOfficer, responseType, err := GetOfficerDetails(companyNumber, authCodeReqDao.Data.OfficerID)
 	if err != nil || responseType == Error {
 		log.Error(fmt.Errorf("error calling Oracle API to get officer: %v", err))
-		return Error
+		return nil, Error
 	}
 
 	if responseType == NotFound {
 		log.Error(fmt.Errorf("officer not found"))
-		return NotFound
+		return nil, NotFound
+	}
+
+	if opts.reviewable {
+		holdReasons, err := s.getHoldReasons(companyOfficer, authCodeReqDao.Data.CreatedBy.ID, userEmail)
+		if err != nil {
+			return nil, Error
+		}
+		if len(holdReasons) > 0 {
+			return nil, s.holdAuthCodeRequest(authCodeReqDao, authCodeRequestID, holdReasons, requester)
+		}
+	}
+
+	letterType := getLetterType(companyHasAuthCode)
+	log.Info(fmt.Sprintf("company[%s] lettertype [%s]", companyNumber, letterType))
+
+	letterItem := newOutboxItem(authCodeRequestID, models.OutboxTypeLetter)
+	letterItem.AuthCodeItem = newAuthCodeItem(companyOfficer, companyNumber, userEmail, letterType)
+
+	if opts.resend != nil {
+		return nil, s.recordResend(authCodeRequestID, authCodeReqDao.Data.Status, *opts.resend, letterItem, requester)
+	}
+
+	emailSend, err := NewConfirmationEmail(userEmail)
+	if err != nil {
+		log.Error(err)
+		return nil, Error
 	}
+	emailItem := newOutboxItem(authCodeRequestID, models.OutboxTypeEmail)
+	emailItem.EmailSend = emailSend
+
+	return nil, s.UpdateAuthCodeRequestStatusSubmitted(authCodeReqDao, authCodeRequestID, companyHasAuthCode, []models.OutboxItemDao{letterItem, emailItem}, requester)
+}
 
+// newAuthCodeItem builds the item sent to the AuthCode API to request a letter for the supplied officer
+func newAuthCodeItem(companyOfficer *oracle.Officer, companyNumber, userEmail, letterType string) *models.AuthCodeItem {
 	var officerName string
 	if companyOfficer.Forename != "" {
 		officerName = fmt.Sprintf("%s %s", companyOfficer.Forename, companyOfficer.Surname)
@@ -116,10 +323,7 @@ func (s *AuthCodeRequestService) SendAuthCodeRequest(authCodeReqDao *models.Auth
 		officerName = companyOfficer.Surname
 	}
 
-	letterType := getLetterType(companyHasAuthCode)
-	log.Info(fmt.Sprintf("company[%s] lettertype [%s]", companyNumber, letterType))
-
-	AuthCodeItem := models.AuthCodeItem{
+	return &models.AuthCodeItem{
 		Type:          "authcode_put",
 		Email:         userEmail,
 		CompanyNumber: companyNumber,
@@ -137,43 +341,11 @@ func (s *AuthCodeRequestService) SendAuthCodeRequest(authCodeReqDao *models.Auth
 		},
 		Status: letterType,
 	}
-
-	err = sendAuthCodeAPI(
-		s.Config,
-		&AuthCodeItem,
-		authCodeRequestID,
-	)
-
-	if err != nil {
-		log.Error(err)
-		return Error
-	}
-
-	return Success
 }
 
-func sendAuthCodeAPI(cfg *config.Config, item *models.AuthCodeItem, authCodeRequestID string) (err error) {
-	// determine which authcode path we shoulbe be using
-	// by interrogating NewAuthCodeAPIFlow config flag
-	var authCodeURL, authCodePath string
-	if cfg.NewAuthCodeAPIFlow {
-		authCodeURL = cfg.AuthCodeAPILocalURL
-		authCodePath = fmt.Sprintf(cfg.AuthCodeAPILocalPath, item.CompanyNumber)
-	} else {
-		authCodeURL = cfg.QueueAPILocalURL
-		authCodePath = cfg.QueueAPILocalPath
-	}
-	client := authcodeapi.NewClient(
-		authCodeURL,
-		authCodePath,
-		cfg.APIKey,
-	)
-	err = client.SendAuthCodeItem(item, authCodeRequestID)
-	return err
-}
-
-// GetAuthCodeReqDao returns an authcode request db object
-func (s *AuthCodeRequestService) GetAuthCodeReqDao(authCodeRequestID, companyNumber string) (*models.AuthCodeRequestResourceDao, ResponseType) {
+// GetAuthCodeReqDao returns an authcode request db object. A request which the
+// requester is not permitted to access is reported as not found.
+func (s *AuthCodeRequestService) GetAuthCodeReqDao(authCodeRequestID, companyNumber string, requester *Requester) (*models.AuthCodeRequestResourceDao, ResponseType) {
 	authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestID)
 	if err != nil {
 		return nil, Error
@@ -182,7 +354,12 @@ func (s *AuthCodeRequestService) GetAuthCodeReqDao(authCodeRequestID, companyNum
 		return nil, NotFound
 	}
 
-	if authCodeRequest.Data.CompanyNumber != companyNumber {
+	if !requester.CanAccess(authCodeRequest) {
+		log.Info("auth code request not accessible by requester", log.Data{"auth_code_request_id": authCodeRequestID})
+		return nil, NotFound
+	}
+
+	if authCodeRequest.Data.CompanyNumber != NormaliseCompanyNumber(companyNumber) {
 		return nil, InvalidData
 	}
 
@@ -196,28 +373,47 @@ func getLetterType(companyHasAuthCode bool) string {
 	return "apply"
 }
 
-// CheckMultipleCorporateBodySubmissions calls the DB to check for multiple company submissions
-func (s *AuthCodeRequestService) CheckMultipleCorporateBodySubmissions(companyNumber string) (bool, error) {
-
-	multipleSubmissions, err := s.DAO.CheckMultipleCorporateBodySubmissions(companyNumber)
+// CheckMultipleCorporateBodySubmissions calls the DB to count the submissions for a company, returning the
+// time at which the company may next submit a request if the submission policy does not permit another
+func (s *AuthCodeRequestService) CheckMultipleCorporateBodySubmissions(companyNumber string) (*time.Time, error) {
+	companyNumber = NormaliseCompanyNumber(companyNumber)
+	policy := s.submissionPolicy()
+	if policy.IsCompanyExempt(companyNumber) {
+		return nil, nil
+	}
 
+	count, err := s.DAO.CountCorporateBodySubmissions(companyNumber, time.Now().Add(-policy.Company.Window), policy.Company.MaxSubmissions)
 	if err != nil {
 		log.Error(fmt.Errorf("error checking corporate body submissions: %v", err))
-		return false, err
+		return nil, err
 	}
 
-	return multipleSubmissions, nil
+	return policy.Company.RetryAfter(count), nil
 }
 
-// CheckMultipleUserSubmissions calls the DB to check for multiple user submissions
-func (s *AuthCodeRequestService) CheckMultipleUserSubmissions(email string) (bool, error) {
-
-	multipleSubmissions, err := s.DAO.CheckMultipleUserSubmissions(email)
+// CheckMultipleUserSubmissions calls the DB to count the submissions by a user, returning the time at which
+// the user may next submit a request if the submission policy does not permit another. Submissions are
+// counted by user ID, while exempt users are configured by email.
+func (s *AuthCodeRequestService) CheckMultipleUserSubmissions(userID, email string) (*time.Time, error) {
+	policy := s.submissionPolicy()
+	if policy.IsUserExempt(email) {
+		return nil, nil
+	}
 
+	count, err := s.DAO.CountUserSubmissions(userID, time.Now().Add(-policy.User.Window), policy.User.MaxSubmissions)
 	if err != nil {
 		log.Error(fmt.Errorf("error checking user submissions: %v", err))
-		return false, err
+		return nil, err
 	}
 
-	return multipleSubmissions, nil
+	return policy.User.RetryAfter(count), nil
+}
+
+// submissionPolicy returns the policy deciding whether requests may be submitted, which is taken from the
+// config if one has not been supplied
+func (s *AuthCodeRequestService) submissionPolicy() *SubmissionPolicy {
+	if s.Policy != nil {
+		return s.Policy
+	}
+	return NewSubmissionPolicy(s.Config)
 }
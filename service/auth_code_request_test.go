@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
 	"github.com/companieshouse/emergency-auth-code-api/mocks"
 	"github.com/companieshouse/emergency-auth-code-api/models"
 	"github.com/companieshouse/emergency-auth-code-api/oracle"
@@ -17,6 +19,7 @@ import (
 const authCodeRequestID = "123"
 const companyNumber = "87654321"
 const testRequestID = "xyz123"
+const testUserID = "user123"
 
 func TestUnitUpdateAuthCodeRequestOfficer(t *testing.T) {
 
@@ -27,29 +30,65 @@ func TestUnitUpdateAuthCodeRequestOfficer(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any()).Return(fmt.Errorf("error"))
+			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
 			authCodeReq := models.AuthCodeRequestResourceDao{}
 			officer := oracle.Officer{}
 
-			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer)
+			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer, nil)
 			So(responseType, ShouldEqual, Error)
 		})
 
-		Convey("officer update - success", func() {
+		Convey("authcode request modified since it was read", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any()).Return(nil)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(dao.ErrEtagMismatch)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			authCodeReq := models.AuthCodeRequestResourceDao{}
+			authCodeReq := models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Etag: "etag1"}}
 			officer := oracle.Officer{}
 
-			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer)
+			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer, nil)
+			So(responseType, ShouldEqual, PreconditionFailed)
+		})
+
+		Convey("authcode request deleted since it was read", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").Return(dao.ErrNotFound)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			authCodeReq := models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Etag: "etag1"}}
+			officer := oracle.Officer{}
+
+			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer, nil)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("officer update - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestOfficer(gomock.Any(), "etag1").DoAndReturn(
+				func(requestDao *models.AuthCodeRequestResourceDao, _ string) error {
+					requestDao.Data.Etag = "etag2"
+					return nil
+				})
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			authCodeReq := models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Etag: "etag1"}}
+			officer := oracle.Officer{ID: "987"}
+
+			responseType := svc.UpdateAuthCodeRequestOfficer(&authCodeReq, authCodeRequestID, &officer, nil)
 			So(responseType, ShouldEqual, Success)
+			So(authCodeReq.Data.Etag, ShouldEqual, "etag2")
+			So(authCodeReq.Data.OfficerID, ShouldEqual, "987")
 		})
 	})
 }
@@ -62,12 +101,12 @@ func TestUnitUpdateAuthCodeRequestStatus(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any()).Return(fmt.Errorf("error"))
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(fmt.Errorf("error"))
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
 			authCodeReq := models.AuthCodeRequestResourceDao{}
 
-			responseType := svc.UpdateAuthCodeRequestStatusSubmitted(&authCodeReq, authCodeRequestID, false)
+			responseType := svc.UpdateAuthCodeRequestStatusSubmitted(&authCodeReq, authCodeRequestID, false, nil, nil)
 			So(responseType, ShouldEqual, Error)
 		})
 
@@ -76,56 +115,235 @@ func TestUnitUpdateAuthCodeRequestStatus(t *testing.T) {
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any()).Return(nil)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
 			authCodeReq := models.AuthCodeRequestResourceDao{}
 
-			responseType := svc.UpdateAuthCodeRequestStatusSubmitted(&authCodeReq, authCodeRequestID, false)
+			responseType := svc.UpdateAuthCodeRequestStatusSubmitted(&authCodeReq, authCodeRequestID, false, nil, nil)
 			So(responseType, ShouldEqual, Success)
 		})
 	})
 }
 
-func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowErrors(t *testing.T) {
-	Convey("send auth code request", t, func() {
-		// build test config
-		cfg, _ := config.Get()
-		cfg.NewAuthCodeAPIFlow = false
-		cfg.QueueAPILocalURL = "http://local.test"
-		cfg.QueueAPILocalPath = "/api/queue/authcode"
+func TestUnitStartAuthCodeRequestSubmission(t *testing.T) {
+	Convey("Start Auth Code Request Submission", t, func() {
 
-		Convey("error getting officer details", func() {
+		Convey("error updating authcode request", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			svc := AuthCodeRequestService{
-				DAO:    mockDaoService,
-				Config: cfg,
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(false, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", nil), ShouldEqual, Error)
+		})
+
+		Convey("request is no longer pending", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(false, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", nil), ShouldEqual, Conflict)
+		})
+
+		Convey("request modified since it was read", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(false, dao.ErrEtagMismatch)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", nil), ShouldEqual, PreconditionFailed)
+		})
+
+		Convey("submission started - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().StartAuthCodeRequestSubmission(authCodeRequestID, "etag1").Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.StartAuthCodeRequestSubmission(authCodeRequestID, "etag1", nil), ShouldEqual, Success)
+		})
+	})
+}
+
+func TestUnitAbortAuthCodeRequestSubmission(t *testing.T) {
+	Convey("Abort Auth Code Request Submission", t, func() {
+
+		Convey("error updating authcode request", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitting, models.StatusPending).Return(false, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.AbortAuthCodeRequestSubmission(authCodeRequestID, nil), ShouldEqual, Error)
+		})
+
+		Convey("submission aborted - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitting, models.StatusPending).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.AbortAuthCodeRequestSubmission(authCodeRequestID, nil), ShouldEqual, Success)
+		})
+	})
+}
+
+func TestUnitCancelAuthCodeRequest(t *testing.T) {
+	Convey("Cancel Auth Code Request", t, func() {
+		requester := &Requester{UserID: testUserID}
+
+		Convey("error getting request", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(nil, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.CancelAuthCodeRequest(authCodeRequestID, requester), ShouldEqual, Error)
+		})
+
+		Convey("request not found", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(nil, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.CancelAuthCodeRequest(authCodeRequestID, requester), ShouldEqual, NotFound)
+		})
+
+		Convey("request already cancelled", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeReq := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusCancelled,
+				},
 			}
 
-			httpmock.Activate()
-			defer httpmock.DeactivateAndReset()
-			responder := httpmock.NewStringResponder(http.StatusInternalServerError, "")
-			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/123", responder)
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(&authCodeReq, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.CancelAuthCodeRequest(authCodeRequestID, requester), ShouldEqual, Success)
+		})
+
+		Convey("request submitted since it was read", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
 
 			authCodeReq := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
-					OfficerID: "987",
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusPending,
+				},
+			}
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(&authCodeReq, nil)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusPending, models.StatusCancelled).Return(false, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.CancelAuthCodeRequest(authCodeRequestID, requester), ShouldEqual, Conflict)
+		})
+
+		Convey("request cancelled - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			authCodeReq := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CreatedBy: models.CreatedByDao{ID: testUserID},
+					Status:    models.StatusPending,
 				},
 			}
 
-			responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true)
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(&authCodeReq, nil)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusPending, models.StatusCancelled).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.CancelAuthCodeRequest(authCodeRequestID, requester), ShouldEqual, Success)
+		})
+	})
+}
+
+func TestUnitListAuthCodeRequests(t *testing.T) {
+	Convey("List Auth Code Requests", t, func() {
+		filter := models.AuthCodeRequestFilter{Status: models.StatusPending, CompanyNumber: companyNumber}
+
+		Convey("requester has no user ID", func() {
+			svc := AuthCodeRequestService{}
+
+			response, responseType := svc.ListAuthCodeRequests(&Requester{}, filter, 0, 15)
+			So(response, ShouldBeNil)
+			So(responseType, ShouldEqual, InvalidData)
+		})
+
+		Convey("error listing requests", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ListAuthCodeRequests(gomock.Any(), 0, 15).Return(nil, int64(0), fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.ListAuthCodeRequests(&Requester{UserID: testUserID}, filter, 0, 15)
+			So(response, ShouldBeNil)
 			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("list requests - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			expectedFilter := models.AuthCodeRequestFilter{UserID: testUserID, Status: models.StatusPending, CompanyNumber: companyNumber}
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{ID: "2", Data: models.AuthCodeRequestDataDao{CompanyNumber: companyNumber, Status: models.StatusPending}},
+			}
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ListAuthCodeRequests(expectedFilter, 15, 15).Return(authCodeRequests, int64(16), nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
 
+			response, responseType := svc.ListAuthCodeRequests(&Requester{UserID: testUserID}, filter, 15, 15)
+			So(responseType, ShouldEqual, Success)
+			So(response.TotalResults, ShouldEqual, 16)
+			So(response.StartIndex, ShouldEqual, 15)
+			So(response.Items, ShouldHaveLength, 1)
 		})
+	})
+}
 
-		Convey("officer not found", func() {
+func TestUnitSendAuthCodeRequestErrors(t *testing.T) {
+	Convey("send auth code request", t, func() {
+		// build test config
+		cfg, _ := config.Get()
+
+		Convey("error getting officer details", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
 			svc := AuthCodeRequestService{
 				DAO:    mockDaoService,
 				Config: cfg,
@@ -133,8 +351,8 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowErrors(t *testing.T) {
 
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
-			responder := httpmock.NewStringResponder(http.StatusNotFound, "")
-			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+			responder := httpmock.NewStringResponder(http.StatusInternalServerError, "")
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/123", responder)
 
 			authCodeReq := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
@@ -142,15 +360,17 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowErrors(t *testing.T) {
 				},
 			}
 
-			responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true)
-			So(responseType, ShouldEqual, NotFound)
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Error)
+
 		})
 
-		Convey("error sending queue item", func() {
+		Convey("officer not found", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
 			svc := AuthCodeRequestService{
 				DAO:    mockDaoService,
 				Config: cfg,
@@ -158,7 +378,7 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowErrors(t *testing.T) {
 
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
-			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			responder := httpmock.NewStringResponder(http.StatusNotFound, "")
 			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
 
 			authCodeReq := models.AuthCodeRequestResourceDao{
@@ -167,25 +387,18 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowErrors(t *testing.T) {
 				},
 			}
 
-			responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true)
-			So(responseType, ShouldEqual, Error)
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, NotFound)
 		})
-	})
-}
-
-func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowSuccess(t *testing.T) {
-	Convey("send auth code request", t, func() {
-		Convey("send auth code request - success", func() {
-			// build test config
-			cfg, _ := config.Get()
-			cfg.NewAuthCodeAPIFlow = false
-			cfg.QueueAPILocalURL = "http://local.test"
-			cfg.QueueAPILocalPath = "/api/queue/authcode"
 
+		Convey("error recording status and outbox items", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(1, nil)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(fmt.Errorf("error"))
 			svc := AuthCodeRequestService{
 				DAO:    mockDaoService,
 				Config: cfg,
@@ -193,10 +406,8 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowSuccess(t *testing.T) {
 
 			httpmock.Activate()
 			defer httpmock.DeactivateAndReset()
-			responder := httpmock.NewStringResponder(http.StatusOK, `{"forename":"joe","surname":"bloggs"}`)
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
 			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
-			queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{}`)
-			httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
 
 			authCodeReq := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
@@ -204,25 +415,30 @@ func TestUnitSendAuthCodeRequestQueueAPIAuthCodeFlowSuccess(t *testing.T) {
 				},
 			}
 
-			responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true)
-			So(responseType, ShouldEqual, Success)
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Error)
 		})
 	})
 }
 
-func TestUnitSendAuthCodeRequestAuthCodeAPIAuthCodeFlow(t *testing.T) {
+func TestUnitSendAuthCodeRequestSuccess(t *testing.T) {
 	Convey("send auth code request", t, func() {
 		Convey("send auth code request - success", func() {
 			// build test config
 			cfg, _ := config.Get()
-			cfg.NewAuthCodeAPIFlow = true
-			cfg.AuthCodeAPILocalURL = "http://local.test"
-			cfg.AuthCodeAPILocalPath = "/private/company/%s/authcode/request"
 
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
+			var outboxItems []models.OutboxItemDao
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(1, nil)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).DoAndReturn(
+				func(_ *models.AuthCodeRequestResourceDao, _ models.RequestStatus, items []models.OutboxItemDao) error {
+					outboxItems = items
+					return nil
+				})
 			svc := AuthCodeRequestService{
 				DAO:    mockDaoService,
 				Config: cfg,
@@ -232,8 +448,6 @@ func TestUnitSendAuthCodeRequestAuthCodeAPIAuthCodeFlow(t *testing.T) {
 			defer httpmock.DeactivateAndReset()
 			responder := httpmock.NewStringResponder(http.StatusOK, `{"forename":"joe","surname":"bloggs"}`)
 			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
-			queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{}`)
-			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf(cfg.AuthCodeAPILocalPath, "87654321"), queueAPIResponder)
 
 			authCodeReq := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
@@ -241,8 +455,19 @@ func TestUnitSendAuthCodeRequestAuthCodeAPIAuthCodeFlow(t *testing.T) {
 				},
 			}
 
-			responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true)
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
 			So(responseType, ShouldEqual, Success)
+
+			// letter and confirmation email are recorded for dispatch rather than sent
+			So(httpmock.GetTotalCallCount(), ShouldEqual, 1)
+			So(outboxItems, ShouldHaveLength, 2)
+			So(outboxItems[0].Type, ShouldEqual, models.OutboxTypeLetter)
+			So(outboxItems[0].AuthCodeRequestID, ShouldEqual, testRequestID)
+			So(outboxItems[0].Status, ShouldEqual, models.OutboxStatusPending)
+			So(outboxItems[0].AuthCodeItem.CompanyName, ShouldEqual, "joe bloggs")
+			So(outboxItems[0].AuthCodeItem.Status, ShouldEqual, "reminder")
+			So(outboxItems[1].Type, ShouldEqual, models.OutboxTypeEmail)
+			So(outboxItems[1].EmailSend.EmailAddress, ShouldEqual, "email@companieshouse.gov.uk")
 		})
 	})
 }
@@ -257,7 +482,7 @@ func TestUnitGetAuthCodeReqDao(t *testing.T) {
 			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(nil, fmt.Errorf("error"))
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber)
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID})
 			So(request, ShouldBeNil)
 			So(responseType, ShouldEqual, Error)
 		})
@@ -270,7 +495,7 @@ func TestUnitGetAuthCodeReqDao(t *testing.T) {
 			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(nil, nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber)
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID})
 			So(request, ShouldBeNil)
 			So(responseType, ShouldEqual, NotFound)
 		})
@@ -283,16 +508,73 @@ func TestUnitGetAuthCodeReqDao(t *testing.T) {
 			response := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
 					CompanyNumber: "mismatch",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
 				},
 			}
 			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(&response, nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber)
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID})
 			So(request, ShouldBeNil)
 			So(responseType, ShouldEqual, InvalidData)
 		})
 
+		Convey("request created by another user", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			response := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: companyNumber,
+					CreatedBy:     models.CreatedByDao{ID: "otherUser"},
+				},
+			}
+			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(&response, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID})
+			So(request, ShouldBeNil)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("request created by another user - elevated requester", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			response := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: companyNumber,
+					CreatedBy:     models.CreatedByDao{ID: "otherUser"},
+				},
+			}
+			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(&response, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID, Elevated: true})
+			So(request.Data.CompanyNumber, ShouldEqual, companyNumber)
+			So(responseType, ShouldEqual, Success)
+		})
+
+		Convey("company number supplied in lower case", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			response := models.AuthCodeRequestResourceDao{
+				Data: models.AuthCodeRequestDataDao{
+					CompanyNumber: "SC123456",
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
+				},
+			}
+			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(&response, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			_, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, "sc123456", &Requester{UserID: testUserID})
+			So(responseType, ShouldEqual, Success)
+		})
+
 		Convey("get auth code request - success", func() {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
@@ -301,12 +583,13 @@ func TestUnitGetAuthCodeReqDao(t *testing.T) {
 			response := models.AuthCodeRequestResourceDao{
 				Data: models.AuthCodeRequestDataDao{
 					CompanyNumber: companyNumber,
+					CreatedBy:     models.CreatedByDao{ID: testUserID},
 				},
 			}
 			mockDaoService.EXPECT().GetAuthCodeRequest(gomock.Any()).Return(&response, nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber)
+			request, responseType := svc.GetAuthCodeReqDao(authCodeRequestID, companyNumber, &Requester{UserID: testUserID})
 			So(request.Data.CompanyNumber, ShouldEqual, companyNumber)
 			So(responseType, ShouldEqual, Success)
 		})
@@ -329,11 +612,11 @@ func TestUnitCheckMultipleCorporateBodySubmissions(t *testing.T) {
 
 			const errorMessage = "error test"
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(false, fmt.Errorf(errorMessage))
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf(errorMessage))
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
 			response, err := svc.CheckMultipleCorporateBodySubmissions(companyNumber)
-			So(response, ShouldBeFalse)
+			So(response, ShouldBeNil)
 			So(err, ShouldNotBeNil)
 			So(err.Error(), ShouldContainSubstring, errorMessage)
 		})
@@ -342,12 +625,38 @@ func TestUnitCheckMultipleCorporateBodySubmissions(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
+			retryAfter := time.Now()
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().CheckMultipleCorporateBodySubmissions(gomock.Any()).Return(true, nil)
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{retryAfter.Add(-72 * time.Hour)}}, nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
 			response, err := svc.CheckMultipleCorporateBodySubmissions(companyNumber)
-			So(response, ShouldBeTrue)
+			So(response.Equal(retryAfter), ShouldBeTrue)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Company is within the configured limit", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), 2).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{time.Now()}}, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: &config.Config{CompanySubmissionLimit: 2}}
+
+			response, err := svc.CheckMultipleCorporateBodySubmissions(companyNumber)
+			So(response, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Company is exempt", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Policy: &SubmissionPolicy{ExemptCompanies: []string{companyNumber}}}
+
+			response, err := svc.CheckMultipleCorporateBodySubmissions(companyNumber)
+			So(response, ShouldBeNil)
 			So(err, ShouldBeNil)
 		})
 	})
@@ -362,11 +671,11 @@ func TestUnitCheckMultipleUserSubmissions(t *testing.T) {
 
 			const errorMessage = "error test"
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(false, fmt.Errorf(errorMessage))
+			mockDaoService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf(errorMessage))
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			response, err := svc.CheckMultipleUserSubmissions(companyNumber)
-			So(response, ShouldBeFalse)
+			response, err := svc.CheckMultipleUserSubmissions(testUserID, "test@test.com")
+			So(response, ShouldBeNil)
 			So(err, ShouldNotBeNil)
 			So(err.Error(), ShouldContainSubstring, errorMessage)
 		})
@@ -375,12 +684,25 @@ func TestUnitCheckMultipleUserSubmissions(t *testing.T) {
 			mockCtrl := gomock.NewController(t)
 			defer mockCtrl.Finish()
 
+			retryAfter := time.Now()
 			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
-			mockDaoService.EXPECT().CheckMultipleUserSubmissions(gomock.Any()).Return(true, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3, SubmittedAt: []time.Time{retryAfter.Add(-time.Hour), retryAfter.Add(-2 * time.Hour), retryAfter.Add(-24 * time.Hour)}}, nil)
 			svc := AuthCodeRequestService{DAO: mockDaoService}
 
-			response, err := svc.CheckMultipleUserSubmissions(companyNumber)
-			So(response, ShouldBeTrue)
+			response, err := svc.CheckMultipleUserSubmissions(testUserID, "test@test.com")
+			So(response.Equal(retryAfter), ShouldBeTrue)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("User is exempt", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Policy: &SubmissionPolicy{ExemptUsers: []string{"support@test.com"}}}
+
+			response, err := svc.CheckMultipleUserSubmissions(testUserID, "support@test.com")
+			So(response, ShouldBeNil)
 			So(err, ShouldBeNil)
 		})
 	})
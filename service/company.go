@@ -2,11 +2,18 @@ package service
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/go-sdk-manager/manager"
 )
 
+// NormaliseCompanyNumber returns the company number in the form in which it is stored, so that requests
+// supplied in any case are found by, and counted against the limits of, the same company
+func NormaliseCompanyNumber(companyNumber string) string {
+	return strings.ToUpper(strings.TrimSpace(companyNumber))
+}
+
 // GetCompanyName will attempt to get the company name from the CompanyProfileAPI.
 func GetCompanyName(companyNumber string, basePath string, req *http.Request) (string, error) {
 
@@ -58,3 +58,10 @@ func TestUnitGetCompanyInformation(t *testing.T) {
 		})
 	})
 }
+
+func TestUnitNormaliseCompanyNumber(t *testing.T) {
+	Convey("Normalise company number", t, func() {
+		So(NormaliseCompanyNumber("sc123456"), ShouldEqual, "SC123456")
+		So(NormaliseCompanyNumber(" 87654321 "), ShouldEqual, "87654321")
+	})
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/transformers"
+)
+
+// ExportDataSubject returns every authcode request created by the data subject, along with its audit
+// trail, to answer their subject access request
+func (s *AuthCodeRequestService) ExportDataSubject(subject models.DataSubject) (*models.DataSubjectExport, ResponseType) {
+	authCodeRequests, err := s.DAO.ListDataSubjectAuthCodeRequests(subject)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing data subject authcode requests: %v", err))
+		return nil, Error
+	}
+
+	exportedAt := time.Now().Truncate(time.Millisecond)
+
+	export := &models.DataSubjectExport{
+		UserID:           subject.UserID,
+		UserEmail:        subject.UserEmail,
+		ExportedAt:       &exportedAt,
+		AuthCodeRequests: make([]models.DataSubjectAuthCodeRequest, 0, len(authCodeRequests)),
+	}
+
+	for i := range authCodeRequests {
+		entries, err := s.AuditDAO.ListAuditEntries(authCodeRequests[i].ID)
+		if err != nil {
+			log.Error(fmt.Errorf("error listing authcode request audit entries: %v", err), log.Data{"auth_code_request_id": authCodeRequests[i].ID})
+			return nil, Error
+		}
+
+		export.AuthCodeRequests = append(export.AuthCodeRequests, *transformers.AuthCodeRequestResourceDaoToDataSubjectResponse(&authCodeRequests[i], entries))
+	}
+
+	log.Info("data subject authcode requests exported", log.Data{"auth_code_requests": len(authCodeRequests)})
+
+	return export, Success
+}
+
+// EraseDataSubject erases every authcode request created by the data subject, to answer their erasure
+// request. The requests are deleted along with their audit trails, or are anonymised, depending on the
+// mode. Conflict is returned, and nothing is changed, if any of the requests are in flight or have letters
+// or emails still to be delivered, as they may yet be sent. Pending requests are cancelled by the requester
+// before being erased. If one is submitted first, the others are still erased, and Conflict is returned
+// along with the requests erased and the request now in flight.
+func (s *AuthCodeRequestService) EraseDataSubject(subject models.DataSubject, mode models.ErasureMode, requester *Requester) (*models.DataSubjectErasureResponse, ResponseType) {
+	logContext := log.Data{"mode": mode, "erased_by": requester.actor().ID}
+
+	authCodeRequests, err := s.DAO.ListDataSubjectAuthCodeRequests(subject)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing data subject authcode requests: %v", err), logContext)
+		return nil, Error
+	}
+
+	response := &models.DataSubjectErasureResponse{
+		UserID:           subject.UserID,
+		UserEmail:        subject.UserEmail,
+		Mode:             string(mode),
+		AuthCodeRequests: []string{},
+	}
+
+	if len(authCodeRequests) == 0 {
+		return response, Success
+	}
+
+	ids := make([]string, 0, len(authCodeRequests))
+	for i := range authCodeRequests {
+		ids = append(ids, authCodeRequests[i].ID)
+	}
+
+	undelivered, err := s.DAO.ListUndeliveredAuthCodeRequestIDs(ids)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing data subject authcode requests with undelivered outbox items: %v", err), logContext)
+		return nil, Error
+	}
+
+	for i := range authCodeRequests {
+		if authCodeRequests[i].Data.Status.IsInFlight() || slices.Contains(undelivered, authCodeRequests[i].ID) {
+			response.InFlight = append(response.InFlight, authCodeRequests[i].ID)
+		}
+	}
+
+	if len(response.InFlight) > 0 {
+		log.Info("data subject has authcode requests in flight so cannot be erased", logContext)
+		return response, Conflict
+	}
+
+	var cancelled []string
+	for i := range authCodeRequests {
+		if authCodeRequests[i].Data.Status != models.StatusPending {
+			continue
+		}
+
+		transitioned, err := s.DAO.TransitionAuthCodeRequestStatus(authCodeRequests[i].ID, models.StatusPending, models.StatusCancelled)
+		if err != nil {
+			log.Error(fmt.Errorf("error cancelling authcode request to erase: %v", err), log.Data{"auth_code_request_id": authCodeRequests[i].ID, "cancelled": cancelled})
+			return nil, Error
+		}
+
+		// the request has been submitted since it was read, so is left for it to be sent, and is not
+		// erased along with the others
+		if !transitioned {
+			log.Info("authcode request status changed so cannot be erased", log.Data{"auth_code_request_id": authCodeRequests[i].ID})
+			response.InFlight = append(response.InFlight, authCodeRequests[i].ID)
+			continue
+		}
+
+		cancelled = append(cancelled, authCodeRequests[i].ID)
+		s.recordAudit(authCodeRequests[i].ID, requester, models.AuditActionCancelled, statusState(models.StatusPending), statusState(models.StatusCancelled))
+	}
+
+	var erased []string
+	if mode == models.ErasureModeAnonymise {
+		if erased, err = s.DAO.AnonymiseAuthCodeRequests(ids); err != nil {
+			log.Error(fmt.Errorf("error anonymising data subject authcode requests: %v", err), logContext)
+			return nil, Error
+		}
+		if len(erased) > 0 {
+			if _, err = s.AuditDAO.AnonymiseAuditEntries(erased); err != nil {
+				log.Error(fmt.Errorf("error anonymising audit entries: %v", err), logContext)
+				return nil, Error
+			}
+		}
+	} else {
+		if erased, err = s.DAO.DeleteAuthCodeRequests(ids); err != nil {
+			log.Error(fmt.Errorf("error deleting data subject authcode requests: %v", err), logContext)
+			return nil, Error
+		}
+		if len(erased) > 0 {
+			if _, err = s.AuditDAO.DeleteAuditEntries(erased); err != nil {
+				log.Error(fmt.Errorf("error deleting audit entries: %v", err), logContext)
+				return nil, Error
+			}
+		}
+	}
+
+	response.AuthCodeRequests = append(response.AuthCodeRequests, erased...)
+
+	logContext["auth_code_requests"] = len(erased)
+
+	if len(response.InFlight) > 0 {
+		log.Info("data subject authcode requests erased other than those submitted while being erased", logContext)
+		return response, Conflict
+	}
+
+	log.Info("data subject authcode requests erased", logContext)
+
+	return response, Success
+}
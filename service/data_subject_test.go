@@ -0,0 +1,263 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitExportDataSubject(t *testing.T) {
+	Convey("Export data subject authcode requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, AuditDAO: mockAuditService}
+
+		subject := models.DataSubject{UserEmail: "test@test.com"}
+
+		Convey("error listing authcode requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(nil, fmt.Errorf("error"))
+
+			export, responseType := svc.ExportDataSubject(subject)
+			So(export, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("error listing audit entries", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{{ID: "123"}}, nil)
+			mockAuditService.EXPECT().ListAuditEntries("123").Return(nil, fmt.Errorf("error"))
+
+			export, responseType := svc.ExportDataSubject(subject)
+			So(export, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("no authcode requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{}, nil)
+
+			export, responseType := svc.ExportDataSubject(subject)
+			So(responseType, ShouldEqual, Success)
+			So(export.UserEmail, ShouldEqual, "test@test.com")
+			So(export.ExportedAt, ShouldNotBeNil)
+			So(export.AuthCodeRequests, ShouldBeEmpty)
+		})
+
+		Convey("authcode requests exported with audit trails", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{CompanyNumber: "87654321", OfficerForename: "Jane", OfficerSurname: "Doe", CreatedBy: models.CreatedByDao{ID: testUserID, Email: "TEST@test.com"}}},
+				{ID: "456", Data: models.AuthCodeRequestDataDao{CompanyNumber: "12345678"}},
+			}, nil)
+			mockAuditService.EXPECT().ListAuditEntries("123").Return([]models.AuditEntryDao{{ID: "1", Action: models.AuditActionCreated}}, nil)
+			mockAuditService.EXPECT().ListAuditEntries("456").Return([]models.AuditEntryDao{}, nil)
+
+			export, responseType := svc.ExportDataSubject(subject)
+			So(responseType, ShouldEqual, Success)
+			So(export.AuthCodeRequests, ShouldHaveLength, 2)
+			So(export.AuthCodeRequests[0].ID, ShouldEqual, "123")
+			So(export.AuthCodeRequests[0].UserEmail, ShouldEqual, "TEST@test.com")
+			So(export.AuthCodeRequests[0].OfficerName, ShouldEqual, "Jane Doe")
+			So(export.AuthCodeRequests[0].AuditTrail, ShouldHaveLength, 1)
+			So(export.AuthCodeRequests[0].AuditTrail[0].Action, ShouldEqual, "created")
+			So(export.AuthCodeRequests[1].ID, ShouldEqual, "456")
+			So(export.AuthCodeRequests[1].AuditTrail, ShouldBeEmpty)
+		})
+
+		Convey("staff identities and hold reasons not exported", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{
+				{ID: "123", Data: models.AuthCodeRequestDataDao{
+					HoldReasons: []models.HoldReason{models.HoldReasonUserMultipleCompanies},
+					Reviews:     []models.ReviewDao{{Decision: models.ReviewApproved, Reason: "checked", ReviewedBy: models.ActorDao{ID: "reviewer", Email: "reviewer@test.com"}}},
+				}},
+			}, nil)
+			mockAuditService.EXPECT().ListAuditEntries("123").Return([]models.AuditEntryDao{
+				{ID: "1", Action: models.AuditActionApproved, Actor: models.ActorDao{ID: "reviewer", Email: "reviewer@test.com"}, ActorType: models.ActorTypeAPIKey},
+			}, nil)
+
+			export, responseType := svc.ExportDataSubject(subject)
+			So(responseType, ShouldEqual, Success)
+			So(export.AuthCodeRequests[0].Reviews, ShouldResemble, []models.DataSubjectReview{{Decision: "approved"}})
+			So(export.AuthCodeRequests[0].AuditTrail[0].Actor, ShouldBeNil)
+		})
+	})
+}
+
+func TestUnitEraseDataSubject(t *testing.T) {
+	Convey("Erase data subject authcode requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, AuditDAO: mockAuditService}
+
+		subject := models.DataSubject{UserID: testUserID}
+		requester := &Requester{UserID: testUserID, APIKey: true, APIKeyID: "admin", Elevated: true}
+
+		authCodeRequests := []models.AuthCodeRequestResourceDao{
+			{ID: "123", Data: models.AuthCodeRequestDataDao{Status: models.StatusPosted}},
+			{ID: "456", Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}},
+		}
+
+		Convey("error listing authcode requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(nil, fmt.Errorf("error"))
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(erasure, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("no authcode requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return([]models.AuthCodeRequestResourceDao{}, nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Success)
+			So(erasure.AuthCodeRequests, ShouldBeEmpty)
+		})
+
+		Convey("nothing erased while requests in flight", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(append(authCodeRequests,
+				models.AuthCodeRequestResourceDao{ID: "789", Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitting}},
+				models.AuthCodeRequestResourceDao{ID: "999", Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld}},
+			), nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Conflict)
+			So(erasure.InFlight, ShouldResemble, []string{"789", "999"})
+		})
+
+		Convey("nothing erased while a submitted request's letter is undelivered", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(append(authCodeRequests,
+				models.AuthCodeRequestResourceDao{ID: "789", Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitted}},
+			), nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeAnonymise, requester)
+			So(responseType, ShouldEqual, Conflict)
+			So(erasure.InFlight, ShouldResemble, []string{"789"})
+			So(erasure.AuthCodeRequests, ShouldBeEmpty)
+		})
+
+		Convey("nothing erased while a resent letter is undelivered", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests, nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123", "456"}).Return([]string{"123"}, nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Conflict)
+			So(erasure.InFlight, ShouldResemble, []string{"123"})
+			So(erasure.AuthCodeRequests, ShouldBeEmpty)
+		})
+
+		Convey("error listing requests with undelivered outbox items", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests, nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123", "456"}).Return(nil, fmt.Errorf("error"))
+
+			_, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("others erased when pending request submitted before it is cancelled", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(append(authCodeRequests,
+				models.AuthCodeRequestResourceDao{ID: "789", Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}},
+			), nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus("456", models.StatusPending, models.StatusCancelled).Return(false, nil)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus("789", models.StatusPending, models.StatusCancelled).Return(true, nil)
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).Return(nil)
+			mockDaoService.EXPECT().DeleteAuthCodeRequests([]string{"123", "456", "789"}).Return([]string{"123", "789"}, nil)
+			mockAuditService.EXPECT().DeleteAuditEntries([]string{"123", "789"}).Return(int64(4), nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Conflict)
+			So(erasure.InFlight, ShouldResemble, []string{"456"})
+			So(erasure.AuthCodeRequests, ShouldResemble, []string{"123", "789"})
+		})
+
+		Convey("error cancelling pending request", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests, nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().TransitionAuthCodeRequestStatus("456", models.StatusPending, models.StatusCancelled).Return(false, fmt.Errorf("error"))
+
+			_, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("pending request cancelled then requests deleted", func() {
+			var auditEntry *models.AuditEntryDao
+			gomock.InOrder(
+				mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests, nil),
+				mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs([]string{"123", "456"}).Return(nil, nil),
+				mockDaoService.EXPECT().TransitionAuthCodeRequestStatus("456", models.StatusPending, models.StatusCancelled).Return(true, nil),
+				mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(func(entry *models.AuditEntryDao) error {
+					auditEntry = entry
+					return nil
+				}),
+				mockDaoService.EXPECT().DeleteAuthCodeRequests([]string{"123", "456"}).Return([]string{"123", "456"}, nil),
+				mockAuditService.EXPECT().DeleteAuditEntries([]string{"123", "456"}).Return(int64(5), nil),
+			)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Success)
+			So(erasure.Mode, ShouldEqual, "delete")
+			So(erasure.AuthCodeRequests, ShouldResemble, []string{"123", "456"})
+			So(auditEntry.Action, ShouldEqual, models.AuditActionCancelled)
+			So(auditEntry.Actor.ID, ShouldEqual, "admin")
+		})
+
+		Convey("error deleting requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests[:1], nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().DeleteAuthCodeRequests([]string{"123"}).Return(nil, fmt.Errorf("error"))
+
+			_, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("error deleting audit entries", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests[:1], nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().DeleteAuthCodeRequests([]string{"123"}).Return([]string{"123"}, nil)
+			mockAuditService.EXPECT().DeleteAuditEntries([]string{"123"}).Return(int64(0), fmt.Errorf("error"))
+
+			_, responseType := svc.EraseDataSubject(subject, models.ErasureModeDelete, requester)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("requests anonymised", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests[:1], nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().AnonymiseAuthCodeRequests([]string{"123"}).Return([]string{"123"}, nil)
+			mockAuditService.EXPECT().AnonymiseAuditEntries([]string{"123"}).Return(int64(3), nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeAnonymise, requester)
+			So(responseType, ShouldEqual, Success)
+			So(erasure.Mode, ShouldEqual, "anonymise")
+			So(erasure.AuthCodeRequests, ShouldResemble, []string{"123"})
+		})
+
+		Convey("requests already anonymised", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests[:1], nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().AnonymiseAuthCodeRequests([]string{"123"}).Return(nil, nil)
+
+			erasure, responseType := svc.EraseDataSubject(subject, models.ErasureModeAnonymise, requester)
+			So(responseType, ShouldEqual, Success)
+			So(erasure.AuthCodeRequests, ShouldBeEmpty)
+		})
+
+		Convey("error anonymising requests", func() {
+			mockDaoService.EXPECT().ListDataSubjectAuthCodeRequests(subject).Return(authCodeRequests[:1], nil)
+			mockDaoService.EXPECT().ListUndeliveredAuthCodeRequestIDs(gomock.Any()).Return(nil, nil)
+			mockDaoService.EXPECT().AnonymiseAuthCodeRequests([]string{"123"}).Return(nil, fmt.Errorf("error"))
+
+			_, responseType := svc.EraseDataSubject(subject, models.ErasureModeAnonymise, requester)
+			So(responseType, ShouldEqual, Error)
+		})
+	})
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// The checks made on whether an auth code may be requested for a company
+const (
+	EligibilityCheckCompanySubmissions = "company-submissions"
+	EligibilityCheckUserSubmissions    = "user-submissions"
+	EligibilityCheckFilingHistory      = "filing-history"
+	EligibilityCheckEligibleOfficers   = "eligible-officers"
+)
+
+// eligibilityCheck is a named check which returns the reason an auth code may not be requested, or nil
+// if the check passes
+type eligibilityCheck struct {
+	name string
+	run  func() (*models.EligibilityFailureResponse, error)
+}
+
+// ValidateCorporateBody checks in turn whether an auth code may be requested for the company by the user,
+// returning the reason of the first check to fail, or nil if they all pass
+func (s *AuthCodeRequestService) ValidateCorporateBody(companyNumber, userID, email string) (*models.EligibilityFailureResponse, error) {
+	companyNumber = NormaliseCompanyNumber(companyNumber)
+
+	for _, check := range s.corporateBodyChecks(companyNumber, userID, email) {
+		failure, err := check.run()
+		if err != nil || failure != nil {
+			return failure, err
+		}
+	}
+
+	return nil, nil
+}
+
+// CheckOfficerSubmissions checks whether an auth code may be requested for the officer, and sent to their
+// usual residential address, given the requests already submitted for them across all companies. The
+// reason the first limit reached is returned, or nil if neither has been. Requests for an exempt company,
+// or by an exempt user, are not limited.
+func (s *AuthCodeRequestService) CheckOfficerSubmissions(companyNumber, email, officerID, officerUraID string) (*models.EligibilityFailureResponse, error) {
+	policy := s.submissionPolicy()
+	if policy.IsCompanyExempt(companyNumber) || policy.IsUserExempt(email) {
+		return nil, nil
+	}
+
+	if officerID != "" {
+		count, err := s.DAO.CountOfficerSubmissions(officerID, time.Now().Add(-policy.Officer.Window), policy.Officer.MaxSubmissions)
+		if err != nil {
+			log.Error(fmt.Errorf("error checking officer submissions: %v", err))
+			return nil, err
+		}
+		if retryAfter := policy.Officer.RetryAfter(count); retryAfter != nil {
+			log.Info("requests exceeded for officer", log.Data{"officer_id": officerID})
+			return models.NewEligibilityFailureResponse(models.ReasonOfficerLimitExceeded,
+				"the maximum number of auth code requests has been reached for this officer", retryAfter), nil
+		}
+	}
+
+	if officerUraID != "" {
+		count, err := s.DAO.CountOfficerAddressSubmissions(officerUraID, time.Now().Add(-policy.Address.Window), policy.Address.MaxSubmissions)
+		if err != nil {
+			log.Error(fmt.Errorf("error checking officer address submissions: %v", err))
+			return nil, err
+		}
+		if retryAfter := policy.Address.RetryAfter(count); retryAfter != nil {
+			log.Info("requests exceeded for officer address", log.Data{"officer_id": officerID})
+			return models.NewEligibilityFailureResponse(models.ReasonAddressLimitExceeded,
+				"the maximum number of auth code requests has been reached for this officer's address", retryAfter), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CheckEligibility makes every check on whether an auth code may be requested for the company by the user,
+// including whether the company has any eligible officers, and reports the outcome of each without
+// creating anything
+func (s *AuthCodeRequestService) CheckEligibility(companyNumber, userID, email string) (*models.EligibilityResponse, error) {
+	companyNumber = NormaliseCompanyNumber(companyNumber)
+
+	checks := append(s.corporateBodyChecks(companyNumber, userID, email), eligibilityCheck{
+		name: EligibilityCheckEligibleOfficers,
+		run: func() (*models.EligibilityFailureResponse, error) {
+			companyIsEligible, err := CheckOfficers(companyNumber)
+			if err != nil || companyIsEligible {
+				return nil, err
+			}
+			return models.NewEligibilityFailureResponse(models.ReasonNoEligibleOfficers,
+				"corporate body has no eligible officers", nil), nil
+		},
+	})
+
+	eligibility := &models.EligibilityResponse{
+		CompanyNumber: companyNumber,
+		Eligible:      true,
+		Checks:        []models.EligibilityCheck{},
+	}
+
+	for _, check := range checks {
+		failure, err := check.run()
+		if err != nil {
+			log.Error(fmt.Errorf("error making eligibility check [%s]: %v", check.name, err))
+			return nil, err
+		}
+
+		eligibility.Checks = append(eligibility.Checks, models.EligibilityCheck{
+			Check:    check.name,
+			Eligible: failure == nil,
+			Failure:  failure,
+		})
+		if failure != nil {
+			eligibility.Eligible = false
+		}
+	}
+
+	return eligibility, nil
+}
+
+// CheckSubmissionLimits checks whether an authcode request may be submitted given the requests already
+// submitted for its company, by the user who created it, and for its officer and their usual residential
+// address. The reason the first limit reached is returned, or nil if none has been.
+func (s *AuthCodeRequestService) CheckSubmissionLimits(authCodeReqDao *models.AuthCodeRequestResourceDao) (*models.EligibilityFailureResponse, error) {
+	data := authCodeReqDao.Data
+
+	for _, check := range s.submissionLimitChecks(data.CompanyNumber, data.CreatedBy.ID, data.CreatedBy.Email) {
+		failure, err := check.run()
+		if err != nil || failure != nil {
+			return failure, err
+		}
+	}
+
+	return s.CheckOfficerSubmissions(data.CompanyNumber, data.CreatedBy.Email, data.OfficerID, data.OfficerUraID)
+}
+
+// corporateBodyChecks returns the checks which must pass before an auth code request may be created
+func (s *AuthCodeRequestService) corporateBodyChecks(companyNumber, userID, email string) []eligibilityCheck {
+	return append(s.submissionLimitChecks(companyNumber, userID, email), eligibilityCheck{
+		name: EligibilityCheckFilingHistory,
+		run: func() (*models.EligibilityFailureResponse, error) {
+			hasFiledWithinPeriod, err := CheckCompanyFilingHistory(companyNumber)
+			if err != nil || !hasFiledWithinPeriod {
+				return nil, err
+			}
+			return models.NewEligibilityFailureResponse(models.ReasonCompanyFiledRecently,
+				"the company has filed electronically within the eligibility period", nil), nil
+		},
+	})
+}
+
+// submissionLimitChecks returns the checks of the submission limits for the company and the user
+func (s *AuthCodeRequestService) submissionLimitChecks(companyNumber, userID, email string) []eligibilityCheck {
+	return []eligibilityCheck{
+		{
+			name: EligibilityCheckCompanySubmissions,
+			run: func() (*models.EligibilityFailureResponse, error) {
+				retryAfter, err := s.CheckMultipleCorporateBodySubmissions(companyNumber)
+				if err != nil || retryAfter == nil {
+					return nil, err
+				}
+				return models.NewEligibilityFailureResponse(models.ReasonCompanyRecentlyRequested,
+					"an auth code has recently been requested for this company", retryAfter), nil
+			},
+		},
+		{
+			name: EligibilityCheckUserSubmissions,
+			run: func() (*models.EligibilityFailureResponse, error) {
+				retryAfter, err := s.CheckMultipleUserSubmissions(userID, email)
+				if err != nil || retryAfter == nil {
+					return nil, err
+				}
+				return models.NewEligibilityFailureResponse(models.ReasonUserLimitExceeded,
+					"the maximum number of auth code requests has been reached for this user", retryAfter), nil
+			},
+		},
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// expectSubmissionLimitsChecked expects the company, user and officer submission limits to be checked when a
+// request is submitted, none of which have been reached
+func expectSubmissionLimitsChecked(mockDaoService *mocks.MockAuthcodeRequestDAOService) {
+	mockDaoService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+	mockDaoService.EXPECT().CountUserSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+	mockDaoService.EXPECT().CountOfficerSubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+}
+
+func TestUnitValidateCorporateBody(t *testing.T) {
+	const email = "test@test.com"
+	filingHistoryURL := "/emergency-auth-code/company/" + companyNumber + "/efiling-status"
+
+	Convey("Validate corporate body", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+		retryAfter := time.Now()
+
+		Convey("error checking company submissions", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			failure, err := svc.ValidateCorporateBody(companyNumber, testUserID, email)
+			So(failure, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("company recently requested", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{retryAfter.Add(-72 * time.Hour)}}, nil)
+
+			failure, err := svc.ValidateCorporateBody(companyNumber, testUserID, email)
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonCompanyRecentlyRequested)
+			So(failure.RetryAfter.Equal(retryAfter), ShouldBeTrue)
+		})
+
+		Convey("user limit exceeded", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3, SubmittedAt: []time.Time{retryAfter.Add(-time.Hour), retryAfter.Add(-2 * time.Hour), retryAfter.Add(-24 * time.Hour)}}, nil)
+
+			failure, err := svc.ValidateCorporateBody(companyNumber, testUserID, email)
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonUserLimitExceeded)
+			So(failure.RetryAfter.Equal(retryAfter), ShouldBeTrue)
+		})
+
+		Convey("company filed recently", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			httpmock.RegisterResponder(http.MethodGet, filingHistoryURL, httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":true}`))
+
+			failure, err := svc.ValidateCorporateBody(companyNumber, testUserID, email)
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonCompanyFiledRecently)
+			So(failure.RetryAfter, ShouldBeNil)
+		})
+
+		Convey("all checks pass", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			httpmock.RegisterResponder(http.MethodGet, filingHistoryURL, httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+
+			failure, err := svc.ValidateCorporateBody(companyNumber, testUserID, email)
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestUnitCheckOfficerSubmissions(t *testing.T) {
+	Convey("Check officer submissions", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, Config: &config.Config{OfficerSubmissionLimit: 2, AddressSubmissionWindowHours: 48}}
+		submittedAt := time.Now().Add(-time.Hour)
+
+		Convey("no officer selected", func() {
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "", "")
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("error counting officer submissions", func() {
+			mockDaoService.EXPECT().CountOfficerSubmissions("officer1", gomock.Any(), 2).Return(nil, fmt.Errorf("error"))
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(failure, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("officer limit exceeded", func() {
+			mockDaoService.EXPECT().CountOfficerSubmissions("officer1", gomock.Any(), 2).Return(&models.SubmissionCount{Count: 2, SubmittedAt: []time.Time{submittedAt, submittedAt}}, nil)
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonOfficerLimitExceeded)
+			So(failure.RetryAfter.Equal(submittedAt.Add(7*24*time.Hour)), ShouldBeTrue)
+		})
+
+		Convey("address limit exceeded", func() {
+			mockDaoService.EXPECT().CountOfficerSubmissions("officer1", gomock.Any(), 2).Return(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{submittedAt}}, nil)
+			mockDaoService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), 3).Return(&models.SubmissionCount{Count: 3, SubmittedAt: []time.Time{submittedAt, submittedAt, submittedAt}}, nil)
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonAddressLimitExceeded)
+			So(failure.RetryAfter.Equal(submittedAt.Add(48*time.Hour)), ShouldBeTrue)
+		})
+
+		Convey("exempt company not limited", func() {
+			svc.Policy = &SubmissionPolicy{ExemptCompanies: []string{companyNumber}}
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("exempt user not limited", func() {
+			svc.Policy = &SubmissionPolicy{ExemptUsers: []string{"TEST@test.com"}}
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("within both limits", func() {
+			mockDaoService.EXPECT().CountOfficerSubmissions("officer1", gomock.Any(), 2).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), 3).Return(&models.SubmissionCount{}, nil)
+
+			failure, err := svc.CheckOfficerSubmissions(companyNumber, "test@test.com", "officer1", "ura1")
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestUnitCheckSubmissionLimits(t *testing.T) {
+	Convey("Check submission limits", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, Config: &config.Config{}}
+		authCodeReq := &models.AuthCodeRequestResourceDao{
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: companyNumber,
+				OfficerID:     "officer1",
+				OfficerUraID:  "ura1",
+				CreatedBy:     models.CreatedByDao{ID: testUserID, Email: "test@test.com"},
+			},
+		}
+
+		Convey("company limit reached", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), 1).Return(&models.SubmissionCount{Count: 1}, nil)
+
+			failure, err := svc.CheckSubmissionLimits(authCodeReq)
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonCompanyRecentlyRequested)
+		})
+
+		Convey("error counting user submissions", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), 1).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("error"))
+
+			failure, err := svc.CheckSubmissionLimits(authCodeReq)
+			So(failure, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("address limit reached", func() {
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3}, nil)
+
+			failure, err := svc.CheckSubmissionLimits(authCodeReq)
+			So(err, ShouldBeNil)
+			So(failure.Code, ShouldEqual, models.ReasonAddressLimitExceeded)
+		})
+
+		Convey("within every limit", func() {
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountOfficerAddressSubmissions("ura1", gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+
+			failure, err := svc.CheckSubmissionLimits(authCodeReq)
+			So(failure, ShouldBeNil)
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestUnitCheckEligibility(t *testing.T) {
+	const email = "test@test.com"
+	filingHistoryURL := "/emergency-auth-code/company/" + companyNumber + "/efiling-status"
+	officersURL := "/emergency-auth-code/company/" + companyNumber + "/eligible-officers"
+
+	Convey("Check eligibility", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+		retryAfter := time.Now()
+
+		Convey("error checking officers", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			httpmock.RegisterResponder(http.MethodGet, filingHistoryURL, httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+
+			eligibility, err := svc.CheckEligibility(companyNumber, testUserID, email)
+			So(eligibility, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("every check is made after one fails", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 3, SubmittedAt: []time.Time{retryAfter.Add(-time.Hour), retryAfter.Add(-2 * time.Hour), retryAfter.Add(-24 * time.Hour)}}, nil)
+			httpmock.RegisterResponder(http.MethodGet, filingHistoryURL, httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":true}`))
+			httpmock.RegisterResponder(http.MethodGet, officersURL, httpmock.NewStringResponder(http.StatusNotFound, ""))
+
+			eligibility, err := svc.CheckEligibility(companyNumber, testUserID, email)
+			So(err, ShouldBeNil)
+			So(eligibility.CompanyNumber, ShouldEqual, companyNumber)
+			So(eligibility.Eligible, ShouldBeFalse)
+			So(eligibility.Checks, ShouldHaveLength, 4)
+			So(eligibility.Checks[0].Check, ShouldEqual, EligibilityCheckCompanySubmissions)
+			So(eligibility.Checks[0].Eligible, ShouldBeTrue)
+			So(eligibility.Checks[1].Failure.Code, ShouldEqual, models.ReasonUserLimitExceeded)
+			So(eligibility.Checks[2].Failure.Code, ShouldEqual, models.ReasonCompanyFiledRecently)
+			So(eligibility.Checks[3].Check, ShouldEqual, EligibilityCheckEligibleOfficers)
+			So(eligibility.Checks[3].Failure.Code, ShouldEqual, models.ReasonNoEligibleOfficers)
+		})
+
+		Convey("company is eligible", func() {
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().CountUserSubmissions(testUserID, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			httpmock.RegisterResponder(http.MethodGet, filingHistoryURL, httpmock.NewStringResponder(http.StatusOK, `{"efiling_found_in_period":false}`))
+			httpmock.RegisterResponder(http.MethodGet, officersURL, httpmock.NewStringResponder(http.StatusOK, `{"total_results":3}`))
+
+			eligibility, err := svc.CheckEligibility(companyNumber, testUserID, email)
+			So(err, ShouldBeNil)
+			So(eligibility.Eligible, ShouldBeTrue)
+			for _, check := range eligibility.Checks {
+				So(check.Eligible, ShouldBeTrue)
+				So(check.Failure, ShouldBeNil)
+			}
+		})
+	})
+}
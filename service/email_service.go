@@ -17,11 +17,12 @@ const eacReceivedAppID = "emergency-auth-code-api.emergency_auth_code_request_re
 const eacFilingDescription = "Emergency Auth Code Request"
 const eacMessageType = "emergency_auth_code_request_received"
 
-func SendEmail(emailAddress string) error {
+// NewConfirmationEmail builds the request confirmation email to be sent to the supplied email address
+func NewConfirmationEmail(emailAddress string) (*models.EmailSend, error) {
 	cfg, err := config.Get()
 	if err != nil {
 		err = fmt.Errorf("error getting config for kafka message production: [%v]", err)
-		return err
+		return nil, err
 	}
 
 	// Populate email details
@@ -35,10 +36,10 @@ func SendEmail(emailAddress string) error {
 	dataBytes, err := json.Marshal(dataFieldMessage)
 	if err != nil {
 		err = fmt.Errorf("error marshalling dataFieldMessage for emailSend: [%v]", err)
-		return err
+		return nil, err
 	}
 	messageID := "<emergency-auth-code-request." + emailAddress + strconv.Itoa(util.Random(0, 100000)) + "@companieshouse.gov.uk>"
-	emailSend := models.EmailSend{
+	emailSend := &models.EmailSend{
 		AppID:        eacReceivedAppID,
 		MessageID:    messageID,
 		MessageType:  eacMessageType,
@@ -47,6 +48,17 @@ func SendEmail(emailAddress string) error {
 		CreatedAt:    time.Now().String(),
 	}
 
+	return emailSend, nil
+}
+
+// PostEmail sends a previously built email to the CHS Kafka API
+func PostEmail(emailSend *models.EmailSend) error {
+	cfg, err := config.Get()
+	if err != nil {
+		err = fmt.Errorf("error getting config for kafka message production: [%v]", err)
+		return err
+	}
+
 	// Build email API request
 	emailSendBytes, err := json.Marshal(emailSend)
 	if err != nil {
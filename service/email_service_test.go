@@ -9,15 +9,20 @@ import (
 	"testing"
 )
 
-func TestUnitSendEmail(t *testing.T) {
+func TestUnitPostEmail(t *testing.T) {
 	// Build test config
 	cfg, _ := config.Get()
 	cfg.CHSURL = "http://local.test"
 	cfg.ChsKafkaApiURL = "http://local.test.chs.kafka"
 	cfg.APIKey = "testApiKey"
 
+	emailSend, err := NewConfirmationEmail("test@test.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	Convey("error sending email", t, func() {
-		res := SendEmail("test@test.com")
+		res := PostEmail(emailSend)
 
 		So(res.Error(), ShouldContainSubstring, "error sending email")
 	})
@@ -28,7 +33,7 @@ func TestUnitSendEmail(t *testing.T) {
 		responder := httpmock.NewStringResponder(http.StatusInternalServerError, "")
 		httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/send-email", cfg.ChsKafkaApiURL), responder)
 
-		res := SendEmail("test@test.com")
+		res := PostEmail(emailSend)
 
 		// Assert send-email endpoint was hit
 		timesHttpHit := httpmock.GetCallCountInfo()
@@ -45,7 +50,7 @@ func TestUnitSendEmail(t *testing.T) {
 		responder := httpmock.NewStringResponder(http.StatusOK, ``)
 		httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/send-email", cfg.ChsKafkaApiURL), responder)
 
-		res := SendEmail("test@test.com")
+		res := PostEmail(emailSend)
 
 		// Assert send-email endpoint was hit
 		timesHttpHit := httpmock.GetCallCountInfo()
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+const (
+	defaultPendingExpiry       = 28 * 24 * time.Hour
+	defaultSubmittingTimeout   = 15 * time.Minute
+	defaultExpirySweepInterval = time.Hour
+
+	// expirySweepBatchSize is the number of pending requests expired in each batch
+	expirySweepBatchSize = 100
+)
+
+// ExpirySweeper moves pending authcode requests which have not been submitted within the configured expiry
+// to expired, so that they can no longer be submitted. It also returns requests which have been submitting
+// for longer than the configured timeout to pending, as their submission was abandoned before anything was
+// recorded for dispatch, so that they can be submitted again. Each change is recorded in the audit trail of
+// the request if an audit DAO is supplied.
+type ExpirySweeper struct {
+	DAO      dao.AuthcodeRequestDAOService
+	AuditDAO dao.AuthcodeAuditDAOService
+	Config   *config.Config
+}
+
+// Start runs the sweeper at the configured interval until the supplied context is cancelled
+func (s *ExpirySweeper) Start(ctx context.Context) {
+	interval := defaultExpirySweepInterval
+	if s.Config.ExpirySweepIntervalMinutes > 0 {
+		interval = time.Duration(s.Config.ExpirySweepIntervalMinutes) * time.Minute
+	}
+
+	log.Info("starting expiry sweeper", log.Data{
+		"interval":           interval.String(),
+		"expiry":             s.expiry().String(),
+		"submitting_timeout": s.submittingTimeout().String(),
+	})
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("expiry sweeper stopped")
+			return
+		case <-ticker.C:
+			s.ExpirePending()
+			s.ResetStaleSubmissions()
+		}
+	}
+}
+
+// ExpirePending expires every pending request created before the expiry period, and returns the number of
+// requests expired. Requests which are submitted or cancelled while the sweep runs are left as they are.
+func (s *ExpirySweeper) ExpirePending() int {
+	createdBefore := time.Now().Add(-s.expiry())
+
+	expired := 0
+	defer func() {
+		if expired > 0 {
+			log.Info("expired pending authcode requests", log.Data{"expired": expired, "created_before": createdBefore})
+		}
+	}()
+
+	for {
+		ids, err := s.DAO.ListStalePendingAuthCodeRequestIDs(createdBefore, expirySweepBatchSize)
+		if err != nil {
+			log.Error(fmt.Errorf("error listing pending authcode requests to expire: %v", err))
+			return expired
+		}
+
+		for _, id := range ids {
+			transitioned, err := s.DAO.TransitionAuthCodeRequestStatus(id, models.StatusPending, models.StatusExpired)
+			if err != nil {
+				// stop rather than list the same requests again
+				log.Error(fmt.Errorf("error expiring authcode request: %v", err), log.Data{"auth_code_request_id": id})
+				return expired
+			}
+			if !transitioned {
+				continue
+			}
+
+			expired++
+			recordAuditEntry(s.AuditDAO, newAuditEntry(id, nil, models.AuditActionExpired, statusState(models.StatusPending), statusState(models.StatusExpired)))
+		}
+
+		if len(ids) < expirySweepBatchSize {
+			return expired
+		}
+	}
+}
+
+// ResetStaleSubmissions returns every request which moved into submitting before the submitting timeout to
+// pending, and returns the number of requests reset. A submission which completes, or is aborted, while
+// the sweep runs is left as it is.
+func (s *ExpirySweeper) ResetStaleSubmissions() int {
+	submittingBefore := time.Now().Add(-s.submittingTimeout())
+
+	reset := 0
+	defer func() {
+		if reset > 0 {
+			log.Info("returned stale submitting authcode requests to pending", log.Data{"reset": reset, "submitting_before": submittingBefore})
+		}
+	}()
+
+	for {
+		ids, err := s.DAO.ListStaleSubmittingAuthCodeRequestIDs(submittingBefore, expirySweepBatchSize)
+		if err != nil {
+			log.Error(fmt.Errorf("error listing submitting authcode requests to reset: %v", err))
+			return reset
+		}
+
+		for _, id := range ids {
+			transitioned, err := s.DAO.TransitionAuthCodeRequestStatus(id, models.StatusSubmitting, models.StatusPending)
+			if err != nil {
+				// stop rather than list the same requests again
+				log.Error(fmt.Errorf("error resetting authcode request: %v", err), log.Data{"auth_code_request_id": id})
+				return reset
+			}
+			if !transitioned {
+				continue
+			}
+
+			reset++
+			recordAuditEntry(s.AuditDAO, newAuditEntry(id, nil, models.AuditActionSubmissionAborted, statusState(models.StatusSubmitting), statusState(models.StatusPending)))
+		}
+
+		if len(ids) < expirySweepBatchSize {
+			return reset
+		}
+	}
+}
+
+// expiry returns how long after it was created a pending request is expired
+func (s *ExpirySweeper) expiry() time.Duration {
+	if s.Config != nil && s.Config.PendingExpiryDays > 0 {
+		return time.Duration(s.Config.PendingExpiryDays) * 24 * time.Hour
+	}
+	return defaultPendingExpiry
+}
+
+// submittingTimeout returns how long after submission started a submitting request is returned to pending
+func (s *ExpirySweeper) submittingTimeout() time.Duration {
+	if s.Config != nil && s.Config.SubmittingTimeoutMinutes > 0 {
+		return time.Duration(s.Config.SubmittingTimeoutMinutes) * time.Minute
+	}
+	return defaultSubmittingTimeout
+}
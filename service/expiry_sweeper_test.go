@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitExpirePending(t *testing.T) {
+	Convey("expire pending authcode requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		sweeper := ExpirySweeper{DAO: mockRequestService, Config: &config.Config{}}
+
+		Convey("error listing requests", func() {
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return(nil, fmt.Errorf("error"))
+
+			So(sweeper.ExpirePending(), ShouldEqual, 0)
+		})
+
+		Convey("requests created before default expiry are expired", func() {
+			var createdBefore time.Time
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).DoAndReturn(
+				func(before time.Time, limit int) ([]string, error) {
+					createdBefore = before
+					return []string{"request1", "request2"}, nil
+				})
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusPending, models.StatusExpired).Return(true, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request2", models.StatusPending, models.StatusExpired).Return(true, nil)
+
+			So(sweeper.ExpirePending(), ShouldEqual, 2)
+			So(createdBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-28*24*time.Hour))
+		})
+
+		Convey("configured expiry", func() {
+			sweeper.Config.PendingExpiryDays = 7
+			var createdBefore time.Time
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).DoAndReturn(
+				func(before time.Time, limit int) ([]string, error) {
+					createdBefore = before
+					return nil, nil
+				})
+
+			So(sweeper.ExpirePending(), ShouldEqual, 0)
+			So(createdBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-7*24*time.Hour))
+		})
+
+		Convey("request no longer pending is not counted", func() {
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusPending, models.StatusExpired).Return(false, nil)
+
+			So(sweeper.ExpirePending(), ShouldEqual, 0)
+		})
+
+		Convey("error expiring request stops sweep", func() {
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1", "request2"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusPending, models.StatusExpired).Return(false, fmt.Errorf("error"))
+
+			So(sweeper.ExpirePending(), ShouldEqual, 0)
+		})
+
+		Convey("full batch followed by another batch", func() {
+			batch := make([]string, expirySweepBatchSize)
+			for i := range batch {
+				batch[i] = fmt.Sprintf("request%d", i)
+			}
+			gomock.InOrder(
+				mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return(batch, nil),
+				mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"last"}, nil),
+			)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus(gomock.Any(), models.StatusPending, models.StatusExpired).Return(true, nil).Times(expirySweepBatchSize + 1)
+
+			So(sweeper.ExpirePending(), ShouldEqual, expirySweepBatchSize+1)
+		})
+
+		Convey("expiry recorded in audit trail", func() {
+			mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+			sweeper.AuditDAO = mockAuditService
+
+			var auditEntry *models.AuditEntryDao
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(func(entry *models.AuditEntryDao) error {
+				auditEntry = entry
+				return nil
+			})
+			mockRequestService.EXPECT().ListStalePendingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusPending, models.StatusExpired).Return(true, nil)
+
+			So(sweeper.ExpirePending(), ShouldEqual, 1)
+			So(auditEntry.AuthCodeRequestID, ShouldEqual, "request1")
+			So(auditEntry.Action, ShouldEqual, models.AuditActionExpired)
+			So(auditEntry.ActorType, ShouldEqual, models.ActorTypeSystem)
+			So(auditEntry.After, ShouldResemble, &models.AuditStateDao{Status: models.StatusExpired})
+		})
+	})
+}
+
+func TestUnitResetStaleSubmissions(t *testing.T) {
+	Convey("reset stale submitting authcode requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		sweeper := ExpirySweeper{DAO: mockRequestService, Config: &config.Config{}}
+
+		Convey("error listing requests", func() {
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return(nil, fmt.Errorf("error"))
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 0)
+		})
+
+		Convey("requests submitting before default timeout are returned to pending", func() {
+			var submittingBefore time.Time
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).DoAndReturn(
+				func(before time.Time, limit int) ([]string, error) {
+					submittingBefore = before
+					return []string{"request1", "request2"}, nil
+				})
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusSubmitting, models.StatusPending).Return(true, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request2", models.StatusSubmitting, models.StatusPending).Return(true, nil)
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 2)
+			So(submittingBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-15*time.Minute))
+		})
+
+		Convey("configured timeout", func() {
+			sweeper.Config.SubmittingTimeoutMinutes = 60
+			var submittingBefore time.Time
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).DoAndReturn(
+				func(before time.Time, limit int) ([]string, error) {
+					submittingBefore = before
+					return nil, nil
+				})
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 0)
+			So(submittingBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-time.Hour))
+		})
+
+		Convey("request submitted meanwhile is not counted", func() {
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusSubmitting, models.StatusPending).Return(false, nil)
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 0)
+		})
+
+		Convey("error resetting request stops sweep", func() {
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1", "request2"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusSubmitting, models.StatusPending).Return(false, fmt.Errorf("error"))
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 0)
+		})
+
+		Convey("reset recorded in audit trail", func() {
+			mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+			sweeper.AuditDAO = mockAuditService
+
+			var auditEntry *models.AuditEntryDao
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(func(entry *models.AuditEntryDao) error {
+				auditEntry = entry
+				return nil
+			})
+			mockRequestService.EXPECT().ListStaleSubmittingAuthCodeRequestIDs(gomock.Any(), expirySweepBatchSize).Return([]string{"request1"}, nil)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus("request1", models.StatusSubmitting, models.StatusPending).Return(true, nil)
+
+			So(sweeper.ResetStaleSubmissions(), ShouldEqual, 1)
+			So(auditEntry.AuthCodeRequestID, ShouldEqual, "request1")
+			So(auditEntry.Action, ShouldEqual, models.AuditActionSubmissionAborted)
+			So(auditEntry.ActorType, ShouldEqual, models.ActorTypeSystem)
+			So(auditEntry.Before, ShouldResemble, &models.AuditStateDao{Status: models.StatusSubmitting})
+			So(auditEntry.After, ShouldResemble, &models.AuditStateDao{Status: models.StatusPending})
+		})
+	})
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// ErrIdempotencyKeyUsed is returned when creating an auth code request with an idempotency key which the
+// user has already used
+var ErrIdempotencyKeyUsed = errors.New("idempotency key has already been used")
+
+// IdempotencyHash returns a hash of the body of a request to create an auth code request, so that a retry
+// of the request can be distinguished from a different request reusing its idempotency key. The hash is of
+// the decoded body, so is not affected by formatting.
+func IdempotencyHash(request *models.AuthCodeRequest) (string, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// GetIdempotentAuthCodeRequest returns the auth code request previously created or resumed by the requester
+// with the idempotency key, along with whether it was resumed rather than created, so that the response to
+// the original request can be replayed. NotFound is returned if there is none, and Unprocessable, along with
+// the request, if the key was used with a request body with a different hash.
+func (s *AuthCodeRequestService) GetIdempotentAuthCodeRequest(requester *Requester, idempotencyKey, idempotencyHash string) (*models.AuthCodeRequestResourceDao, bool, ResponseType) {
+	if requester == nil || requester.UserID == "" {
+		return nil, false, InvalidData
+	}
+
+	logContext := log.Data{"idempotency_key": idempotencyKey}
+
+	authCodeRequest, err := s.DAO.GetAuthCodeRequestByIdempotencyKey(requester.UserID, idempotencyKey)
+	if err != nil {
+		log.Error(fmt.Errorf("error getting authcode request by idempotency key: %v", err), logContext)
+		return nil, false, Error
+	}
+	if authCodeRequest == nil {
+		return nil, false, NotFound
+	}
+
+	logContext["auth_code_request_id"] = authCodeRequest.ID
+
+	var used *models.IdempotencyKeyDao
+	for i := range authCodeRequest.Data.IdempotencyKeys {
+		if authCodeRequest.Data.IdempotencyKeys[i].Key == idempotencyKey {
+			used = &authCodeRequest.Data.IdempotencyKeys[i]
+			break
+		}
+	}
+	if used == nil {
+		log.Error(fmt.Errorf("authcode request found by idempotency key does not have the key"), logContext)
+		return nil, false, Error
+	}
+
+	if used.Hash != idempotencyHash {
+		log.Info("idempotency key reused with a different request body", logContext)
+		return authCodeRequest, used.Resumed, Unprocessable
+	}
+
+	log.Info("replaying authcode request created or resumed with idempotency key", logContext)
+
+	return authCodeRequest, used.Resumed, Success
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitIdempotencyHash(t *testing.T) {
+	Convey("Idempotency hash", t, func() {
+		hash, err := IdempotencyHash(&models.AuthCodeRequest{CompanyNumber: companyNumber, OfficerID: "officer1"})
+		So(err, ShouldBeNil)
+		So(hash, ShouldHaveLength, 64)
+
+		same, _ := IdempotencyHash(&models.AuthCodeRequest{CompanyNumber: companyNumber, OfficerID: "officer1"})
+		So(same, ShouldEqual, hash)
+
+		different, _ := IdempotencyHash(&models.AuthCodeRequest{CompanyNumber: companyNumber, OfficerID: "officer2"})
+		So(different, ShouldNotEqual, hash)
+	})
+}
+
+func TestUnitGetIdempotentAuthCodeRequest(t *testing.T) {
+	Convey("Get idempotent auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+		requester := &Requester{UserID: testUserID}
+
+		existing := &models.AuthCodeRequestResourceDao{
+			ID: authCodeRequestID,
+			Data: models.AuthCodeRequestDataDao{IdempotencyKeys: []models.IdempotencyKeyDao{
+				{Key: "key1", Hash: "hash1"},
+				{Key: "key2", Hash: "hash2", Resumed: true},
+			}},
+		}
+
+		Convey("requester without user", func() {
+			authCodeRequest, _, responseType := svc.GetIdempotentAuthCodeRequest(&Requester{}, "key1", "hash1")
+			So(authCodeRequest, ShouldBeNil)
+			So(responseType, ShouldEqual, InvalidData)
+		})
+
+		Convey("error getting request", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequestByIdempotencyKey(testUserID, "key1").Return(nil, fmt.Errorf("error"))
+			_, _, responseType := svc.GetIdempotentAuthCodeRequest(requester, "key1", "hash1")
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("key not used", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequestByIdempotencyKey(testUserID, "key1").Return(nil, nil)
+			authCodeRequest, _, responseType := svc.GetIdempotentAuthCodeRequest(requester, "key1", "hash1")
+			So(authCodeRequest, ShouldBeNil)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("key used with same body", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequestByIdempotencyKey(testUserID, "key1").Return(existing, nil)
+			authCodeRequest, _, responseType := svc.GetIdempotentAuthCodeRequest(requester, "key1", "hash1")
+			So(authCodeRequest, ShouldEqual, existing)
+			So(responseType, ShouldEqual, Success)
+		})
+
+		Convey("key used with different body", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequestByIdempotencyKey(testUserID, "key1").Return(existing, nil)
+			_, _, responseType := svc.GetIdempotentAuthCodeRequest(requester, "key1", "hash2")
+			So(responseType, ShouldEqual, Unprocessable)
+		})
+	})
+}
+
+func TestUnitCreateAuthCodeRequestDuplicateIdempotencyKey(t *testing.T) {
+	Convey("Create auth code request with used idempotency key", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockDaoService.EXPECT().InsertAuthCodeRequest(gomock.Any()).Return(dao.ErrDuplicateIdempotencyKey)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+
+		err := svc.CreateAuthCodeRequest(&models.AuthCodeRequestResourceDao{}, &Requester{UserID: testUserID})
+		So(err, ShouldEqual, ErrIdempotencyKeyUsed)
+	})
+}
+
+func TestUnitCreateAuthCodeRequest(t *testing.T) {
+	Convey("Create auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+
+		Convey("company number is stored in upper case", func() {
+			var inserted *models.AuthCodeRequestResourceDao
+			mockDaoService.EXPECT().InsertAuthCodeRequest(gomock.Any()).DoAndReturn(func(dao *models.AuthCodeRequestResourceDao) error {
+				inserted = dao
+				return nil
+			})
+
+			err := svc.CreateAuthCodeRequest(&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{CompanyNumber: "sc123456"}}, &Requester{UserID: testUserID})
+			So(err, ShouldBeNil)
+			So(inserted.Data.CompanyNumber, ShouldEqual, "SC123456")
+		})
+	})
+}
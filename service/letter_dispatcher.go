@@ -0,0 +1,192 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/authcodeapi"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+// LetterDispatcher sends the letter for an authcode request to a letter backend
+type LetterDispatcher interface {
+	// Backend identifies the letter backend, as recorded against each letter dispatched
+	Backend() models.LetterBackend
+	// DispatchLetter sends the item to the letter backend, returning the acknowledgement it returned
+	DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error)
+}
+
+// LetterValidator is a letter backend with a dry run, which can be used as a shadow backend
+type LetterValidator interface {
+	LetterDispatcher
+	// ValidateLetter reports whether the letter backend would accept the item, without sending the letter
+	ValidateLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error)
+}
+
+// NewLetterDispatcher returns the letter dispatcher for the letter backend selected in the config. If no
+// backend is selected, the AuthCode API is used when NewAuthCodeAPIFlow is set and the queue API if not.
+// If a different shadow backend is also selected, every letter is validated by the shadow backend too, which
+// is only allowed for backends with a dry run.
+func NewLetterDispatcher(cfg *config.Config) (LetterDispatcher, error) {
+	backend := models.LetterBackend(cfg.LetterBackend)
+	if backend == "" {
+		backend = models.LetterBackendQueueAPI
+		if cfg.NewAuthCodeAPIFlow {
+			backend = models.LetterBackendAuthCodeAPI
+		}
+	}
+
+	primary, err := newLetterBackendDispatcher(cfg, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	shadowBackend := models.LetterBackend(cfg.LetterShadowBackend)
+	if shadowBackend == "" || shadowBackend == backend {
+		return primary, nil
+	}
+
+	shadowDispatcher, err := newLetterBackendDispatcher(cfg, shadowBackend)
+	if err != nil {
+		return nil, fmt.Errorf("error configuring shadow letter backend: %v", err)
+	}
+
+	shadow, ok := shadowDispatcher.(LetterValidator)
+	if !ok {
+		return nil, fmt.Errorf("letter backend [%s] has no dry run so cannot be used as a shadow letter backend", shadowBackend)
+	}
+
+	return &ShadowLetterDispatcher{Primary: primary, Shadow: shadow}, nil
+}
+
+// newLetterBackendDispatcher returns the letter dispatcher for a single letter backend
+func newLetterBackendDispatcher(cfg *config.Config, backend models.LetterBackend) (LetterDispatcher, error) {
+	switch backend {
+	case models.LetterBackendQueueAPI:
+		return &QueueAPILetterDispatcher{
+			URL:    cfg.QueueAPILocalURL,
+			Path:   cfg.QueueAPILocalPath,
+			APIKey: cfg.APIKey,
+		}, nil
+	case models.LetterBackendAuthCodeAPI:
+		return &AuthCodeAPILetterDispatcher{
+			URL:        cfg.AuthCodeAPILocalURL,
+			PathFormat: cfg.AuthCodeAPILocalPath,
+			APIKey:     cfg.APIKey,
+		}, nil
+	case models.LetterBackendFile:
+		if cfg.LetterFilePath == "" {
+			return nil, fmt.Errorf("letter file path must be configured for the [%s] letter backend", backend)
+		}
+		return &FileLetterDispatcher{Path: cfg.LetterFilePath}, nil
+	default:
+		return nil, fmt.Errorf("unknown letter backend [%s]", backend)
+	}
+}
+
+// QueueAPILetterDispatcher sends letters to the queue API
+type QueueAPILetterDispatcher struct {
+	URL    string
+	Path   string
+	APIKey string
+}
+
+// Backend identifies the queue API as the letter backend
+func (d *QueueAPILetterDispatcher) Backend() models.LetterBackend {
+	return models.LetterBackendQueueAPI
+}
+
+// DispatchLetter sends the item to the queue API
+func (d *QueueAPILetterDispatcher) DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	return authcodeapi.NewClient(d.URL, d.Path, d.APIKey).SendAuthCodeItem(item, authCodeRequestID)
+}
+
+// AuthCodeAPILetterDispatcher sends letters to the AuthCode API. PathFormat is formatted with the company
+// number of each letter.
+type AuthCodeAPILetterDispatcher struct {
+	URL        string
+	PathFormat string
+	APIKey     string
+}
+
+// Backend identifies the AuthCode API as the letter backend
+func (d *AuthCodeAPILetterDispatcher) Backend() models.LetterBackend {
+	return models.LetterBackendAuthCodeAPI
+}
+
+// DispatchLetter sends the item to the AuthCode API
+func (d *AuthCodeAPILetterDispatcher) DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	path := fmt.Sprintf(d.PathFormat, item.CompanyNumber)
+	return authcodeapi.NewClient(d.URL, path, d.APIKey).SendAuthCodeItem(item, authCodeRequestID)
+}
+
+// FileLetterDispatcher appends letters to a local file, one JSON document per line, so that the service can
+// be run in development and tests without a letter backend
+type FileLetterDispatcher struct {
+	Path string
+
+	mtx sync.Mutex
+}
+
+// fileLetter is a letter as written to the file by the FileLetterDispatcher
+type fileLetter struct {
+	LetterReference   string               `json:"letter_reference"`
+	AuthCodeRequestID string               `json:"auth_code_request_id"`
+	DispatchedAt      time.Time            `json:"dispatched_at"`
+	Item              *models.AuthCodeItem `json:"item"`
+}
+
+// Backend identifies the file as the letter backend
+func (d *FileLetterDispatcher) Backend() models.LetterBackend {
+	return models.LetterBackendFile
+}
+
+// DispatchLetter appends the item to the file, acknowledging it with a generated letter reference
+func (d *FileLetterDispatcher) DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	letter := fileLetter{
+		LetterReference:   utils.GenerateID(),
+		AuthCodeRequestID: authCodeRequestID,
+		DispatchedAt:      time.Now().Truncate(time.Millisecond),
+		Item:              item,
+	}
+
+	line, err := json.Marshal(letter)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	file, err := os.OpenFile(d.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("error opening letter file: %v", err)
+	}
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("error writing letter file: %v", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("error closing letter file: %v", err)
+	}
+
+	return &models.AuthCodeItemResponse{
+		LetterReference: letter.LetterReference,
+		CreatedAt:       &letter.DispatchedAt,
+	}, nil
+}
+
+// ValidateLetter accepts any item which can be written to the file, without writing it
+func (d *FileLetterDispatcher) ValidateLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	if _, err := json.Marshal(fileLetter{AuthCodeRequestID: authCodeRequestID, Item: item}); err != nil {
+		return nil, err
+	}
+	return &models.AuthCodeItemResponse{}, nil
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitNewLetterDispatcher(t *testing.T) {
+	Convey("new letter dispatcher", t, func() {
+		cfg := &config.Config{
+			QueueAPILocalURL:     "http://queue.test",
+			QueueAPILocalPath:    "/api/queue/authcode",
+			AuthCodeAPILocalURL:  "http://authcode.test",
+			AuthCodeAPILocalPath: "/company/%s/auth-code",
+		}
+
+		Convey("queue API used when no backend configured and not new AuthCode API flow", func() {
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			So(dispatcher.Backend(), ShouldEqual, models.LetterBackendQueueAPI)
+		})
+
+		Convey("AuthCode API used when no backend configured and new AuthCode API flow", func() {
+			cfg.NewAuthCodeAPIFlow = true
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			So(dispatcher.Backend(), ShouldEqual, models.LetterBackendAuthCodeAPI)
+		})
+
+		Convey("configured backend used in preference to new AuthCode API flow", func() {
+			cfg.NewAuthCodeAPIFlow = true
+			cfg.LetterBackend = "queue-api"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			So(dispatcher.Backend(), ShouldEqual, models.LetterBackendQueueAPI)
+		})
+
+		Convey("file backend", func() {
+			cfg.LetterBackend = "file"
+			cfg.LetterFilePath = "letters.jsonl"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			So(dispatcher.Backend(), ShouldEqual, models.LetterBackendFile)
+		})
+
+		Convey("file backend without file path", func() {
+			cfg.LetterBackend = "file"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(dispatcher, ShouldBeNil)
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("unknown backend", func() {
+			cfg.LetterBackend = "pigeon"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(dispatcher, ShouldBeNil)
+			So(err.Error(), ShouldEqual, "unknown letter backend [pigeon]")
+		})
+	})
+}
+
+func TestUnitAPILetterDispatchers(t *testing.T) {
+	Convey("dispatch letter to API", t, func() {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		item := &models.AuthCodeItem{CompanyNumber: companyNumber, Status: "apply"}
+
+		Convey("queue API", func() {
+			httpmock.RegisterResponder(http.MethodPost, "http://queue.test/api/queue/authcode",
+				httpmock.NewStringResponder(http.StatusCreated, `{"queue_item_id":"queue123"}`))
+			dispatcher := &QueueAPILetterDispatcher{URL: "http://queue.test", Path: "/api/queue/authcode"}
+
+			resp, err := dispatcher.DispatchLetter(item, authCodeRequestID)
+			So(err, ShouldBeNil)
+			So(resp.QueueItemID, ShouldEqual, "queue123")
+		})
+
+		Convey("AuthCode API path includes company number", func() {
+			httpmock.RegisterResponder(http.MethodPost, "http://authcode.test/company/"+companyNumber+"/auth-code",
+				httpmock.NewStringResponder(http.StatusCreated, `{"letter_reference":"LTR123"}`))
+			dispatcher := &AuthCodeAPILetterDispatcher{URL: "http://authcode.test", PathFormat: "/company/%s/auth-code"}
+
+			resp, err := dispatcher.DispatchLetter(item, authCodeRequestID)
+			So(err, ShouldBeNil)
+			So(resp.LetterReference, ShouldEqual, "LTR123")
+		})
+
+		Convey("error response", func() {
+			httpmock.RegisterResponder(http.MethodPost, "http://queue.test/api/queue/authcode",
+				httpmock.NewStringResponder(http.StatusInternalServerError, `{}`))
+			dispatcher := &QueueAPILetterDispatcher{URL: "http://queue.test", Path: "/api/queue/authcode"}
+
+			_, err := dispatcher.DispatchLetter(item, authCodeRequestID)
+			So(err, ShouldNotBeNil)
+		})
+	})
+}
+
+func TestUnitFileLetterDispatcher(t *testing.T) {
+	Convey("dispatch letter to file", t, func() {
+		path := filepath.Join(t.TempDir(), "letters.jsonl")
+		dispatcher := &FileLetterDispatcher{Path: path}
+
+		first, err := dispatcher.DispatchLetter(&models.AuthCodeItem{CompanyNumber: "11111111"}, "request1")
+		So(err, ShouldBeNil)
+		So(first.LetterReference, ShouldNotBeEmpty)
+		So(first.CreatedAt, ShouldNotBeNil)
+
+		second, err := dispatcher.DispatchLetter(&models.AuthCodeItem{CompanyNumber: "22222222"}, "request2")
+		So(err, ShouldBeNil)
+		So(second.LetterReference, ShouldNotEqual, first.LetterReference)
+
+		file, err := os.Open(path)
+		So(err, ShouldBeNil)
+		defer file.Close()
+
+		var letters []fileLetter
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var letter fileLetter
+			So(json.Unmarshal(scanner.Bytes(), &letter), ShouldBeNil)
+			letters = append(letters, letter)
+		}
+
+		So(letters, ShouldHaveLength, 2)
+		So(letters[0].LetterReference, ShouldEqual, first.LetterReference)
+		So(letters[0].AuthCodeRequestID, ShouldEqual, "request1")
+		So(letters[0].Item.CompanyNumber, ShouldEqual, "11111111")
+		So(letters[1].AuthCodeRequestID, ShouldEqual, "request2")
+		So(letters[1].Item.CompanyNumber, ShouldEqual, "22222222")
+	})
+
+	Convey("error opening letter file", t, func() {
+		dispatcher := &FileLetterDispatcher{Path: filepath.Join(t.TempDir(), "missing", "letters.jsonl")}
+
+		resp, err := dispatcher.DispatchLetter(&models.AuthCodeItem{}, "request1")
+		So(resp, ShouldBeNil)
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestUnitValidateLetter(t *testing.T) {
+	Convey("validate letter", t, func() {
+		item := &models.AuthCodeItem{CompanyNumber: companyNumber}
+
+		Convey("file does not write letter", func() {
+			path := filepath.Join(t.TempDir(), "letters.jsonl")
+			dispatcher := &FileLetterDispatcher{Path: path}
+
+			_, err := dispatcher.ValidateLetter(item, authCodeRequestID)
+			So(err, ShouldBeNil)
+			_, err = os.Stat(path)
+			So(os.IsNotExist(err), ShouldBeTrue)
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// RecordLetterEvent records a stage reached by the letter for a submitted authcode request, as reported
+// by the AuthCode API, and moves the request on to the status for that stage. Events reported out of
+// order, or more than once, are recorded without moving the status back. NotFound is returned if there is
+// no such request, and Conflict if it has not been submitted or its status changed while recording.
+func (s *AuthCodeRequestService) RecordLetterEvent(authCodeRequestID string, eventRequest *models.LetterEventRequest, requester *Requester) ResponseType {
+	receivedAt := time.Now().Truncate(time.Millisecond)
+
+	event := models.LetterEventDao{
+		Type:       models.LetterEventType(eventRequest.Type),
+		Reference:  eventRequest.Reference,
+		Detail:     eventRequest.Detail,
+		OccurredAt: eventRequest.OccurredAt,
+		ReceivedAt: &receivedAt,
+		ReportedBy: requester.actor(),
+	}
+	if event.OccurredAt == nil {
+		event.OccurredAt = &receivedAt
+	}
+
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID, "letter_event": event.Type}
+
+	authCodeRequest, responseType := s.GetSubmittedAuthCodeRequest(authCodeRequestID)
+	if responseType != Success {
+		return responseType
+	}
+
+	fromStatus := authCodeRequest.Data.Status
+	toStatus := event.Type.Status()
+	if !fromStatus.CanTransitionTo(toStatus) {
+		toStatus = fromStatus
+	}
+
+	recorded, err := s.DAO.RecordLetterEvent(authCodeRequestID, fromStatus, toStatus, event)
+	if err != nil {
+		log.Error(fmt.Errorf("error recording letter event: %v", err), logContext)
+		return Error
+	}
+
+	// the status of the request has changed since it was read
+	if !recorded {
+		log.Info("authcode request status changed so letter event not recorded", logContext)
+		return Conflict
+	}
+
+	log.Info("letter event recorded for authcode request", logContext)
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionLetterEvent, statusState(fromStatus), statusState(toStatus))
+
+	return Success
+}
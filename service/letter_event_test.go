@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRecordLetterEvent(t *testing.T) {
+	Convey("Record letter event", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+
+		requester := &Requester{UserID: testUserID, APIKey: true, APIKeyID: "authcode-api-key", Elevated: true}
+		inStatus := func(status models.RequestStatus) *models.AuthCodeRequestResourceDao {
+			return &models.AuthCodeRequestResourceDao{ID: authCodeRequestID, Data: models.AuthCodeRequestDataDao{Status: status}}
+		}
+
+		Convey("request not found", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(nil, nil)
+
+			So(svc.RecordLetterEvent(authCodeRequestID, &models.LetterEventRequest{Type: "printed"}, requester), ShouldEqual, NotFound)
+		})
+
+		Convey("request not submitted", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusPending), nil)
+
+			So(svc.RecordLetterEvent(authCodeRequestID, &models.LetterEventRequest{Type: "printed"}, requester), ShouldEqual, Conflict)
+		})
+
+		Convey("event moves request on", func() {
+			occurredAt := time.Date(2020, 5, 12, 9, 0, 0, 0, time.UTC)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusDispatched), nil)
+			mockDaoService.EXPECT().RecordLetterEvent(authCodeRequestID, models.StatusDispatched, models.StatusPosted, gomock.Any()).DoAndReturn(
+				func(_ string, _, _ models.RequestStatus, event models.LetterEventDao) (bool, error) {
+					So(event.Type, ShouldEqual, models.LetterEventDispatched)
+					So(event.Reference, ShouldEqual, "LTR1")
+					So(*event.OccurredAt, ShouldEqual, occurredAt)
+					So(event.ReceivedAt, ShouldNotBeNil)
+					So(event.ReportedBy.ID, ShouldEqual, "authcode-api-key")
+					return true, nil
+				})
+
+			eventRequest := &models.LetterEventRequest{Type: "dispatched", Reference: "LTR1", OccurredAt: &occurredAt}
+			So(svc.RecordLetterEvent(authCodeRequestID, eventRequest, requester), ShouldEqual, Success)
+		})
+
+		Convey("event received out of order does not move request back", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusPosted), nil)
+			mockDaoService.EXPECT().RecordLetterEvent(authCodeRequestID, models.StatusPosted, models.StatusPosted, gomock.Any()).DoAndReturn(
+				func(_ string, _, _ models.RequestStatus, event models.LetterEventDao) (bool, error) {
+					So(event.OccurredAt, ShouldEqual, event.ReceivedAt)
+					return true, nil
+				})
+
+			So(svc.RecordLetterEvent(authCodeRequestID, &models.LetterEventRequest{Type: "printed"}, requester), ShouldEqual, Success)
+		})
+
+		Convey("late event does not move returned request on", func() {
+			occurredAt := time.Date(2020, 5, 12, 9, 0, 0, 0, time.UTC)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusReturned), nil)
+			mockDaoService.EXPECT().RecordLetterEvent(authCodeRequestID, models.StatusReturned, models.StatusReturned, gomock.Any()).Return(true, nil)
+
+			eventRequest := &models.LetterEventRequest{Type: "printed", OccurredAt: &occurredAt}
+			So(svc.RecordLetterEvent(authCodeRequestID, eventRequest, requester), ShouldEqual, Success)
+		})
+
+		Convey("status changed while recording", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusSubmitted), nil)
+			mockDaoService.EXPECT().RecordLetterEvent(authCodeRequestID, models.StatusSubmitted, models.StatusPrinted, gomock.Any()).Return(false, nil)
+
+			So(svc.RecordLetterEvent(authCodeRequestID, &models.LetterEventRequest{Type: "printed"}, requester), ShouldEqual, Conflict)
+		})
+
+		Convey("error recording event", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(inStatus(models.StatusPrinted), nil)
+			mockDaoService.EXPECT().RecordLetterEvent(authCodeRequestID, models.StatusPrinted, models.StatusReturned, gomock.Any()).Return(false, fmt.Errorf("error"))
+
+			So(svc.RecordLetterEvent(authCodeRequestID, &models.LetterEventRequest{Type: "returned-undelivered"}, requester), ShouldEqual, Error)
+		})
+	})
+}
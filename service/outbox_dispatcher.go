@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/utils"
+)
+
+const (
+	defaultOutboxDispatchInterval = 10 * time.Second
+	defaultOutboxMaxAttempts      = 10
+
+	// outboxLeaseDuration is how long a claimed item is hidden from other dispatchers while it is delivered
+	outboxLeaseDuration = 5 * time.Minute
+
+	outboxRetryBaseDelay = 30 * time.Second
+	outboxRetryMaxDelay  = time.Hour
+)
+
+// OutboxDispatcher delivers the letters and emails recorded in the outbox when authcode requests are
+// submitted. Items are delivered at least once: an item which is delivered but cannot then be marked
+// as done will be delivered again once its lease expires. Once a letter has been delivered, or will
+// no longer be retried, the status of its authcode request is updated to reflect this, and recorded in
+// its audit trail if an audit DAO is supplied.
+// Letters are sent using the LetterDispatcher, which is chosen from the config if one is not supplied.
+type OutboxDispatcher struct {
+	DAO              dao.AuthcodeOutboxDAOService
+	RequestDAO       dao.AuthcodeRequestDAOService
+	AuditDAO         dao.AuthcodeAuditDAOService
+	LetterDispatcher LetterDispatcher
+	Config           *config.Config
+}
+
+// Start runs the dispatcher at the configured interval until the supplied context is cancelled
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	interval := defaultOutboxDispatchInterval
+	if d.Config.OutboxDispatchIntervalSeconds > 0 {
+		interval = time.Duration(d.Config.OutboxDispatchIntervalSeconds) * time.Second
+	}
+
+	log.Info("starting outbox dispatcher", log.Data{"interval": interval.String()})
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+			d.DispatchPending()
+		}
+	}
+}
+
+// DispatchPending attempts delivery of every outbox item which is currently due, and returns the
+// number of items successfully delivered
+func (d *OutboxDispatcher) DispatchPending() int {
+	delivered := 0
+	for {
+		item, err := d.DAO.ClaimOutboxItem(outboxLeaseDuration)
+		if err != nil {
+			log.Error(fmt.Errorf("error claiming outbox item: %v", err))
+			return delivered
+		}
+		if item == nil {
+			return delivered
+		}
+
+		if d.dispatch(item) {
+			delivered++
+		}
+	}
+}
+
+// dispatch delivers a single claimed outbox item, recording the outcome against the item
+func (d *OutboxDispatcher) dispatch(item *models.OutboxItemDao) bool {
+	logContext := log.Data{
+		"outbox_item_id":       item.ID,
+		"auth_code_request_id": item.AuthCodeRequestID,
+		"type":                 item.Type,
+		"attempts":             item.Attempts,
+	}
+
+	err := d.deliver(item)
+	if err == nil {
+		if err := d.DAO.CompleteOutboxItem(item.ID); err != nil {
+			log.Error(fmt.Errorf("error marking outbox item as done: %v", err), logContext)
+		}
+		log.Info("outbox item delivered", logContext)
+		d.updateRequestStatus(item, models.StatusDispatched, logContext)
+		return true
+	}
+
+	log.Error(fmt.Errorf("error delivering outbox item: %v", err), logContext)
+
+	if item.Attempts >= d.maxAttempts() {
+		if err := d.DAO.FailOutboxItem(item.ID, err.Error()); err != nil {
+			log.Error(fmt.Errorf("error marking outbox item as failed: %v", err), logContext)
+		}
+		log.Error(fmt.Errorf("outbox item will not be retried"), logContext)
+		d.updateRequestStatus(item, models.StatusFailed, logContext)
+		return false
+	}
+
+	nextAttemptAt := time.Now().Add(outboxRetryDelay(item.Attempts)).Truncate(time.Millisecond)
+	if err := d.DAO.RetryOutboxItem(item.ID, err.Error(), nextAttemptAt); err != nil {
+		log.Error(fmt.Errorf("error scheduling retry of outbox item: %v", err), logContext)
+	}
+
+	return false
+}
+
+// deliver sends an outbox item to the API responsible for it
+func (d *OutboxDispatcher) deliver(item *models.OutboxItemDao) error {
+	switch item.Type {
+	case models.OutboxTypeLetter:
+		if item.AuthCodeItem == nil {
+			return fmt.Errorf("letter outbox item has no authcode item")
+		}
+		letterDispatcher, err := d.letterDispatcher()
+		if err != nil {
+			return err
+		}
+		resp, err := letterDispatcher.DispatchLetter(item.AuthCodeItem, item.AuthCodeRequestID)
+		// Only the first attempt is shadowed, so that each letter is compared once however often it is retried
+		if shadow, ok := letterDispatcher.(*ShadowLetterDispatcher); ok && item.Attempts == 1 {
+			shadow.ShadowLetter(item.AuthCodeItem, item.AuthCodeRequestID, err)
+		}
+		if err != nil {
+			return err
+		}
+		d.recordLetterDispatch(item, models.NewLetterDispatch(letterDispatcher.Backend(), resp))
+		return nil
+	case models.OutboxTypeEmail:
+		if item.EmailSend == nil {
+			return fmt.Errorf("email outbox item has no email")
+		}
+		return PostEmail(item.EmailSend)
+	default:
+		return fmt.Errorf("unknown outbox item type [%s]", item.Type)
+	}
+}
+
+// recordLetterDispatch stores the acknowledgement of a delivered letter against its authcode request, so
+// that it can be reconciled with the letter system. The letter has already been delivered, so a failure
+// to store it is logged rather than returned.
+func (d *OutboxDispatcher) recordLetterDispatch(item *models.OutboxItemDao, dispatch *models.LetterDispatchDao) {
+	dispatch.OutboxItemID = item.ID
+
+	if err := d.RequestDAO.RecordLetterDispatch(item.AuthCodeRequestID, *dispatch); err != nil {
+		log.Error(fmt.Errorf("error recording letter dispatch: %v", err), log.Data{
+			"outbox_item_id":       item.ID,
+			"auth_code_request_id": item.AuthCodeRequestID,
+			"backend":              dispatch.Backend,
+		})
+	}
+}
+
+// updateRequestStatus moves the authcode request of a letter outbox item on from submitted, once
+// delivery of the letter has succeeded or been abandoned
+func (d *OutboxDispatcher) updateRequestStatus(item *models.OutboxItemDao, toStatus models.RequestStatus, logContext log.Data) {
+	if item.Type != models.OutboxTypeLetter {
+		return
+	}
+
+	transitioned, err := d.RequestDAO.TransitionAuthCodeRequestStatus(item.AuthCodeRequestID, models.StatusSubmitted, toStatus)
+	if err != nil {
+		log.Error(fmt.Errorf("error updating status of authcode request to [%s]: %v", toStatus, err), logContext)
+		return
+	}
+	if !transitioned {
+		log.Info("authcode request is no longer submitted so status not updated", logContext)
+		return
+	}
+
+	action := models.AuditActionDispatched
+	if toStatus == models.StatusFailed {
+		action = models.AuditActionDispatchFailed
+	}
+	recordAuditEntry(d.AuditDAO, newAuditEntry(item.AuthCodeRequestID, nil, action, statusState(models.StatusSubmitted), statusState(toStatus)))
+}
+
+// letterDispatcher returns the dispatcher used to send letters, which is taken from the config if one has
+// not been supplied
+func (d *OutboxDispatcher) letterDispatcher() (LetterDispatcher, error) {
+	if d.LetterDispatcher != nil {
+		return d.LetterDispatcher, nil
+	}
+	return NewLetterDispatcher(d.Config)
+}
+
+func (d *OutboxDispatcher) maxAttempts() int {
+	if d.Config.OutboxMaxAttempts > 0 {
+		return d.Config.OutboxMaxAttempts
+	}
+	return defaultOutboxMaxAttempts
+}
+
+// outboxRetryDelay returns how long to wait before retrying an item, doubling with every attempt
+func outboxRetryDelay(attempts int) time.Duration {
+	delay := outboxRetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= outboxRetryMaxDelay {
+			return outboxRetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// newOutboxItem creates an outbox item of the supplied type for an authcode request, due for immediate delivery
+func newOutboxItem(authCodeRequestID, itemType string) models.OutboxItemDao {
+	createdAt := time.Now().Truncate(time.Millisecond)
+
+	return models.OutboxItemDao{
+		ID:                utils.GenerateID(),
+		AuthCodeRequestID: authCodeRequestID,
+		Type:              itemType,
+		Status:            models.OutboxStatusPending,
+		CreatedAt:         &createdAt,
+		NextAttemptAt:     &createdAt,
+	}
+}
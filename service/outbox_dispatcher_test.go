@@ -0,0 +1,262 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testOutboxItemID = "outbox123"
+
+func letterOutboxItem(attempts int) *models.OutboxItemDao {
+	return &models.OutboxItemDao{
+		ID:                testOutboxItemID,
+		AuthCodeRequestID: authCodeRequestID,
+		Type:              models.OutboxTypeLetter,
+		AuthCodeItem: &models.AuthCodeItem{
+			CompanyNumber: companyNumber,
+			Status:        "apply",
+		},
+		Status:   models.OutboxStatusPending,
+		Attempts: attempts,
+	}
+}
+
+func TestUnitDispatchPendingQueueAPIAuthCodeFlow(t *testing.T) {
+	Convey("dispatch pending outbox items", t, func() {
+		// build test config
+		cfg, _ := config.Get()
+		cfg.NewAuthCodeAPIFlow = false
+		cfg.QueueAPILocalURL = "http://local.test"
+		cfg.QueueAPILocalPath = "/api/queue/authcode"
+		cfg.OutboxMaxAttempts = 3
+
+		Convey("error claiming outbox item", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, fmt.Errorf("error"))
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, Config: cfg}
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 0)
+		})
+
+		Convey("error sending queue item - retry scheduled", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(1), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().RetryOutboxItem(testOutboxItemID, gomock.Any(), gomock.Any()).Return(nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, Config: cfg}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			queueAPIResponder := httpmock.NewStringResponder(http.StatusInternalServerError, `{}`)
+			httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 0)
+		})
+
+		Convey("error sending queue item - attempts exhausted", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(3), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().FailOutboxItem(testOutboxItemID, gomock.Any()).Return(nil)
+			mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitted, models.StatusFailed).Return(true, nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, RequestDAO: mockRequestService, Config: cfg}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			queueAPIResponder := httpmock.NewStringResponder(http.StatusInternalServerError, `{}`)
+			httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 0)
+		})
+
+		Convey("send queue item - success", func() {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(1), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().CompleteOutboxItem(testOutboxItemID).Return(nil)
+			mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockRequestService.EXPECT().RecordLetterDispatch(authCodeRequestID, gomock.Any()).DoAndReturn(func(_ string, dispatch models.LetterDispatchDao) error {
+				So(dispatch.Backend, ShouldEqual, models.LetterBackendQueueAPI)
+				So(dispatch.OutboxItemID, ShouldEqual, testOutboxItemID)
+				So(dispatch.StatusCode, ShouldEqual, http.StatusOK)
+				So(dispatch.QueueItemID, ShouldEqual, "queue123")
+				So(dispatch.DispatchedAt, ShouldNotBeNil)
+				return nil
+			})
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitted, models.StatusDispatched).Return(true, nil)
+			mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+			var auditEntry *models.AuditEntryDao
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(func(entry *models.AuditEntryDao) error {
+				auditEntry = entry
+				return nil
+			})
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, RequestDAO: mockRequestService, AuditDAO: mockAuditService, Config: cfg}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			queueAPIResponder := httpmock.NewStringResponder(http.StatusOK, `{"queue_item_id":"queue123"}`)
+			httpmock.RegisterResponder(http.MethodPost, cfg.QueueAPILocalPath, queueAPIResponder)
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 1)
+			So(auditEntry.Action, ShouldEqual, models.AuditActionDispatched)
+			So(auditEntry.ActorType, ShouldEqual, models.ActorTypeSystem)
+			So(auditEntry.Actor.ID, ShouldEqual, systemActorID)
+			So(auditEntry.After.Status, ShouldEqual, models.StatusDispatched)
+		})
+	})
+}
+
+func TestUnitDispatchPendingAuthCodeAPIAuthCodeFlow(t *testing.T) {
+	Convey("dispatch pending outbox items", t, func() {
+		Convey("send authcode item - success", func() {
+			// build test config
+			cfg, _ := config.Get()
+			cfg.NewAuthCodeAPIFlow = true
+			cfg.AuthCodeAPILocalURL = "http://local.test"
+			cfg.AuthCodeAPILocalPath = "/private/company/%s/authcode/request"
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(1), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().CompleteOutboxItem(testOutboxItemID).Return(nil)
+			mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockRequestService.EXPECT().RecordLetterDispatch(authCodeRequestID, gomock.Any()).DoAndReturn(func(_ string, dispatch models.LetterDispatchDao) error {
+				So(dispatch.Backend, ShouldEqual, models.LetterBackendAuthCodeAPI)
+				So(dispatch.LetterReference, ShouldEqual, "LTR123")
+				return nil
+			})
+			mockRequestService.EXPECT().TransitionAuthCodeRequestStatus(authCodeRequestID, models.StatusSubmitted, models.StatusDispatched).Return(true, nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, RequestDAO: mockRequestService, Config: cfg}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			authCodeAPIResponder := httpmock.NewStringResponder(http.StatusCreated, `{"letter_reference":"LTR123"}`)
+			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf(cfg.AuthCodeAPILocalPath, companyNumber), authCodeAPIResponder)
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestUnitDispatchPendingEmail(t *testing.T) {
+	Convey("dispatch pending outbox items", t, func() {
+		Convey("send email item - success", func() {
+			// build test config
+			cfg, _ := config.Get()
+			cfg.ChsKafkaApiURL = "http://local.test.chs.kafka"
+
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			emailItem := &models.OutboxItemDao{
+				ID:                testOutboxItemID,
+				AuthCodeRequestID: authCodeRequestID,
+				Type:              models.OutboxTypeEmail,
+				EmailSend:         &models.EmailSend{EmailAddress: "test@test.com"},
+				Status:            models.OutboxStatusPending,
+				Attempts:          1,
+			}
+
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(emailItem, nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().CompleteOutboxItem(testOutboxItemID).Return(nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, Config: cfg}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+			responder := httpmock.NewStringResponder(http.StatusOK, ``)
+			httpmock.RegisterResponder(http.MethodPost, fmt.Sprintf("%s/send-email", cfg.ChsKafkaApiURL), responder)
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 1)
+		})
+	})
+}
+
+func TestUnitOutboxRetryDelay(t *testing.T) {
+	Convey("outbox retry delay", t, func() {
+		So(outboxRetryDelay(1), ShouldEqual, 30*time.Second)
+		So(outboxRetryDelay(2), ShouldEqual, time.Minute)
+		So(outboxRetryDelay(3), ShouldEqual, 2*time.Minute)
+		So(outboxRetryDelay(20), ShouldEqual, time.Hour)
+	})
+}
+
+func TestUnitDispatchPendingShadowLetter(t *testing.T) {
+	Convey("dispatch pending letter with shadow backend", t, func() {
+		cfg := &config.Config{OutboxMaxAttempts: 3}
+		primary := &stubLetterDispatcher{backend: models.LetterBackendQueueAPI, dispatchErr: fmt.Errorf("error")}
+		shadow := &stubLetterDispatcher{backend: models.LetterBackendFile}
+		letterDispatcher := &ShadowLetterDispatcher{Primary: primary, Shadow: shadow}
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		Convey("first attempt shadowed", func() {
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(1), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().RetryOutboxItem(testOutboxItemID, gomock.Any(), gomock.Any()).Return(nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, LetterDispatcher: letterDispatcher, Config: cfg}
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 0)
+			letterDispatcher.Wait()
+			So(primary.dispatched, ShouldEqual, 1)
+			So(shadow.validated, ShouldEqual, 1)
+			So(letterDispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 1})
+		})
+
+		Convey("retry not shadowed again", func() {
+			mockOutboxService := mocks.NewMockAuthcodeOutboxDAOService(mockCtrl)
+			gomock.InOrder(
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(letterOutboxItem(2), nil),
+				mockOutboxService.EXPECT().ClaimOutboxItem(gomock.Any()).Return(nil, nil),
+			)
+			mockOutboxService.EXPECT().RetryOutboxItem(testOutboxItemID, gomock.Any(), gomock.Any()).Return(nil)
+			dispatcher := OutboxDispatcher{DAO: mockOutboxService, LetterDispatcher: letterDispatcher, Config: cfg}
+
+			So(dispatcher.DispatchPending(), ShouldEqual, 0)
+			letterDispatcher.Wait()
+			So(primary.dispatched, ShouldEqual, 1)
+			So(shadow.validated, ShouldEqual, 0)
+			So(letterDispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{})
+		})
+	})
+}
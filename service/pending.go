@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/oracle"
+)
+
+// defaultPendingResumeWindow is how long after it was created a pending request may be resumed
+const defaultPendingResumeWindow = 24 * time.Hour
+
+// GetPendingAuthCodeRequest returns the requester's most recently created pending authcode request for the
+// company, if it was created recently enough to be resumed. NotFound is returned if there is no such request.
+func (s *AuthCodeRequestService) GetPendingAuthCodeRequest(requester *Requester, companyNumber string) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	if requester == nil || requester.UserID == "" {
+		return nil, InvalidData
+	}
+
+	companyNumber = NormaliseCompanyNumber(companyNumber)
+	createdSince := time.Now().Add(-s.pendingResumeWindow())
+
+	authCodeRequest, err := s.DAO.GetLatestPendingAuthCodeRequest(requester.UserID, companyNumber, createdSince)
+	if err != nil {
+		log.Error(fmt.Errorf("error getting pending authcode request: %v", err), log.Data{"company_number": companyNumber})
+		return nil, Error
+	}
+	if authCodeRequest == nil {
+		return nil, NotFound
+	}
+
+	return authCodeRequest, Success
+}
+
+// ResumePendingAuthCodeRequest returns the requester's pending authcode request for the company, so that it
+// can be reused instead of creating another. If an officer is supplied which differs from that of the
+// request, the request is updated with the officer, and any idempotency key supplied is recorded against the
+// request in the same write, so that a retry is replayed. The write is only applied if the request has not
+// been modified since it was read, and PreconditionFailed is returned if it has. NotFound is returned if
+// there is no request to resume, and Conflict if the requester has already used the idempotency key.
+func (s *AuthCodeRequestService) ResumePendingAuthCodeRequest(requester *Requester, companyNumber string, officer *oracle.Officer, idempotencyKey *models.IdempotencyKeyDao) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	authCodeRequest, responseType := s.GetPendingAuthCodeRequest(requester, companyNumber)
+	if responseType != Success {
+		return nil, responseType
+	}
+
+	logContext := log.Data{"auth_code_request_id": authCodeRequest.ID, "company_number": companyNumber}
+
+	officerChanged := officer != nil && officer.ID != authCodeRequest.Data.OfficerID
+	if officerChanged || idempotencyKey != nil {
+		requestDao := models.AuthCodeRequestResourceDao{
+			ID: authCodeRequest.ID,
+			Data: models.AuthCodeRequestDataDao{
+				OfficerID:       authCodeRequest.Data.OfficerID,
+				OfficerUraID:    authCodeRequest.Data.OfficerUraID,
+				OfficerForename: authCodeRequest.Data.OfficerForename,
+				OfficerSurname:  authCodeRequest.Data.OfficerSurname,
+			},
+		}
+		if officerChanged {
+			requestDao.Data.OfficerID = officer.ID
+			requestDao.Data.OfficerUraID = officer.UsualResidentialAddress.ID
+			requestDao.Data.OfficerForename = officer.Forename
+			requestDao.Data.OfficerSurname = officer.Surname
+		}
+
+		err := s.DAO.ResumeAuthCodeRequest(&requestDao, authCodeRequest.Data.Etag, idempotencyKey)
+		switch err {
+		case nil:
+		case dao.ErrEtagMismatch:
+			log.Info("pending authcode request modified since it was read so not resumed", logContext)
+			return nil, PreconditionFailed
+		case dao.ErrNotFound:
+			log.Info("pending authcode request deleted since it was read so not resumed", logContext)
+			return nil, NotFound
+		case dao.ErrDuplicateIdempotencyKey:
+			log.Info("idempotency key already used so pending authcode request not resumed", logContext)
+			return nil, Conflict
+		default:
+			log.Error(fmt.Errorf("error resuming pending authcode request: %v", err), logContext)
+			return nil, Error
+		}
+
+		before := auditState(authCodeRequest)
+
+		authCodeRequest.Data.Etag = requestDao.Data.Etag
+		authCodeRequest.Data.OfficerID = requestDao.Data.OfficerID
+		authCodeRequest.Data.OfficerUraID = requestDao.Data.OfficerUraID
+		authCodeRequest.Data.OfficerForename = requestDao.Data.OfficerForename
+		authCodeRequest.Data.OfficerSurname = requestDao.Data.OfficerSurname
+		if idempotencyKey != nil {
+			authCodeRequest.Data.IdempotencyKeys = append(authCodeRequest.Data.IdempotencyKeys, *idempotencyKey)
+		}
+
+		if officerChanged {
+			s.recordAudit(authCodeRequest.ID, requester, models.AuditActionOfficerUpdated, before, auditState(authCodeRequest))
+		}
+	}
+
+	log.Info("resuming pending authcode request", logContext)
+
+	return authCodeRequest, Success
+}
+
+// pendingResumeWindow returns how long after it was created a pending request may be resumed
+func (s *AuthCodeRequestService) pendingResumeWindow() time.Duration {
+	if s.Config != nil && s.Config.PendingResumeHours > 0 {
+		return time.Duration(s.Config.PendingResumeHours) * time.Hour
+	}
+	return defaultPendingResumeWindow
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/oracle"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetPendingAuthCodeRequest(t *testing.T) {
+	Convey("Get pending auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService, Config: &config.Config{}}
+		requester := &Requester{UserID: testUserID}
+
+		Convey("requester without user", func() {
+			_, responseType := svc.GetPendingAuthCodeRequest(&Requester{}, companyNumber)
+			So(responseType, ShouldEqual, InvalidData)
+		})
+
+		Convey("error getting request", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(nil, fmt.Errorf("error"))
+			_, responseType := svc.GetPendingAuthCodeRequest(requester, companyNumber)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("no pending request", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(nil, nil)
+			_, responseType := svc.GetPendingAuthCodeRequest(requester, companyNumber)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("company number supplied in lower case", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, "SC123456", gomock.Any()).Return(nil, nil)
+			_, responseType := svc.GetPendingAuthCodeRequest(requester, "sc123456")
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("default resume window", func() {
+			var createdSince time.Time
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).DoAndReturn(
+				func(userID, companyNumber string, since time.Time) (*models.AuthCodeRequestResourceDao, error) {
+					createdSince = since
+					return &models.AuthCodeRequestResourceDao{ID: authCodeRequestID}, nil
+				})
+
+			authCodeRequest, responseType := svc.GetPendingAuthCodeRequest(requester, companyNumber)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeRequest.ID, ShouldEqual, authCodeRequestID)
+			So(createdSince, ShouldHappenWithin, time.Minute, time.Now().Add(-24*time.Hour))
+		})
+
+		Convey("configured resume window", func() {
+			svc.Config.PendingResumeHours = 2
+			var createdSince time.Time
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).DoAndReturn(
+				func(userID, companyNumber string, since time.Time) (*models.AuthCodeRequestResourceDao, error) {
+					createdSince = since
+					return nil, nil
+				})
+
+			svc.GetPendingAuthCodeRequest(requester, companyNumber)
+			So(createdSince, ShouldHappenWithin, time.Minute, time.Now().Add(-2*time.Hour))
+		})
+	})
+}
+
+func TestUnitResumePendingAuthCodeRequest(t *testing.T) {
+	Convey("Resume pending auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+		requester := &Requester{UserID: testUserID}
+
+		pending := &models.AuthCodeRequestResourceDao{
+			ID:   authCodeRequestID,
+			Data: models.AuthCodeRequestDataDao{Status: models.StatusPending, OfficerID: "officer1", Etag: "etag1"},
+		}
+
+		Convey("no pending request", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(nil, nil)
+			_, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, nil, nil)
+			So(responseType, ShouldEqual, NotFound)
+		})
+
+		Convey("resumed without officer", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			authCodeRequest, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, nil, nil)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeRequest.Data.OfficerID, ShouldEqual, "officer1")
+		})
+
+		Convey("resumed with same officer", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			_, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, &oracle.Officer{ID: "officer1"}, nil)
+			So(responseType, ShouldEqual, Success)
+		})
+
+		Convey("resumed with different officer", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			mockDaoService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", nil).Return(nil)
+
+			authCodeRequest, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, &oracle.Officer{ID: "officer2", Forename: "New", Surname: "Officer"}, nil)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeRequest.Data.OfficerID, ShouldEqual, "officer2")
+			So(authCodeRequest.Data.OfficerForename, ShouldEqual, "New")
+		})
+
+		Convey("officer not updated", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			mockDaoService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", nil).Return(fmt.Errorf("error"))
+
+			authCodeRequest, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, &oracle.Officer{ID: "officer2"}, nil)
+			So(authCodeRequest, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("idempotency key recorded with same officer", func() {
+			idempotencyKey := &models.IdempotencyKeyDao{Key: "key1", Hash: "hash1", Resumed: true}
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			mockDaoService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", idempotencyKey).DoAndReturn(func(resumed *models.AuthCodeRequestResourceDao, _ string, _ *models.IdempotencyKeyDao) error {
+				So(resumed.Data.OfficerID, ShouldEqual, "officer1")
+				resumed.Data.Etag = "etag2"
+				return nil
+			})
+
+			authCodeRequest, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, &oracle.Officer{ID: "officer1"}, idempotencyKey)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeRequest.Data.Etag, ShouldEqual, "etag2")
+			So(authCodeRequest.Data.IdempotencyKeys, ShouldResemble, []models.IdempotencyKeyDao{*idempotencyKey})
+		})
+
+		Convey("request modified since it was read", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			mockDaoService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", gomock.Any()).Return(dao.ErrEtagMismatch)
+
+			_, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, nil, &models.IdempotencyKeyDao{Key: "key1"})
+			So(responseType, ShouldEqual, PreconditionFailed)
+		})
+
+		Convey("idempotency key already used", func() {
+			mockDaoService.EXPECT().GetLatestPendingAuthCodeRequest(testUserID, companyNumber, gomock.Any()).Return(pending, nil)
+			mockDaoService.EXPECT().ResumeAuthCodeRequest(gomock.Any(), "etag1", gomock.Any()).Return(dao.ErrDuplicateIdempotencyKey)
+
+			_, responseType := svc.ResumePendingAuthCodeRequest(requester, companyNumber, nil, &models.IdempotencyKeyDao{Key: "key1"})
+			So(responseType, ShouldEqual, Conflict)
+		})
+	})
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// Requester identifies the caller on whose behalf an auth code request is being accessed
+type Requester struct {
+	UserID   string
+	Email    string
+	Elevated bool
+	// APIKey is set when the caller has been authenticated using an API key, rather than as a user, in
+	// which case APIKeyID identifies the key
+	APIKey   bool
+	APIKeyID string
+	// RequestID is the X-Request-Id of the request made by the caller, recorded in the audit trail
+	RequestID string
+}
+
+// CanAccess returns whether the requester is permitted to access the supplied auth code request.
+// Requests may only be accessed by the user who created them, unless the caller has been
+// authenticated using an API key with elevated privileges.
+func (r *Requester) CanAccess(authCodeRequest *models.AuthCodeRequestResourceDao) bool {
+	if r == nil {
+		return false
+	}
+	if r.Elevated {
+		return true
+	}
+	return r.UserID != "" && authCodeRequest.Data.CreatedBy.ID == r.UserID
+}
+
+// actor returns the requester as the actor recorded against changes they make to auth code requests. A
+// caller authenticated using an API key is identified by the key, rather than by the user it acts for.
+func (r *Requester) actor() models.ActorDao {
+	if r == nil {
+		return models.ActorDao{}
+	}
+	if r.APIKey {
+		return models.ActorDao{
+			ID:    r.APIKeyID,
+			Email: r.Email,
+		}
+	}
+	return models.ActorDao{
+		ID:    r.UserID,
+		Email: r.Email,
+	}
+}
+
+// actorType returns the kind of caller the requester is, as recorded in the audit trail
+func (r *Requester) actorType() models.ActorType {
+	if r == nil {
+		return models.ActorTypeSystem
+	}
+	if r.APIKey {
+		return models.ActorTypeAPIKey
+	}
+	return models.ActorTypeUser
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRequesterCanAccess(t *testing.T) {
+	Convey("Requester can access auth code request", t, func() {
+		authCodeReq := &models.AuthCodeRequestResourceDao{
+			Data: models.AuthCodeRequestDataDao{
+				CreatedBy: models.CreatedByDao{ID: testUserID},
+			},
+		}
+
+		Convey("request created by requester", func() {
+			So((&Requester{UserID: testUserID}).CanAccess(authCodeReq), ShouldBeTrue)
+		})
+
+		Convey("request created by another user", func() {
+			So((&Requester{UserID: "otherUser"}).CanAccess(authCodeReq), ShouldBeFalse)
+		})
+
+		Convey("requester has no user ID", func() {
+			So((&Requester{}).CanAccess(&models.AuthCodeRequestResourceDao{}), ShouldBeFalse)
+		})
+
+		Convey("requester has elevated privileges", func() {
+			So((&Requester{UserID: "otherUser", Elevated: true}).CanAccess(authCodeReq), ShouldBeTrue)
+		})
+
+		Convey("no requester", func() {
+			var requester *Requester
+			So(requester.CanAccess(authCodeReq), ShouldBeFalse)
+		})
+	})
+}
+
+func TestUnitRequesterActor(t *testing.T) {
+	Convey("Requester recorded as actor", t, func() {
+		Convey("user", func() {
+			requester := &Requester{UserID: testUserID, Email: "test@test.com"}
+			So(requester.actor(), ShouldResemble, models.ActorDao{ID: testUserID, Email: "test@test.com"})
+			So(requester.actorType(), ShouldEqual, models.ActorTypeUser)
+		})
+
+		Convey("API key is identified by the key rather than the user", func() {
+			requester := &Requester{UserID: testUserID, Email: "test@test.com", APIKey: true, APIKeyID: "key123"}
+			So(requester.actor(), ShouldResemble, models.ActorDao{ID: "key123", Email: "test@test.com"})
+			So(requester.actorType(), ShouldEqual, models.ActorTypeAPIKey)
+		})
+
+		Convey("no requester", func() {
+			var requester *Requester
+			So(requester.actor(), ShouldResemble, models.ActorDao{})
+			So(requester.actorType(), ShouldEqual, models.ActorTypeSystem)
+		})
+	})
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// GetSubmittedAuthCodeRequest returns an authcode request for which a letter has been requested.
+// Conflict is returned if the request has not been submitted.
+func (s *AuthCodeRequestService) GetSubmittedAuthCodeRequest(authCodeRequestID string) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	return s.getAuthCodeRequestInStatus(authCodeRequestID, models.RequestStatus.IsSubmitted)
+}
+
+// GetResendableAuthCodeRequest returns an authcode request whose letter has been dispatched, so may be
+// sent again. Conflict is returned if it has not, including while the letter is waiting in the outbox.
+func (s *AuthCodeRequestService) GetResendableAuthCodeRequest(authCodeRequestID string) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	return s.getAuthCodeRequestInStatus(authCodeRequestID, models.RequestStatus.IsResendable)
+}
+
+// ResendAuthCodeRequest sends the letter for a dispatched authcode request again, on behalf of the
+// supplied operator. Conflict is returned if the letter has not been dispatched. The eligibility checks are not applied, and the request is not held for review.
+// The submission limits are applied unless the operator has asked to override them, and Forbidden is
+// returned along with the reason if one has been reached. The resend is recorded in the history of the
+// request, along with the requester and whether the limits were overridden.
+func (s *AuthCodeRequestService) ResendAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, resendRequest *models.ResendRequest, requester *Requester, companyHasAuthCode bool) (*models.EligibilityFailureResponse, ResponseType) {
+	if !authCodeReqDao.Data.Status.IsResendable() {
+		log.Info("authcode request letter has not been dispatched so cannot be resent", log.Data{"auth_code_request_id": authCodeReqDao.ID, "status": authCodeReqDao.Data.Status})
+		return nil, Conflict
+	}
+
+	resentAt := time.Now().Truncate(time.Millisecond)
+
+	resend := models.ResendDao{
+		ReasonCode:     models.ResendReason(resendRequest.ReasonCode),
+		Operator:       resendRequest.Operator,
+		Note:           resendRequest.Note,
+		OverrideLimits: resendRequest.OverrideLimits,
+		RequestedBy:    requester.actor(),
+		ResentAt:       &resentAt,
+	}
+
+	return s.sendAuthCodeRequest(authCodeReqDao, authCodeReqDao.Data.CompanyNumber, authCodeReqDao.Data.CreatedBy.Email, authCodeReqDao.ID, companyHasAuthCode, requester,
+		sendOptions{resend: &resend, overrideLimits: resendRequest.OverrideLimits})
+}
+
+// recordResend records the letter for a dispatched authcode request for dispatch again, along with the
+// resend in the history of the request. A request whose letter was returned moves back to submitted.
+// Conflict is returned if the request is no longer in the status it was read in.
+func (s *AuthCodeRequestService) recordResend(authCodeRequestID string, fromStatus models.RequestStatus, resend models.ResendDao, letterItem models.OutboxItemDao, requester *Requester) ResponseType {
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID, "reason_code": resend.ReasonCode, "operator": resend.Operator}
+
+	err := s.DAO.ResendAuthCodeRequest(authCodeRequestID, fromStatus, resend, []models.OutboxItemDao{letterItem})
+	if err == dao.ErrStatusChanged {
+		log.Info("authcode request letter is no longer dispatched so not resent", logContext)
+		return Conflict
+	}
+	if err != nil {
+		log.Error(fmt.Errorf("error recording authcode request resend: %v", err), logContext)
+		return Error
+	}
+
+	log.Info("authcode request letter queued for resend", logContext)
+
+	if fromStatus == models.StatusReturned {
+		s.recordAudit(authCodeRequestID, requester, models.AuditActionResent, statusState(models.StatusReturned), statusState(models.StatusSubmitted))
+	} else {
+		s.recordAudit(authCodeRequestID, requester, models.AuditActionResent, nil, nil)
+	}
+
+	return Success
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitGetSubmittedAuthCodeRequest(t *testing.T) {
+	Convey("get submitted auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+
+		Convey("request not submitted", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusPending}}, nil)
+
+			_, responseType := svc.GetSubmittedAuthCodeRequest(authCodeRequestID)
+			So(responseType, ShouldEqual, Conflict)
+		})
+
+		Convey("request dispatched", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusDispatched}}, nil)
+
+			authCodeReq, responseType := svc.GetSubmittedAuthCodeRequest(authCodeRequestID)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeReq.Data.Status, ShouldEqual, models.StatusDispatched)
+		})
+	})
+}
+
+func TestUnitGetResendableAuthCodeRequest(t *testing.T) {
+	Convey("get resendable auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		svc := AuthCodeRequestService{DAO: mockDaoService}
+
+		Convey("letter waiting in the outbox", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitted}}, nil)
+
+			_, responseType := svc.GetResendableAuthCodeRequest(authCodeRequestID)
+			So(responseType, ShouldEqual, Conflict)
+		})
+
+		Convey("letter posted", func() {
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusPosted}}, nil)
+
+			authCodeReq, responseType := svc.GetResendableAuthCodeRequest(authCodeRequestID)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeReq.Data.Status, ShouldEqual, models.StatusPosted)
+		})
+	})
+}
+
+func TestUnitResendAuthCodeRequest(t *testing.T) {
+	Convey("resend auth code request", t, func() {
+		cfg, _ := config.Get()
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+		responder := httpmock.NewStringResponder(http.StatusOK, `{"forename":"joe","surname":"bloggs"}`)
+		httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+		requester := &Requester{UserID: testReviewerID, Elevated: true}
+		resendRequest := &models.ResendRequest{ReasonCode: "lost-in-post", Operator: "support.agent", Note: "customer called"}
+		authCodeReq := models.AuthCodeRequestResourceDao{
+			ID: authCodeRequestID,
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: companyNumber,
+				OfficerID:     "987",
+				Status:        models.StatusDispatched,
+				CreatedBy:     models.CreatedByDao{ID: testUserID, Email: "email@companieshouse.gov.uk"},
+			},
+		}
+
+		Convey("letter not yet dispatched", func() {
+			authCodeReq.Data.Status = models.StatusSubmitted
+			svc := AuthCodeRequestService{DAO: mocks.NewMockAuthcodeRequestDAOService(mockCtrl), Config: cfg}
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Conflict)
+		})
+
+		Convey("request no longer submitted", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ResendAuthCodeRequest(authCodeRequestID, models.StatusDispatched, gomock.Any(), gomock.Any()).Return(dao.ErrStatusChanged)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Conflict)
+		})
+
+		Convey("error recording resend", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ResendAuthCodeRequest(authCodeRequestID, models.StatusDispatched, gomock.Any(), gomock.Any()).Return(fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("submission limit reached", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1}, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			limitFailure, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Forbidden)
+			So(limitFailure.Code, ShouldEqual, models.ReasonCompanyRecentlyRequested)
+		})
+
+		Convey("submission limits overridden by operator", func() {
+			var resend models.ResendDao
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ResendAuthCodeRequest(authCodeRequestID, models.StatusDispatched, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ models.RequestStatus, r models.ResendDao, _ []models.OutboxItemDao) error {
+					resend = r
+					return nil
+				})
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			overrideRequest := *resendRequest
+			overrideRequest.OverrideLimits = true
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, &overrideRequest, requester, true)
+			So(responseType, ShouldEqual, Success)
+			So(resend.OverrideLimits, ShouldBeTrue)
+		})
+
+		Convey("resend request - success", func() {
+			var resend models.ResendDao
+			var outboxItems []models.OutboxItemDao
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ResendAuthCodeRequest(authCodeRequestID, models.StatusDispatched, gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ string, _ models.RequestStatus, r models.ResendDao, items []models.OutboxItemDao) error {
+					resend = r
+					outboxItems = items
+					return nil
+				})
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Success)
+
+			// only the letter is sent again, without applying the hold rules
+			So(resend.ReasonCode, ShouldEqual, models.ResendReasonLostInPost)
+			So(resend.Operator, ShouldEqual, "support.agent")
+			So(resend.Note, ShouldEqual, "customer called")
+			So(resend.RequestedBy.ID, ShouldEqual, testReviewerID)
+			So(resend.ResentAt, ShouldNotBeNil)
+			So(outboxItems, ShouldHaveLength, 1)
+			So(outboxItems[0].Type, ShouldEqual, models.OutboxTypeLetter)
+			So(outboxItems[0].AuthCodeItem.CompanyName, ShouldEqual, "joe bloggs")
+			So(outboxItems[0].AuthCodeItem.Email, ShouldEqual, "email@companieshouse.gov.uk")
+		})
+
+		Convey("returned letter resent moves request back to submitted", func() {
+			authCodeReq.Data.Status = models.StatusReturned
+
+			var auditEntry *models.AuditEntryDao
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ResendAuthCodeRequest(authCodeRequestID, models.StatusReturned, gomock.Any(), gomock.Any()).Return(nil)
+			mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+			mockAuditService.EXPECT().InsertAuditEntry(gomock.Any()).DoAndReturn(func(entry *models.AuditEntryDao) error {
+				auditEntry = entry
+				return nil
+			})
+			svc := AuthCodeRequestService{DAO: mockDaoService, AuditDAO: mockAuditService, Config: cfg}
+
+			_, responseType := svc.ResendAuthCodeRequest(&authCodeReq, resendRequest, requester, true)
+			So(responseType, ShouldEqual, Success)
+			So(auditEntry.Action, ShouldEqual, models.AuditActionResent)
+			So(auditEntry.Before.Status, ShouldEqual, models.StatusReturned)
+			So(auditEntry.After.Status, ShouldEqual, models.StatusSubmitted)
+		})
+	})
+}
@@ -18,6 +18,15 @@ const (
 
 	// Success response
 	Success
+
+	// Conflict response
+	Conflict
+
+	// PreconditionFailed response
+	PreconditionFailed
+
+	// Unprocessable response
+	Unprocessable
 )
 
 var vals = [...]string{
@@ -26,6 +35,9 @@ var vals = [...]string{
 	"forbidden",
 	"not-found",
 	"success",
+	"conflict",
+	"precondition-failed",
+	"unprocessable",
 }
 
 // String representation of `ResponseType`
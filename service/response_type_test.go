@@ -9,5 +9,7 @@ import (
 func TestUnitResponseType(t *testing.T) {
 	Convey("Successful Get Response Type", t, func() {
 		So(NotFound.String(), ShouldEqual, "not-found")
+		So(PreconditionFailed.String(), ShouldEqual, "precondition-failed")
+		So(Unprocessable.String(), ShouldEqual, "unprocessable")
 	})
 }
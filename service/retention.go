@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/dao"
+)
+
+const (
+	defaultRetentionInterval = 24 * time.Hour
+
+	// retentionBatchSize is the number of requests anonymised or deleted in each batch
+	retentionBatchSize = 100
+)
+
+// RetentionJob applies the data retention schedule to authcode requests which are no longer open. The
+// personal data of such requests is removed once they reach the configured anonymisation age, and the
+// requests are deleted, along with their audit trails, once they reach the configured deletion age. Ages
+// are measured from when the request was submitted, as that is when it starts to count towards the
+// submission limits, or from when it was created if it was never submitted. Either stage is skipped if its
+// age is not set. In
+// dry run mode the requests which would be anonymised and deleted are counted, but not changed.
+type RetentionJob struct {
+	DAO      dao.AuthcodeRequestDAOService
+	AuditDAO dao.AuthcodeAuditDAOService
+	Config   *config.Config
+}
+
+// RetentionResult is the number of requests anonymised and deleted by a run of the retention job, or that
+// would have been in dry run mode
+type RetentionResult struct {
+	Anonymised int64
+	Deleted    int64
+	DryRun     bool
+}
+
+// Enabled returns whether the config sets an age at which requests are anonymised or deleted
+func (j *RetentionJob) Enabled() bool {
+	return j.anonymiseAfter() > 0 || j.deleteAfter() > 0
+}
+
+// CheckConfig returns an error if the retention ages in the config are inconsistent, or if requests would
+// be anonymised or deleted while they still count towards the submission limits
+func (j *RetentionJob) CheckConfig() error {
+	if j.anonymiseAfter() > 0 && j.deleteAfter() > 0 && j.deleteAfter() <= j.anonymiseAfter() {
+		return fmt.Errorf("retention delete days [%d] must be greater than retention anonymise days [%d]", j.Config.RetentionDeleteDays, j.Config.RetentionAnonymiseDays)
+	}
+
+	longestWindow := NewSubmissionPolicy(j.Config).LongestWindow()
+	if j.anonymiseAfter() > 0 && j.anonymiseAfter() < longestWindow {
+		return fmt.Errorf("retention anonymise days [%d] must not be shorter than the longest submission limit window of [%s]", j.Config.RetentionAnonymiseDays, longestWindow)
+	}
+	if j.deleteAfter() > 0 && j.deleteAfter() < longestWindow {
+		return fmt.Errorf("retention delete days [%d] must not be shorter than the longest submission limit window of [%s]", j.Config.RetentionDeleteDays, longestWindow)
+	}
+
+	return nil
+}
+
+// Start runs the job at the configured interval until the supplied context is cancelled. Nothing is run if
+// the job is not enabled.
+func (j *RetentionJob) Start(ctx context.Context) {
+	if !j.Enabled() {
+		log.Info("retention job not enabled")
+		return
+	}
+
+	interval := defaultRetentionInterval
+	if j.Config.RetentionIntervalHours > 0 {
+		interval = time.Duration(j.Config.RetentionIntervalHours) * time.Hour
+	}
+
+	log.Info("starting retention job", log.Data{
+		"interval":        interval.String(),
+		"anonymise_after": j.anonymiseAfter().String(),
+		"delete_after":    j.deleteAfter().String(),
+		"dry_run":         j.Config.RetentionDryRun,
+	})
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Info("retention job stopped")
+			return
+		case <-ticker.C:
+			j.Run()
+		}
+	}
+}
+
+// Run applies the retention schedule once, logging and returning the number of requests deleted and
+// anonymised. Requests are deleted before anonymising, so that requests due for deletion are not first
+// anonymised. If an error occurs the counts of the requests changed before it are returned along with it.
+func (j *RetentionJob) Run() (RetentionResult, error) {
+	result := RetentionResult{DryRun: j.Config.RetentionDryRun}
+	now := time.Now()
+
+	err := j.deleteRequests(now, &result)
+	if err == nil {
+		err = j.anonymiseRequests(now, &result)
+	}
+
+	logContext := log.Data{"anonymised": result.Anonymised, "deleted": result.Deleted, "dry_run": result.DryRun}
+	if err != nil {
+		log.Error(fmt.Errorf("error applying retention schedule: %v", err), logContext)
+		return result, err
+	}
+
+	if result.DryRun {
+		log.Info("retention job dry run completed; no requests changed", logContext)
+	} else {
+		log.Info("retention job completed", logContext)
+	}
+
+	return result, nil
+}
+
+// deleteRequests deletes the closed requests which have reached the deletion age, along with their audit
+// trails, adding the number deleted to the result
+func (j *RetentionJob) deleteRequests(now time.Time, result *RetentionResult) error {
+	if j.deleteAfter() <= 0 {
+		return nil
+	}
+	before := now.Add(-j.deleteAfter())
+
+	if result.DryRun {
+		count, err := j.DAO.CountClosedAuthCodeRequests(before, false)
+		result.Deleted = count
+		return err
+	}
+
+	for {
+		ids, err := j.DAO.DeleteClosedAuthCodeRequests(before, retentionBatchSize)
+		if err != nil {
+			return fmt.Errorf("error deleting authcode requests: %v", err)
+		}
+		result.Deleted += int64(len(ids))
+
+		if len(ids) > 0 && j.AuditDAO != nil {
+			if _, err := j.AuditDAO.DeleteAuditEntries(ids); err != nil {
+				return fmt.Errorf("error deleting audit entries: %v", err)
+			}
+		}
+
+		if len(ids) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// anonymiseRequests removes the personal data from the closed requests which have reached the
+// anonymisation age, and from their audit trails, adding the number anonymised to the result
+func (j *RetentionJob) anonymiseRequests(now time.Time, result *RetentionResult) error {
+	if j.anonymiseAfter() <= 0 {
+		return nil
+	}
+	before := now.Add(-j.anonymiseAfter())
+
+	if result.DryRun {
+		count, err := j.DAO.CountClosedAuthCodeRequests(before, true)
+		result.Anonymised = count
+		return err
+	}
+
+	for {
+		ids, err := j.DAO.AnonymiseClosedAuthCodeRequests(before, retentionBatchSize)
+		if err != nil {
+			return fmt.Errorf("error anonymising authcode requests: %v", err)
+		}
+		result.Anonymised += int64(len(ids))
+
+		if len(ids) > 0 && j.AuditDAO != nil {
+			if _, err := j.AuditDAO.AnonymiseAuditEntries(ids); err != nil {
+				return fmt.Errorf("error anonymising audit entries: %v", err)
+			}
+		}
+
+		if len(ids) < retentionBatchSize {
+			return nil
+		}
+	}
+}
+
+// anonymiseAfter returns how long after it was created a closed request is anonymised, or zero if it is not
+func (j *RetentionJob) anonymiseAfter() time.Duration {
+	return time.Duration(j.Config.RetentionAnonymiseDays) * 24 * time.Hour
+}
+
+// deleteAfter returns how long after it was created a closed request is deleted, or zero if it is not
+func (j *RetentionJob) deleteAfter() time.Duration {
+	return time.Duration(j.Config.RetentionDeleteDays) * 24 * time.Hour
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitRetentionJobConfig(t *testing.T) {
+	Convey("retention job config", t, func() {
+		job := RetentionJob{Config: &config.Config{}}
+
+		Convey("not enabled without ages", func() {
+			So(job.Enabled(), ShouldBeFalse)
+			So(job.CheckConfig(), ShouldBeNil)
+		})
+
+		Convey("enabled with anonymise age only", func() {
+			job.Config.RetentionAnonymiseDays = 365
+			So(job.Enabled(), ShouldBeTrue)
+			So(job.CheckConfig(), ShouldBeNil)
+		})
+
+		Convey("enabled with delete age only", func() {
+			job.Config.RetentionDeleteDays = 730
+			So(job.Enabled(), ShouldBeTrue)
+			So(job.CheckConfig(), ShouldBeNil)
+		})
+
+		Convey("delete age must be greater than anonymise age", func() {
+			job.Config.RetentionAnonymiseDays = 365
+			job.Config.RetentionDeleteDays = 365
+			So(job.CheckConfig().Error(), ShouldEqual, "retention delete days [365] must be greater than retention anonymise days [365]")
+		})
+
+		Convey("anonymise age must cover longest submission limit window", func() {
+			job.Config.RetentionAnonymiseDays = 7
+			So(job.CheckConfig(), ShouldBeNil)
+
+			job.Config.OfficerSubmissionWindowHours = 30 * 24
+			So(job.CheckConfig().Error(), ShouldEqual, "retention anonymise days [7] must not be shorter than the longest submission limit window of [720h0m0s]")
+		})
+
+		Convey("delete age must cover longest submission limit window", func() {
+			job.Config.RetentionDeleteDays = 3
+			So(job.CheckConfig().Error(), ShouldEqual, "retention delete days [3] must not be shorter than the longest submission limit window of [168h0m0s]")
+		})
+	})
+}
+
+func TestUnitRetentionJobRun(t *testing.T) {
+	Convey("run retention job", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		mockRequestService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+		mockAuditService := mocks.NewMockAuthcodeAuditDAOService(mockCtrl)
+		cfg := &config.Config{RetentionAnonymiseDays: 365, RetentionDeleteDays: 730}
+		job := RetentionJob{DAO: mockRequestService, AuditDAO: mockAuditService, Config: cfg}
+
+		Convey("dry run counts without changing requests", func() {
+			cfg.RetentionDryRun = true
+
+			var deleteBefore, anonymiseBefore time.Time
+			mockRequestService.EXPECT().CountClosedAuthCodeRequests(gomock.Any(), false).DoAndReturn(func(before time.Time, excludeAnonymised bool) (int64, error) {
+				deleteBefore = before
+				return 3, nil
+			})
+			mockRequestService.EXPECT().CountClosedAuthCodeRequests(gomock.Any(), true).DoAndReturn(func(before time.Time, excludeAnonymised bool) (int64, error) {
+				anonymiseBefore = before
+				return 5, nil
+			})
+
+			result, err := job.Run()
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, RetentionResult{Anonymised: 5, Deleted: 3, DryRun: true})
+			So(deleteBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-730*24*time.Hour))
+			So(anonymiseBefore, ShouldHappenWithin, time.Minute, time.Now().Add(-365*24*time.Hour))
+		})
+
+		Convey("requests deleted then anonymised along with audit trails", func() {
+			gomock.InOrder(
+				mockRequestService.EXPECT().DeleteClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return([]string{"old1"}, nil),
+				mockAuditService.EXPECT().DeleteAuditEntries([]string{"old1"}).Return(int64(4), nil),
+				mockRequestService.EXPECT().AnonymiseClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return([]string{"request1", "request2"}, nil),
+				mockAuditService.EXPECT().AnonymiseAuditEntries([]string{"request1", "request2"}).Return(int64(6), nil),
+			)
+
+			result, err := job.Run()
+			So(err, ShouldBeNil)
+			So(result, ShouldResemble, RetentionResult{Anonymised: 2, Deleted: 1})
+		})
+
+		Convey("full batches repeated until fewer returned", func() {
+			cfg.RetentionDeleteDays = 0
+			batch := make([]string, retentionBatchSize)
+			for i := range batch {
+				batch[i] = fmt.Sprintf("request%d", i)
+			}
+			gomock.InOrder(
+				mockRequestService.EXPECT().AnonymiseClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return(batch, nil),
+				mockRequestService.EXPECT().AnonymiseClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return(nil, nil),
+			)
+			mockAuditService.EXPECT().AnonymiseAuditEntries(batch).Return(int64(0), nil)
+
+			result, err := job.Run()
+			So(err, ShouldBeNil)
+			So(result.Anonymised, ShouldEqual, retentionBatchSize)
+		})
+
+		Convey("error deleting requests stops job", func() {
+			mockRequestService.EXPECT().DeleteClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return(nil, fmt.Errorf("error"))
+
+			result, err := job.Run()
+			So(err.Error(), ShouldEqual, "error deleting authcode requests: error")
+			So(result, ShouldResemble, RetentionResult{})
+		})
+
+		Convey("error anonymising audit entries returns count so far", func() {
+			mockRequestService.EXPECT().DeleteClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return(nil, nil)
+			mockRequestService.EXPECT().AnonymiseClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return([]string{"request1"}, nil)
+			mockAuditService.EXPECT().AnonymiseAuditEntries([]string{"request1"}).Return(int64(0), fmt.Errorf("error"))
+
+			result, err := job.Run()
+			So(err.Error(), ShouldEqual, "error anonymising audit entries: error")
+			So(result.Anonymised, ShouldEqual, 1)
+		})
+
+		Convey("audit trails not changed without audit DAO", func() {
+			job.AuditDAO = nil
+			mockRequestService.EXPECT().DeleteClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return([]string{"old1"}, nil)
+			mockRequestService.EXPECT().AnonymiseClosedAuthCodeRequests(gomock.Any(), retentionBatchSize).Return(nil, nil)
+
+			result, err := job.Run()
+			So(err, ShouldBeNil)
+			So(result.Deleted, ShouldEqual, 1)
+		})
+	})
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/oracle"
+	"github.com/companieshouse/emergency-auth-code-api/transformers"
+)
+
+// getHoldReasons returns the reasons, if any, why the submission policy requires a submission for the
+// supplied officer by the supplied user to be held for manual review
+func (s *AuthCodeRequestService) getHoldReasons(companyOfficer *oracle.Officer, userID, userEmail string) ([]models.HoldReason, error) {
+	policy := s.submissionPolicy()
+
+	var reasons []models.HoldReason
+
+	if policy.IsOfficerRecentlyAppointed(companyOfficer.AppointedOn) {
+		reasons = append(reasons, models.HoldReasonOfficerRecentlyAppointed)
+	}
+
+	if !policy.IsUserExempt(userEmail) {
+		companies, err := s.DAO.CountUserCompanies(userID, time.Now().Add(-policy.UserCompanies.Window))
+		if err != nil {
+			log.Error(fmt.Errorf("error counting companies requested by user: %v", err))
+			return nil, err
+		}
+		if companies > policy.UserCompanies.MaxSubmissions {
+			reasons = append(reasons, models.HoldReasonUserMultipleCompanies)
+		}
+	}
+
+	return reasons, nil
+}
+
+// holdAuthCodeRequest moves a submitting authcode request to held for manual review
+func (s *AuthCodeRequestService) holdAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, authCodeRequestID string, reasons []models.HoldReason, requester *Requester) ResponseType {
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID, "hold_reasons": reasons}
+
+	transitioned, err := s.DAO.HoldAuthCodeRequest(authCodeRequestID, reasons)
+	if err != nil {
+		log.Error(fmt.Errorf("error holding authcode request for review: %v", err), logContext)
+		return Error
+	}
+
+	if !transitioned {
+		log.Info("authcode request is not submitting so cannot be held for review", logContext)
+		return Conflict
+	}
+
+	log.Info("authcode request held for review", logContext)
+
+	s.recordAudit(authCodeRequestID, requester, models.AuditActionHeld, statusState(models.StatusSubmitting), statusState(models.StatusHeld))
+
+	authCodeReqDao.Data.Status = models.StatusHeld
+	authCodeReqDao.Data.HoldReasons = reasons
+
+	return Success
+}
+
+// ListHeldAuthCodeRequests returns a page of the authcode requests which are held for manual review,
+// newest first
+func (s *AuthCodeRequestService) ListHeldAuthCodeRequests(startIndex, itemsPerPage int) (*models.AdminAuthCodeRequestListResponse, ResponseType) {
+	filter := models.AuthCodeRequestFilter{Status: models.StatusHeld}
+
+	authCodeRequests, totalResults, err := s.DAO.ListAuthCodeRequests(filter, startIndex, itemsPerPage)
+	if err != nil {
+		log.Error(fmt.Errorf("error listing held authcode requests: %v", err))
+		return nil, Error
+	}
+
+	return transformers.AuthCodeRequestResourceDaoListToAdminResponse(authCodeRequests, startIndex, itemsPerPage, totalResults), Success
+}
+
+// GetHeldAuthCodeRequest returns an authcode request which is held for manual review. Conflict is
+// returned if the request is not held.
+func (s *AuthCodeRequestService) GetHeldAuthCodeRequest(authCodeRequestID string) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	return s.getAuthCodeRequestInStatus(authCodeRequestID, func(status models.RequestStatus) bool {
+		return status == models.StatusHeld
+	})
+}
+
+// getAuthCodeRequestInStatus returns an authcode request, along with Conflict if its status is not one
+// accepted by the supplied function
+func (s *AuthCodeRequestService) getAuthCodeRequestInStatus(authCodeRequestID string, accepted func(models.RequestStatus) bool) (*models.AuthCodeRequestResourceDao, ResponseType) {
+	authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestID)
+	if err != nil {
+		log.Error(fmt.Errorf("error getting authcode request: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+		return nil, Error
+	}
+	if authCodeRequest == nil {
+		return nil, NotFound
+	}
+
+	if !accepted(authCodeRequest.Data.Status) {
+		return authCodeRequest, Conflict
+	}
+
+	return authCodeRequest, Success
+}
+
+// ApproveAuthCodeRequest records the approval of a held authcode request by the reviewer and submits it,
+// without applying the hold rules again. The letter is requested for the user who created the request,
+// subject to the submission limits, and Forbidden is returned along with the reason if one has been
+// reached. If the request cannot be submitted it is held again, so that the approval can be retried.
+func (s *AuthCodeRequestService) ApproveAuthCodeRequest(authCodeReqDao *models.AuthCodeRequestResourceDao, reviewer *Requester, reason string, companyHasAuthCode bool) (*models.EligibilityFailureResponse, ResponseType) {
+	authCodeRequestID := authCodeReqDao.ID
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID, "reviewed_by": reviewer.actor().ID}
+
+	transitioned, err := s.DAO.ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, newReview(models.ReviewApproved, reason, reviewer))
+	if err != nil {
+		log.Error(fmt.Errorf("error approving authcode request: %v", err), logContext)
+		return nil, Error
+	}
+
+	// the request has been reviewed since it was read
+	if !transitioned {
+		log.Info("authcode request is not held so cannot be approved", logContext)
+		return nil, Conflict
+	}
+
+	s.recordAudit(authCodeRequestID, reviewer, models.AuditActionApproved, statusState(models.StatusHeld), statusState(models.StatusSubmitting))
+
+	limitFailure, responseType := s.sendAuthCodeRequest(authCodeReqDao, authCodeReqDao.Data.CompanyNumber, authCodeReqDao.Data.CreatedBy.Email, authCodeRequestID, companyHasAuthCode, reviewer, sendOptions{})
+	if responseType != Success {
+		// Nothing has been recorded for dispatch, so return the request to the review queue
+		if _, err := s.DAO.HoldAuthCodeRequest(authCodeRequestID, authCodeReqDao.Data.HoldReasons); err != nil {
+			log.Error(fmt.Errorf("error returning approved authcode request to review: %v", err), logContext)
+		} else {
+			s.recordAudit(authCodeRequestID, reviewer, models.AuditActionHeld, statusState(models.StatusSubmitting), statusState(models.StatusHeld))
+		}
+		return limitFailure, responseType
+	}
+
+	log.Info("held authcode request approved", logContext)
+
+	return nil, Success
+}
+
+// RejectAuthCodeRequest records the rejection of a held authcode request by the reviewer, so that its
+// letter is never sent. NotFound is returned if there is no such request, and Conflict if it is not held.
+func (s *AuthCodeRequestService) RejectAuthCodeRequest(authCodeRequestID string, reviewer *Requester, reason string) ResponseType {
+	logContext := log.Data{"auth_code_request_id": authCodeRequestID, "reviewed_by": reviewer.actor().ID}
+
+	_, responseType := s.GetHeldAuthCodeRequest(authCodeRequestID)
+	if responseType != Success {
+		return responseType
+	}
+
+	transitioned, err := s.DAO.ReviewAuthCodeRequest(authCodeRequestID, models.StatusRejected, newReview(models.ReviewRejected, reason, reviewer))
+	if err != nil {
+		log.Error(fmt.Errorf("error rejecting authcode request: %v", err), logContext)
+		return Error
+	}
+
+	// the request has been reviewed since it was read
+	if !transitioned {
+		log.Info("authcode request is not held so cannot be rejected", logContext)
+		return Conflict
+	}
+
+	log.Info("held authcode request rejected", logContext)
+
+	s.recordAudit(authCodeRequestID, reviewer, models.AuditActionRejected, statusState(models.StatusHeld), statusState(models.StatusRejected))
+
+	return Success
+}
+
+// newReview returns the record of a decision made now by the reviewer on a held authcode request
+func newReview(decision models.ReviewDecision, reason string, reviewer *Requester) models.ReviewDao {
+	reviewedAt := time.Now().Truncate(time.Millisecond)
+
+	return models.ReviewDao{
+		Decision:   decision,
+		Reason:     reason,
+		ReviewedBy: reviewer.actor(),
+		ReviewedAt: &reviewedAt,
+	}
+}
+
+// GetAdminAuthCodeRequest returns an authcode request as seen by an administrator, including the reasons
+// it was held for review and the decisions made on it
+func (s *AuthCodeRequestService) GetAdminAuthCodeRequest(authCodeRequestID string) (*models.AdminAuthCodeRequestResponse, ResponseType) {
+	authCodeRequest, err := s.DAO.GetAuthCodeRequest(authCodeRequestID)
+	if err != nil {
+		log.Error(fmt.Errorf("error getting authcode request: %v", err), log.Data{"auth_code_request_id": authCodeRequestID})
+		return nil, Error
+	}
+	if authCodeRequest == nil {
+		return nil, NotFound
+	}
+
+	return transformers.AuthCodeRequestResourceDaoToAdminResponse(authCodeRequest), Success
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	"github.com/jarcoal/httpmock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const testReviewerID = "reviewer-key"
+
+func TestUnitSendAuthCodeRequestHeld(t *testing.T) {
+	Convey("send auth code request held for review", t, func() {
+		cfg, _ := config.Get()
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		authCodeReq := models.AuthCodeRequestResourceDao{
+			Data: models.AuthCodeRequestDataDao{
+				OfficerID: "987",
+				Status:    models.StatusSubmitting,
+				CreatedBy: models.CreatedByDao{ID: testUserID, Email: "email@companieshouse.gov.uk"},
+			},
+		}
+
+		Convey("officer recently appointed", func() {
+			appointedOn := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs","appointed_on":"`+appointedOn+`"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(testUserID, gomock.Any()).Return(1, nil)
+			mockDaoService.EXPECT().HoldAuthCodeRequest(testRequestID, []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed}).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeReq.Data.Status, ShouldEqual, models.StatusHeld)
+			So(authCodeReq.Data.HoldReasons, ShouldResemble, []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed})
+		})
+
+		Convey("user has requested for several companies", func() {
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs","appointed_on":"01-01-2001"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(testUserID, gomock.Any()).Return(4, nil)
+			mockDaoService.EXPECT().HoldAuthCodeRequest(testRequestID, []models.HoldReason{models.HoldReasonUserMultipleCompanies}).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Success)
+			So(authCodeReq.Data.Status, ShouldEqual, models.StatusHeld)
+		})
+
+		Convey("exempt user is not held for requesting several companies", func() {
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(gomock.Any(), gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{}, nil)
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).Return(nil)
+			policy := NewSubmissionPolicy(cfg)
+			policy.ExemptUsers = []string{"email@companieshouse.gov.uk"}
+			svc := AuthCodeRequestService{DAO: mockDaoService, Policy: policy}
+
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Success)
+		})
+
+		Convey("error counting companies requested by user", func() {
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(0, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("request no longer submitting", func() {
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().CountUserCompanies(gomock.Any(), gomock.Any()).Return(10, nil)
+			mockDaoService.EXPECT().HoldAuthCodeRequest(testRequestID, gomock.Any()).Return(false, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.SendAuthCodeRequest(&authCodeReq, companyNumber, "email@companieshouse.gov.uk", testRequestID, true, nil)
+			So(responseType, ShouldEqual, Conflict)
+		})
+	})
+}
+
+func TestUnitListHeldAuthCodeRequests(t *testing.T) {
+	Convey("list held auth code requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		filter := models.AuthCodeRequestFilter{Status: models.StatusHeld}
+
+		Convey("error listing requests", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ListAuthCodeRequests(filter, 0, 15).Return(nil, int64(0), fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.ListHeldAuthCodeRequests(0, 15)
+			So(response, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("list requests - success", func() {
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{ID: authCodeRequestID, Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld, HoldReasons: []models.HoldReason{models.HoldReasonUserMultipleCompanies}}},
+			}
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ListAuthCodeRequests(filter, 0, 15).Return(authCodeRequests, int64(1), nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.ListHeldAuthCodeRequests(0, 15)
+			So(responseType, ShouldEqual, Success)
+			So(response.TotalResults, ShouldEqual, 1)
+			So(response.Items[0].ID, ShouldEqual, authCodeRequestID)
+			So(response.Items[0].HoldReasons, ShouldResemble, []string{"user-multiple-companies"})
+		})
+	})
+}
+
+func TestUnitApproveAuthCodeRequest(t *testing.T) {
+	Convey("approve auth code request", t, func() {
+		cfg, _ := config.Get()
+
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		reviewer := &Requester{UserID: testReviewerID, Elevated: true}
+		reasons := []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed}
+		authCodeReq := models.AuthCodeRequestResourceDao{
+			ID: authCodeRequestID,
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: companyNumber,
+				OfficerID:     "987",
+				Status:        models.StatusHeld,
+				HoldReasons:   reasons,
+				CreatedBy:     models.CreatedByDao{ID: testUserID, Email: "email@companieshouse.gov.uk"},
+			},
+		}
+
+		Convey("error recording review", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, gomock.Any()).Return(false, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ApproveAuthCodeRequest(&authCodeReq, reviewer, "", true)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("request reviewed since it was read", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, gomock.Any()).Return(false, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ApproveAuthCodeRequest(&authCodeReq, reviewer, "", true)
+			So(responseType, ShouldEqual, Conflict)
+		})
+
+		Convey("submission limit reached returns request to review", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, gomock.Any()).Return(true, nil)
+			mockDaoService.EXPECT().CountCorporateBodySubmissions(companyNumber, gomock.Any(), gomock.Any()).Return(&models.SubmissionCount{Count: 1}, nil)
+			mockDaoService.EXPECT().HoldAuthCodeRequest(authCodeRequestID, reasons).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			limitFailure, responseType := svc.ApproveAuthCodeRequest(&authCodeReq, reviewer, "", true)
+			So(responseType, ShouldEqual, Forbidden)
+			So(limitFailure.Code, ShouldEqual, models.ReasonCompanyRecentlyRequested)
+		})
+
+		Convey("error submitting request returns it to review", func() {
+			responder := httpmock.NewStringResponder(http.StatusInternalServerError, "")
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, gomock.Any()).Return(true, nil)
+			mockDaoService.EXPECT().HoldAuthCodeRequest(authCodeRequestID, reasons).Return(true, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ApproveAuthCodeRequest(&authCodeReq, reviewer, "", true)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("approve request - success", func() {
+			appointedOn := time.Now().AddDate(0, 0, -2).Format("2006-01-02")
+			responder := httpmock.NewStringResponder(http.StatusOK, `{"surname":"bloggs","appointed_on":"`+appointedOn+`"}`)
+			httpmock.RegisterResponder(http.MethodGet, "/emergency-auth-code/company/87654321/eligible-officers/987", responder)
+
+			var review models.ReviewDao
+			var outboxItems []models.OutboxItemDao
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			expectSubmissionLimitsChecked(mockDaoService)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusSubmitting, gomock.Any()).DoAndReturn(
+				func(_ string, _ models.RequestStatus, r models.ReviewDao) (bool, error) {
+					review = r
+					return true, nil
+				})
+			mockDaoService.EXPECT().UpdateAuthCodeRequestStatus(gomock.Any(), models.StatusSubmitting, gomock.Any()).DoAndReturn(
+				func(_ *models.AuthCodeRequestResourceDao, _ models.RequestStatus, items []models.OutboxItemDao) error {
+					outboxItems = items
+					return nil
+				})
+			svc := AuthCodeRequestService{DAO: mockDaoService, Config: cfg}
+
+			_, responseType := svc.ApproveAuthCodeRequest(&authCodeReq, reviewer, "officer verified", true)
+			So(responseType, ShouldEqual, Success)
+
+			// the recently appointed officer is not held again once approved
+			So(review.Decision, ShouldEqual, models.ReviewApproved)
+			So(review.Reason, ShouldEqual, "officer verified")
+			So(review.ReviewedBy.ID, ShouldEqual, testReviewerID)
+			So(review.ReviewedAt, ShouldNotBeNil)
+			So(outboxItems, ShouldHaveLength, 2)
+			So(outboxItems[0].AuthCodeItem.Email, ShouldEqual, "email@companieshouse.gov.uk")
+		})
+	})
+}
+
+func TestUnitRejectAuthCodeRequest(t *testing.T) {
+	Convey("reject auth code request", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		reviewer := &Requester{UserID: testReviewerID, Email: "reviewer@companieshouse.gov.uk", Elevated: true}
+		held := &models.AuthCodeRequestResourceDao{ID: authCodeRequestID, Data: models.AuthCodeRequestDataDao{Status: models.StatusHeld}}
+
+		Convey("request not found", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(nil, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.RejectAuthCodeRequest(authCodeRequestID, reviewer, "fraud"), ShouldEqual, NotFound)
+		})
+
+		Convey("request not held", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(
+				&models.AuthCodeRequestResourceDao{Data: models.AuthCodeRequestDataDao{Status: models.StatusSubmitted}}, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.RejectAuthCodeRequest(authCodeRequestID, reviewer, "fraud"), ShouldEqual, Conflict)
+		})
+
+		Convey("error recording review", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(held, nil)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusRejected, gomock.Any()).Return(false, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.RejectAuthCodeRequest(authCodeRequestID, reviewer, "fraud"), ShouldEqual, Error)
+		})
+
+		Convey("reject request - success", func() {
+			var review models.ReviewDao
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().GetAuthCodeRequest(authCodeRequestID).Return(held, nil)
+			mockDaoService.EXPECT().ReviewAuthCodeRequest(authCodeRequestID, models.StatusRejected, gomock.Any()).DoAndReturn(
+				func(_ string, _ models.RequestStatus, r models.ReviewDao) (bool, error) {
+					review = r
+					return true, nil
+				})
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			So(svc.RejectAuthCodeRequest(authCodeRequestID, reviewer, "fraud"), ShouldEqual, Success)
+			So(review.Decision, ShouldEqual, models.ReviewRejected)
+			So(review.Reason, ShouldEqual, "fraud")
+			So(review.ReviewedBy, ShouldResemble, models.ActorDao{ID: testReviewerID, Email: "reviewer@companieshouse.gov.uk"})
+		})
+	})
+}
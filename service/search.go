@@ -0,0 +1,31 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/companieshouse/emergency-auth-code-api/transformers"
+)
+
+// SearchAuthCodeRequests returns a page of the authcode requests, created by any user, which match the
+// supplied search, newest first. A cursor for the next page is returned if there are further matches.
+func (s *AuthCodeRequestService) SearchAuthCodeRequests(search models.AuthCodeRequestSearch, itemsPerPage int) (*models.AdminAuthCodeRequestSearchResponse, ResponseType) {
+	// fetch one more than a page to find whether there is a next page
+	search.Limit = itemsPerPage + 1
+	search.CompanyNumber = NormaliseCompanyNumber(search.CompanyNumber)
+
+	authCodeRequests, err := s.DAO.SearchAuthCodeRequests(search)
+	if err != nil {
+		log.Error(fmt.Errorf("error searching authcode requests: %v", err))
+		return nil, Error
+	}
+
+	var nextCursor string
+	if len(authCodeRequests) > itemsPerPage {
+		authCodeRequests = authCodeRequests[:itemsPerPage]
+		nextCursor = models.NewAuthCodeRequestCursor(&authCodeRequests[itemsPerPage-1]).String()
+	}
+
+	return transformers.AuthCodeRequestResourceDaoListToAdminSearchResponse(authCodeRequests, itemsPerPage, nextCursor), Success
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/mocks"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	"github.com/golang/mock/gomock"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitSearchAuthCodeRequests(t *testing.T) {
+	Convey("search auth code requests", t, func() {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		createdAt := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+		search := models.AuthCodeRequestSearch{CompanyNumber: companyNumber}
+		expectedSearch := models.AuthCodeRequestSearch{CompanyNumber: companyNumber, Limit: 3}
+
+		Convey("error searching requests", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().SearchAuthCodeRequests(expectedSearch).Return(nil, fmt.Errorf("error"))
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.SearchAuthCodeRequests(search, 2)
+			So(response, ShouldBeNil)
+			So(responseType, ShouldEqual, Error)
+		})
+
+		Convey("last page", func() {
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().SearchAuthCodeRequests(expectedSearch).Return([]models.AuthCodeRequestResourceDao{{ID: "1"}}, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.SearchAuthCodeRequests(search, 2)
+			So(responseType, ShouldEqual, Success)
+			So(response.Items, ShouldHaveLength, 1)
+			So(response.NextCursor, ShouldBeEmpty)
+		})
+
+		Convey("further pages", func() {
+			authCodeRequests := []models.AuthCodeRequestResourceDao{
+				{ID: "3", Data: models.AuthCodeRequestDataDao{CreatedAt: &createdAt}},
+				{ID: "2", Data: models.AuthCodeRequestDataDao{CreatedAt: &createdAt}},
+				{ID: "1", Data: models.AuthCodeRequestDataDao{CreatedAt: &createdAt}},
+			}
+
+			mockDaoService := mocks.NewMockAuthcodeRequestDAOService(mockCtrl)
+			mockDaoService.EXPECT().SearchAuthCodeRequests(expectedSearch).Return(authCodeRequests, nil)
+			svc := AuthCodeRequestService{DAO: mockDaoService}
+
+			response, responseType := svc.SearchAuthCodeRequests(search, 2)
+			So(responseType, ShouldEqual, Success)
+			So(response.ItemsPerPage, ShouldEqual, 2)
+			So(response.Items, ShouldHaveLength, 2)
+			So(response.Items[1].ID, ShouldEqual, "2")
+
+			// the next page starts after the last request returned
+			cursor, err := models.ParseAuthCodeRequestCursor(response.NextCursor)
+			So(err, ShouldBeNil)
+			So(cursor.ID, ShouldEqual, "2")
+			So(cursor.CreatedAt, ShouldEqual, createdAt)
+		})
+	})
+}
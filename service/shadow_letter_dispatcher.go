@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/companieshouse/chs.go/log"
+	"github.com/companieshouse/emergency-auth-code-api/authcodeapi"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// ShadowLetterDispatcher sends letters to its primary backend. Items may also be shadowed, which asks the
+// shadow backend to validate them in the background using its dry run. Differences between whether the
+// backends accepted each item, or in the errors they returned, are logged and counted, so that confidence
+// can be gained in the shadow backend before moving letters to it.
+type ShadowLetterDispatcher struct {
+	Primary LetterDispatcher
+	Shadow  LetterValidator
+
+	compared   atomic.Int64
+	mismatched atomic.Int64
+	pending    sync.WaitGroup
+}
+
+// ShadowDispatchCounts are the number of items validated by the shadow backend, and of those the number
+// for which the outcome differed from the primary backend
+type ShadowDispatchCounts struct {
+	Compared   int64
+	Mismatched int64
+}
+
+// Backend identifies the primary backend, which sends the letters
+func (d *ShadowLetterDispatcher) Backend() models.LetterBackend {
+	return d.Primary.Backend()
+}
+
+// DispatchLetter sends the item to the primary backend only
+func (d *ShadowLetterDispatcher) DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	return d.Primary.DispatchLetter(item, authCodeRequestID)
+}
+
+// ShadowLetter validates the item using the shadow backend in the background, and compares the outcome
+// with primaryErr, the error returned when the item was sent to the primary backend
+func (d *ShadowLetterDispatcher) ShadowLetter(item *models.AuthCodeItem, authCodeRequestID string, primaryErr error) {
+	d.pending.Add(1)
+	go func() {
+		defer d.pending.Done()
+		_, shadowErr := d.Shadow.ValidateLetter(item, authCodeRequestID)
+		d.compare(authCodeRequestID, primaryErr, shadowErr)
+	}()
+}
+
+// Wait blocks until every item shadowed so far has been compared
+func (d *ShadowLetterDispatcher) Wait() {
+	d.pending.Wait()
+}
+
+// Counts returns the number of items compared, and mismatched, since the dispatcher was created
+func (d *ShadowLetterDispatcher) Counts() ShadowDispatchCounts {
+	return ShadowDispatchCounts{
+		Compared:   d.compared.Load(),
+		Mismatched: d.mismatched.Load(),
+	}
+}
+
+// compare counts the outcome of an item from both backends, logging it if they differ
+func (d *ShadowLetterDispatcher) compare(authCodeRequestID string, primaryErr, shadowErr error) {
+	compared := d.compared.Add(1)
+
+	primaryOutcome := letterDispatchOutcome(primaryErr)
+	shadowOutcome := letterDispatchOutcome(shadowErr)
+
+	logContext := log.Data{
+		"auth_code_request_id": authCodeRequestID,
+		"primary_backend":      d.Primary.Backend(),
+		"shadow_backend":       d.Shadow.Backend(),
+		"primary_outcome":      primaryOutcome,
+		"shadow_outcome":       shadowOutcome,
+		"compared":             compared,
+	}
+
+	if primaryOutcome == shadowOutcome {
+		logContext["mismatched"] = d.mismatched.Load()
+		log.Info("shadow letter backend outcome matched primary", logContext)
+		return
+	}
+
+	logContext["mismatched"] = d.mismatched.Add(1)
+	if primaryErr != nil {
+		logContext["primary_error"] = primaryErr.Error()
+	}
+	if shadowErr != nil {
+		logContext["shadow_error"] = shadowErr.Error()
+	}
+	log.Error(fmt.Errorf("shadow letter backend outcome differed from primary"), logContext)
+}
+
+// letterDispatchOutcome summarises the result of sending an item to a letter backend for comparison: it was
+// either accepted, rejected with a status code, or could not be sent
+func letterDispatchOutcome(err error) string {
+	if err == nil {
+		return "accepted"
+	}
+
+	var statusErr *authcodeapi.UnexpectedStatusError
+	if errors.As(err, &statusErr) {
+		return fmt.Sprintf("rejected-%d", statusErr.StatusCode)
+	}
+
+	return "error"
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/companieshouse/emergency-auth-code-api/authcodeapi"
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// stubLetterDispatcher records the items sent to it, returning the configured errors
+type stubLetterDispatcher struct {
+	backend     models.LetterBackend
+	dispatchErr error
+	validateErr error
+	dispatched  int
+	validated   int
+}
+
+func (d *stubLetterDispatcher) Backend() models.LetterBackend {
+	return d.backend
+}
+
+func (d *stubLetterDispatcher) DispatchLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	d.dispatched++
+	if d.dispatchErr != nil {
+		return nil, d.dispatchErr
+	}
+	return &models.AuthCodeItemResponse{StatusCode: http.StatusCreated, LetterReference: "LTR123"}, nil
+}
+
+func (d *stubLetterDispatcher) ValidateLetter(item *models.AuthCodeItem, authCodeRequestID string) (*models.AuthCodeItemResponse, error) {
+	d.validated++
+	if d.validateErr != nil {
+		return nil, d.validateErr
+	}
+	return &models.AuthCodeItemResponse{StatusCode: http.StatusOK}, nil
+}
+
+func TestUnitShadowLetterDispatcher(t *testing.T) {
+	Convey("shadow letter dispatcher", t, func() {
+		item := &models.AuthCodeItem{CompanyNumber: companyNumber}
+		primary := &stubLetterDispatcher{backend: models.LetterBackendQueueAPI}
+		shadow := &stubLetterDispatcher{backend: models.LetterBackendFile}
+		dispatcher := &ShadowLetterDispatcher{Primary: primary, Shadow: shadow}
+
+		So(dispatcher.Backend(), ShouldEqual, models.LetterBackendQueueAPI)
+
+		Convey("dispatch sends to primary only", func() {
+			resp, err := dispatcher.DispatchLetter(item, authCodeRequestID)
+			So(err, ShouldBeNil)
+			So(resp.LetterReference, ShouldEqual, "LTR123")
+			So(primary.dispatched, ShouldEqual, 1)
+			So(shadow.dispatched, ShouldEqual, 0)
+			So(shadow.validated, ShouldEqual, 0)
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{})
+		})
+
+		Convey("both backends accept", func() {
+			dispatcher.ShadowLetter(item, authCodeRequestID, nil)
+			dispatcher.Wait()
+			So(shadow.dispatched, ShouldEqual, 0)
+			So(shadow.validated, ShouldEqual, 1)
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 0})
+		})
+
+		Convey("shadow backend rejects item accepted by primary", func() {
+			shadow.validateErr = &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusBadRequest}
+
+			dispatcher.ShadowLetter(item, authCodeRequestID, nil)
+			dispatcher.Wait()
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 1})
+		})
+
+		Convey("primary error when shadow backend accepts", func() {
+			dispatcher.ShadowLetter(item, authCodeRequestID, &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusInternalServerError})
+			dispatcher.Wait()
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 1})
+		})
+
+		Convey("backends reject with different status codes", func() {
+			shadow.validateErr = &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusUnprocessableEntity}
+
+			dispatcher.ShadowLetter(item, authCodeRequestID, &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusBadRequest})
+			dispatcher.Wait()
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 1})
+		})
+
+		Convey("backends reject with same status code", func() {
+			shadow.validateErr = &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusBadRequest}
+
+			dispatcher.ShadowLetter(item, authCodeRequestID, &authcodeapi.UnexpectedStatusError{StatusCode: http.StatusBadRequest})
+			dispatcher.Wait()
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 1, Mismatched: 0})
+		})
+
+		Convey("counts accumulate", func() {
+			dispatcher.ShadowLetter(item, authCodeRequestID, nil)
+			dispatcher.Wait()
+			shadow.validateErr = fmt.Errorf("connection refused")
+			dispatcher.ShadowLetter(item, authCodeRequestID, nil)
+			dispatcher.Wait()
+			So(dispatcher.Counts(), ShouldResemble, ShadowDispatchCounts{Compared: 2, Mismatched: 1})
+		})
+	})
+}
+
+func TestUnitNewShadowLetterDispatcher(t *testing.T) {
+	Convey("new letter dispatcher with shadow backend", t, func() {
+		cfg := &config.Config{LetterBackend: "queue-api", LetterFilePath: "letters.jsonl"}
+
+		Convey("shadow backend with dry run wraps primary", func() {
+			cfg.LetterShadowBackend = "file"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			shadowDispatcher, ok := dispatcher.(*ShadowLetterDispatcher)
+			So(ok, ShouldBeTrue)
+			So(shadowDispatcher.Primary.Backend(), ShouldEqual, models.LetterBackendQueueAPI)
+			So(shadowDispatcher.Shadow.Backend(), ShouldEqual, models.LetterBackendFile)
+		})
+
+		Convey("shadow backend without dry run refused", func() {
+			cfg.LetterShadowBackend = "authcode-api"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(dispatcher, ShouldBeNil)
+			So(err.Error(), ShouldEqual, "letter backend [authcode-api] has no dry run so cannot be used as a shadow letter backend")
+		})
+
+		Convey("shadow backend same as primary ignored", func() {
+			cfg.LetterShadowBackend = "queue-api"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(err, ShouldBeNil)
+			_, ok := dispatcher.(*ShadowLetterDispatcher)
+			So(ok, ShouldBeFalse)
+		})
+
+		Convey("invalid shadow backend", func() {
+			cfg.LetterShadowBackend = "pigeon"
+			dispatcher, err := NewLetterDispatcher(cfg)
+			So(dispatcher, ShouldBeNil)
+			So(err.Error(), ShouldEqual, "error configuring shadow letter backend: unknown letter backend [pigeon]")
+		})
+	})
+}
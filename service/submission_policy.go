@@ -0,0 +1,165 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+const (
+	defaultCompanySubmissionWindow = 72 * time.Hour
+	defaultCompanySubmissionLimit  = 1
+	defaultUserSubmissionWindow    = 24 * time.Hour
+	defaultUserSubmissionLimit     = 3
+	defaultOfficerSubmissionWindow = 7 * 24 * time.Hour
+	defaultOfficerSubmissionLimit  = 3
+	defaultAddressSubmissionWindow = 7 * 24 * time.Hour
+	defaultAddressSubmissionLimit  = 3
+	defaultOfficerAppointedWithin  = 14 * 24 * time.Hour
+	defaultUserCompanyWindow       = 7 * 24 * time.Hour
+	defaultUserCompanyLimit        = 3
+)
+
+// SubmissionLimit restricts the number of auth code requests which may be submitted within a period
+type SubmissionLimit struct {
+	Window         time.Duration
+	MaxSubmissions int
+}
+
+// RetryAfter decides whether another request may be submitted given the requests already submitted within
+// the window. If it may not, the time at which the oldest of the submissions counting towards the limit
+// leaves the window is returned, otherwise nil.
+func (l SubmissionLimit) RetryAfter(count *models.SubmissionCount) *time.Time {
+	if count == nil || count.Count < int64(l.MaxSubmissions) {
+		return nil
+	}
+
+	retryAfter := time.Now().Add(l.Window)
+	if len(count.SubmittedAt) >= l.MaxSubmissions && l.MaxSubmissions > 0 {
+		retryAfter = count.SubmittedAt[l.MaxSubmissions-1].Add(l.Window)
+	}
+
+	return &retryAfter
+}
+
+// SubmissionPolicy decides whether auth code requests may be submitted for a company, by a user, for an
+// officer or to an officer's usual residential address, given the requests already submitted for them.
+// It also decides which submissions are held for manual review: those for an officer appointed within
+// OfficerAppointedWithin, and those by a user who has created requests for more than UserCompanies
+// MaxSubmissions companies within its window.
+type SubmissionPolicy struct {
+	Company                SubmissionLimit
+	User                   SubmissionLimit
+	Officer                SubmissionLimit
+	Address                SubmissionLimit
+	ExemptCompanies        []string
+	ExemptUsers            []string
+	OfficerAppointedWithin time.Duration
+	UserCompanies          SubmissionLimit
+}
+
+// NewSubmissionPolicy returns the submission policy set in the supplied config, using the default limits
+// for any which are not set
+func NewSubmissionPolicy(cfg *config.Config) *SubmissionPolicy {
+	policy := &SubmissionPolicy{
+		Company: SubmissionLimit{Window: defaultCompanySubmissionWindow, MaxSubmissions: defaultCompanySubmissionLimit},
+		User:    SubmissionLimit{Window: defaultUserSubmissionWindow, MaxSubmissions: defaultUserSubmissionLimit},
+		Officer: SubmissionLimit{Window: defaultOfficerSubmissionWindow, MaxSubmissions: defaultOfficerSubmissionLimit},
+		Address: SubmissionLimit{Window: defaultAddressSubmissionWindow, MaxSubmissions: defaultAddressSubmissionLimit},
+
+		OfficerAppointedWithin: defaultOfficerAppointedWithin,
+		UserCompanies:          SubmissionLimit{Window: defaultUserCompanyWindow, MaxSubmissions: defaultUserCompanyLimit},
+	}
+
+	if cfg == nil {
+		return policy
+	}
+
+	if cfg.CompanySubmissionWindowHours > 0 {
+		policy.Company.Window = time.Duration(cfg.CompanySubmissionWindowHours) * time.Hour
+	}
+	if cfg.CompanySubmissionLimit > 0 {
+		policy.Company.MaxSubmissions = cfg.CompanySubmissionLimit
+	}
+	if cfg.UserSubmissionWindowHours > 0 {
+		policy.User.Window = time.Duration(cfg.UserSubmissionWindowHours) * time.Hour
+	}
+	if cfg.UserSubmissionLimit > 0 {
+		policy.User.MaxSubmissions = cfg.UserSubmissionLimit
+	}
+	if cfg.OfficerSubmissionWindowHours > 0 {
+		policy.Officer.Window = time.Duration(cfg.OfficerSubmissionWindowHours) * time.Hour
+	}
+	if cfg.OfficerSubmissionLimit > 0 {
+		policy.Officer.MaxSubmissions = cfg.OfficerSubmissionLimit
+	}
+	if cfg.AddressSubmissionWindowHours > 0 {
+		policy.Address.Window = time.Duration(cfg.AddressSubmissionWindowHours) * time.Hour
+	}
+	if cfg.AddressSubmissionLimit > 0 {
+		policy.Address.MaxSubmissions = cfg.AddressSubmissionLimit
+	}
+	if cfg.HoldOfficerAppointedDays > 0 {
+		policy.OfficerAppointedWithin = time.Duration(cfg.HoldOfficerAppointedDays) * 24 * time.Hour
+	}
+	if cfg.HoldUserCompanyWindowHours > 0 {
+		policy.UserCompanies.Window = time.Duration(cfg.HoldUserCompanyWindowHours) * time.Hour
+	}
+	if cfg.HoldUserCompanyLimit > 0 {
+		policy.UserCompanies.MaxSubmissions = cfg.HoldUserCompanyLimit
+	}
+	policy.ExemptCompanies = cfg.SubmissionLimitExemptCompanies
+	policy.ExemptUsers = cfg.SubmissionLimitExemptUsers
+
+	return policy
+}
+
+// LongestWindow returns the longest of the windows within which submissions are counted
+func (p *SubmissionPolicy) LongestWindow() time.Duration {
+	longest := time.Duration(0)
+	for _, limit := range []SubmissionLimit{p.Company, p.User, p.Officer, p.Address, p.UserCompanies} {
+		if limit.Window > longest {
+			longest = limit.Window
+		}
+	}
+	return longest
+}
+
+// IsCompanyExempt returns whether the company is exempt from the submission limits
+func (p *SubmissionPolicy) IsCompanyExempt(companyNumber string) bool {
+	return containsFold(p.ExemptCompanies, companyNumber)
+}
+
+// IsUserExempt returns whether the user with the supplied email is exempt from the submission limits
+func (p *SubmissionPolicy) IsUserExempt(email string) bool {
+	return containsFold(p.ExemptUsers, email)
+}
+
+// IsOfficerRecentlyAppointed returns whether an officer appointed on the supplied date was appointed
+// recently enough for submissions for them to be held for review. Appointment dates which cannot be
+// parsed are not treated as recent.
+func (p *SubmissionPolicy) IsOfficerRecentlyAppointed(appointedOn string) bool {
+	for _, layout := range appointedOnLayouts {
+		appointed, err := time.Parse(layout, appointedOn)
+		if err == nil {
+			return time.Since(appointed) < p.OfficerAppointedWithin
+		}
+	}
+	return false
+}
+
+// appointedOnLayouts are the formats in which the Oracle Query API returns officer appointment dates
+var appointedOnLayouts = []string{"2006-01-02", "02-01-2006"}
+
+// containsFold returns whether the list contains the value, ignoring case and surrounding whitespace
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		item = strings.TrimSpace(item)
+		if item != "" && strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
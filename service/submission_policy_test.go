@@ -0,0 +1,98 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/config"
+	"github.com/companieshouse/emergency-auth-code-api/models"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitNewSubmissionPolicy(t *testing.T) {
+	Convey("default policy", t, func() {
+		policy := NewSubmissionPolicy(&config.Config{})
+		So(policy.Company, ShouldResemble, SubmissionLimit{Window: 72 * time.Hour, MaxSubmissions: 1})
+		So(policy.User, ShouldResemble, SubmissionLimit{Window: 24 * time.Hour, MaxSubmissions: 3})
+		So(policy.Officer, ShouldResemble, SubmissionLimit{Window: 168 * time.Hour, MaxSubmissions: 3})
+		So(policy.Address, ShouldResemble, SubmissionLimit{Window: 168 * time.Hour, MaxSubmissions: 3})
+		So(policy.OfficerAppointedWithin, ShouldEqual, 14*24*time.Hour)
+		So(policy.UserCompanies, ShouldResemble, SubmissionLimit{Window: 168 * time.Hour, MaxSubmissions: 3})
+		So(policy.LongestWindow(), ShouldEqual, 168*time.Hour)
+		So(NewSubmissionPolicy(nil), ShouldResemble, policy)
+	})
+
+	Convey("configured policy", t, func() {
+		policy := NewSubmissionPolicy(&config.Config{
+			CompanySubmissionWindowHours:   168,
+			CompanySubmissionLimit:         2,
+			UserSubmissionWindowHours:      48,
+			UserSubmissionLimit:            1,
+			OfficerSubmissionWindowHours:   720,
+			OfficerSubmissionLimit:         5,
+			AddressSubmissionWindowHours:   24,
+			AddressSubmissionLimit:         2,
+			SubmissionLimitExemptCompanies: []string{"SC123456"},
+			SubmissionLimitExemptUsers:     []string{"support@test.com"},
+			HoldOfficerAppointedDays:       30,
+			HoldUserCompanyWindowHours:     24,
+			HoldUserCompanyLimit:           10,
+		})
+		So(policy.Company, ShouldResemble, SubmissionLimit{Window: 168 * time.Hour, MaxSubmissions: 2})
+		So(policy.User, ShouldResemble, SubmissionLimit{Window: 48 * time.Hour, MaxSubmissions: 1})
+		So(policy.Officer, ShouldResemble, SubmissionLimit{Window: 720 * time.Hour, MaxSubmissions: 5})
+		So(policy.Address, ShouldResemble, SubmissionLimit{Window: 24 * time.Hour, MaxSubmissions: 2})
+		So(policy.IsCompanyExempt("sc123456"), ShouldBeTrue)
+		So(policy.IsCompanyExempt("87654321"), ShouldBeFalse)
+		So(policy.IsUserExempt("Support@test.com"), ShouldBeTrue)
+		So(policy.IsUserExempt(""), ShouldBeFalse)
+		So(policy.OfficerAppointedWithin, ShouldEqual, 30*24*time.Hour)
+		So(policy.UserCompanies, ShouldResemble, SubmissionLimit{Window: 24 * time.Hour, MaxSubmissions: 10})
+		So(policy.LongestWindow(), ShouldEqual, 720*time.Hour)
+	})
+}
+
+func TestUnitIsOfficerRecentlyAppointed(t *testing.T) {
+	policy := NewSubmissionPolicy(nil)
+
+	Convey("recently appointed", t, func() {
+		So(policy.IsOfficerRecentlyAppointed(time.Now().AddDate(0, 0, -3).Format("2006-01-02")), ShouldBeTrue)
+		So(policy.IsOfficerRecentlyAppointed(time.Now().AddDate(0, 0, -3).Format("02-01-2006")), ShouldBeTrue)
+	})
+
+	Convey("not recently appointed", t, func() {
+		So(policy.IsOfficerRecentlyAppointed("01-01-2001"), ShouldBeFalse)
+		So(policy.IsOfficerRecentlyAppointed(time.Now().AddDate(0, 0, -30).Format("2006-01-02")), ShouldBeFalse)
+	})
+
+	Convey("unknown appointment date", t, func() {
+		So(policy.IsOfficerRecentlyAppointed(""), ShouldBeFalse)
+		So(policy.IsOfficerRecentlyAppointed("unknown"), ShouldBeFalse)
+	})
+}
+
+func TestUnitSubmissionLimitRetryAfter(t *testing.T) {
+	limit := SubmissionLimit{Window: 24 * time.Hour, MaxSubmissions: 2}
+	newest := time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)
+	oldest := newest.Add(-6 * time.Hour)
+
+	Convey("under the limit", t, func() {
+		So(limit.RetryAfter(nil), ShouldBeNil)
+		So(limit.RetryAfter(&models.SubmissionCount{Count: 1, SubmittedAt: []time.Time{newest}}), ShouldBeNil)
+	})
+
+	Convey("limit reached", t, func() {
+		retryAfter := limit.RetryAfter(&models.SubmissionCount{Count: 2, SubmittedAt: []time.Time{newest, oldest}})
+		So(*retryAfter, ShouldEqual, oldest.Add(24*time.Hour))
+	})
+
+	Convey("limit exceeded", t, func() {
+		retryAfter := limit.RetryAfter(&models.SubmissionCount{Count: 5, SubmittedAt: []time.Time{newest, oldest}})
+		So(*retryAfter, ShouldEqual, oldest.Add(24*time.Hour))
+	})
+
+	Convey("submission times unknown", t, func() {
+		retryAfter := limit.RetryAfter(&models.SubmissionCount{Count: 2})
+		So(*retryAfter, ShouldHappenAfter, time.Now().Add(23*time.Hour))
+	})
+}
@@ -0,0 +1,46 @@
+package transformers
+
+import (
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// AuditEntryDaoListToResponse will transform the audit trail of an auth code request into the http
+// response entity seen by administrators
+func AuditEntryDaoListToResponse(authCodeRequestID string, daos []models.AuditEntryDao) *models.AuditTrailResponse {
+	resp := &models.AuditTrailResponse{
+		AuthCodeRequestID: authCodeRequestID,
+		Items:             make([]models.AuditEntry, 0, len(daos)),
+	}
+	for _, entry := range daos {
+		resp.Items = append(resp.Items, models.AuditEntry{
+			ID:     entry.ID,
+			Action: string(entry.Action),
+			Actor: models.Actor{
+				ID:    entry.Actor.ID,
+				Email: entry.Actor.Email,
+			},
+			ActorType: string(entry.ActorType),
+			Before:    auditStateDaoToResponse(entry.Before),
+			After:     auditStateDaoToResponse(entry.After),
+			At:        entry.At,
+			RequestID: entry.RequestID,
+		})
+	}
+	return resp
+}
+
+// auditStateDaoToResponse transforms the state of an auth code request recorded in its audit trail into
+// its response entity
+func auditStateDaoToResponse(state *models.AuditStateDao) *models.AuditState {
+	if state == nil {
+		return nil
+	}
+
+	return &models.AuditState{
+		Status:          string(state.Status),
+		OfficerID:       state.OfficerID,
+		OfficerUraID:    state.OfficerUraID,
+		OfficerForename: state.OfficerForename,
+		OfficerSurname:  state.OfficerSurname,
+	}
+}
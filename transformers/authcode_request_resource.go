@@ -34,7 +34,7 @@ func AuthCodeResourceRequestToDB(req *models.AuthCodeRequest) *models.AuthCodeRe
 			OfficerUraID:    req.OfficerUraID,
 			OfficerForename: req.OfficerForename,
 			OfficerSurname:  req.OfficerSurname,
-			Status:          "pending",
+			Status:          models.StatusPending,
 			CreatedAt:       &createdAt,
 			SubmittedAt:     nil,
 			Kind:            "emergency-auth-code-request",
@@ -65,7 +65,8 @@ func AuthCodeRequestResourceDaoToResponse(model *models.AuthCodeRequestResourceD
 		OfficerID:     model.Data.OfficerID,
 		OfficerUraID:  model.Data.OfficerUraID,
 		OfficerName:   strings.Join([]string{model.Data.OfficerForename, model.Data.OfficerSurname}, " "),
-		Status:        model.Data.Status,
+		Status:        string(model.Data.Status),
+		StatusHistory: statusHistoryDaoToResponse(model.Data.StatusHistory),
 		CreatedAt:     model.Data.CreatedAt,
 		SubmittedAt:   model.Data.SubmittedAt,
 		Etag:          model.Data.Etag,
@@ -75,3 +76,133 @@ func AuthCodeRequestResourceDaoToResponse(model *models.AuthCodeRequestResourceD
 		},
 	}
 }
+
+// AuthCodeRequestResourceDaoListToResponse will transform a page of auth code resource daos into an
+// http list response entity
+func AuthCodeRequestResourceDaoListToResponse(daos []models.AuthCodeRequestResourceDao, startIndex, itemsPerPage int, totalResults int64) *models.AuthCodeRequestListResponse {
+	resp := &models.AuthCodeRequestListResponse{
+		ItemsPerPage: itemsPerPage,
+		StartIndex:   startIndex,
+		TotalResults: int(totalResults),
+		Items:        make([]models.AuthCodeRequestResourceResponse, 0, len(daos)),
+	}
+	for i := range daos {
+		resp.Items = append(resp.Items, *AuthCodeRequestResourceDaoToResponse(&daos[i]))
+	}
+	return resp
+}
+
+// statusHistoryDaoToResponse transforms the recorded status transitions of an auth code request into
+// their response entities
+func statusHistoryDaoToResponse(statusHistory []models.StatusTransitionDao) []models.StatusTransition {
+	if len(statusHistory) == 0 {
+		return nil
+	}
+
+	transitions := make([]models.StatusTransition, len(statusHistory))
+	for i, transition := range statusHistory {
+		transitions[i] = models.StatusTransition{
+			From: string(transition.From),
+			To:   string(transition.To),
+			At:   transition.At,
+		}
+	}
+
+	return transitions
+}
+
+// AuthCodeRequestResourceDaoToAdminResponse will transform an auth code resource dao into the http
+// response entity seen by administrators
+func AuthCodeRequestResourceDaoToAdminResponse(model *models.AuthCodeRequestResourceDao) *models.AdminAuthCodeRequestResponse {
+	resp := &models.AdminAuthCodeRequestResponse{
+		ID:                              model.ID,
+		AuthCodeRequestResourceResponse: *AuthCodeRequestResourceDaoToResponse(model),
+	}
+
+	for _, reason := range model.Data.HoldReasons {
+		resp.HoldReasons = append(resp.HoldReasons, string(reason))
+	}
+
+	for _, review := range model.Data.Reviews {
+		resp.Reviews = append(resp.Reviews, models.Review{
+			Decision: string(review.Decision),
+			Reason:   review.Reason,
+			ReviewedBy: models.Actor{
+				ID:    review.ReviewedBy.ID,
+				Email: review.ReviewedBy.Email,
+			},
+			ReviewedAt: review.ReviewedAt,
+		})
+	}
+
+	for _, resend := range model.Data.Resends {
+		resp.Resends = append(resp.Resends, models.Resend{
+			ReasonCode:     string(resend.ReasonCode),
+			Operator:       resend.Operator,
+			Note:           resend.Note,
+			OverrideLimits: resend.OverrideLimits,
+			RequestedBy: models.Actor{
+				ID:    resend.RequestedBy.ID,
+				Email: resend.RequestedBy.Email,
+			},
+			ResentAt: resend.ResentAt,
+		})
+	}
+
+	for _, event := range model.Data.LetterEvents {
+		resp.LetterEvents = append(resp.LetterEvents, models.LetterEvent{
+			Type:       string(event.Type),
+			Reference:  event.Reference,
+			Detail:     event.Detail,
+			OccurredAt: event.OccurredAt,
+			ReceivedAt: event.ReceivedAt,
+			ReportedBy: models.Actor{
+				ID:    event.ReportedBy.ID,
+				Email: event.ReportedBy.Email,
+			},
+		})
+	}
+
+	for _, dispatch := range model.Data.LetterDispatches {
+		resp.LetterDispatches = append(resp.LetterDispatches, models.LetterDispatch{
+			Backend:            string(dispatch.Backend),
+			StatusCode:         dispatch.StatusCode,
+			LetterReference:    dispatch.LetterReference,
+			QueueItemID:        dispatch.QueueItemID,
+			AcceptedAt:         dispatch.AcceptedAt,
+			ExpectedDispatchAt: dispatch.ExpectedDispatchAt,
+			DispatchedAt:       dispatch.DispatchedAt,
+		})
+	}
+
+	return resp
+}
+
+// AuthCodeRequestResourceDaoListToAdminResponse will transform a page of auth code resource daos into the
+// http list response entity seen by administrators
+func AuthCodeRequestResourceDaoListToAdminResponse(daos []models.AuthCodeRequestResourceDao, startIndex, itemsPerPage int, totalResults int64) *models.AdminAuthCodeRequestListResponse {
+	resp := &models.AdminAuthCodeRequestListResponse{
+		ItemsPerPage: itemsPerPage,
+		StartIndex:   startIndex,
+		TotalResults: int(totalResults),
+		Items:        make([]models.AdminAuthCodeRequestResponse, 0, len(daos)),
+	}
+	for i := range daos {
+		resp.Items = append(resp.Items, *AuthCodeRequestResourceDaoToAdminResponse(&daos[i]))
+	}
+	return resp
+}
+
+// AuthCodeRequestResourceDaoListToAdminSearchResponse will transform a page of auth code resource daos
+// matching an administrator's search into an http search response entity
+func AuthCodeRequestResourceDaoListToAdminSearchResponse(daos []models.AuthCodeRequestResourceDao, itemsPerPage int, nextCursor string) *models.AdminAuthCodeRequestSearchResponse {
+	resp := &models.AdminAuthCodeRequestSearchResponse{
+		ItemsPerPage: itemsPerPage,
+		NextCursor:   nextCursor,
+		Items:        make([]models.AdminAuthCodeRequestResponse, 0, len(daos)),
+	}
+	for i := range daos {
+		resp.Items = append(resp.Items, *AuthCodeRequestResourceDaoToAdminResponse(&daos[i]))
+	}
+	return resp
+}
@@ -2,6 +2,7 @@ package transformers
 
 import (
 	"testing"
+	"time"
 
 	"github.com/companieshouse/emergency-auth-code-api/models"
 
@@ -21,17 +22,24 @@ func TestUnitAuthCodeResourceRequestToDB(t *testing.T) {
 		So(dao.Data.CompanyNumber, ShouldEqual, "12345678")
 		So(dao.ID, ShouldHaveLength, 15)
 		So(dao.Data.Etag, ShouldNotBeNil)
+		So(dao.Data.Status, ShouldEqual, models.StatusPending)
 	})
 }
 
 func TestUnitAuthCodeRequestResourceDaoToResponse(t *testing.T) {
 
 	Convey("Auth code details from DB are transformed back to REST call", t, func() {
+		submittedAt := time.Now()
 		req := &models.AuthCodeRequestResourceDao{
 			Data: models.AuthCodeRequestDataDao{
 				CompanyNumber: "12345678",
 				CompanyName:   "test",
 				OfficerID:     "87654321",
+				Status:        models.StatusSubmitted,
+				StatusHistory: []models.StatusTransitionDao{
+					{From: models.StatusPending, To: models.StatusSubmitting, At: &submittedAt},
+					{From: models.StatusSubmitting, To: models.StatusSubmitted, At: &submittedAt},
+				},
 			},
 		}
 
@@ -40,5 +48,103 @@ func TestUnitAuthCodeRequestResourceDaoToResponse(t *testing.T) {
 		So(response.CompanyNumber, ShouldEqual, "12345678")
 		So(response.CompanyName, ShouldEqual, "test")
 		So(response.OfficerID, ShouldEqual, "87654321")
+		So(response.Status, ShouldEqual, "submitted")
+		So(response.StatusHistory, ShouldHaveLength, 2)
+		So(response.StatusHistory[1].From, ShouldEqual, "submitting")
+		So(response.StatusHistory[1].To, ShouldEqual, "submitted")
+		So(response.StatusHistory[1].At, ShouldEqual, &submittedAt)
+	})
+}
+
+func TestUnitAuthCodeRequestResourceDaoListToResponse(t *testing.T) {
+
+	Convey("Page of auth code requests from DB is transformed to a list response", t, func() {
+		daos := []models.AuthCodeRequestResourceDao{
+			{Data: models.AuthCodeRequestDataDao{CompanyNumber: "12345678"}},
+			{Data: models.AuthCodeRequestDataDao{CompanyNumber: "87654321"}},
+		}
+
+		response := AuthCodeRequestResourceDaoListToResponse(daos, 2, 2, 5)
+
+		So(response.StartIndex, ShouldEqual, 2)
+		So(response.ItemsPerPage, ShouldEqual, 2)
+		So(response.TotalResults, ShouldEqual, 5)
+		So(response.Items, ShouldHaveLength, 2)
+		So(response.Items[1].CompanyNumber, ShouldEqual, "87654321")
+	})
+
+	Convey("Empty page is transformed to an empty list", t, func() {
+		response := AuthCodeRequestResourceDaoListToResponse(nil, 0, 15, 0)
+
+		So(response.Items, ShouldNotBeNil)
+		So(response.Items, ShouldBeEmpty)
+	})
+}
+
+func TestUnitAuthCodeRequestResourceDaoToAdminResponse(t *testing.T) {
+
+	Convey("Held auth code request from DB is transformed to an admin response", t, func() {
+		reviewedAt := time.Now()
+		dao := &models.AuthCodeRequestResourceDao{
+			ID: "123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: "12345678",
+				Status:        models.StatusHeld,
+				HoldReasons:   []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed},
+				Reviews: []models.ReviewDao{
+					{
+						Decision:   models.ReviewApproved,
+						Reason:     "checked",
+						ReviewedBy: models.ActorDao{ID: "key", Email: "reviewer@test.com"},
+						ReviewedAt: &reviewedAt,
+					},
+				},
+			},
+		}
+
+		response := AuthCodeRequestResourceDaoToAdminResponse(dao)
+
+		So(response.ID, ShouldEqual, "123")
+		So(response.CompanyNumber, ShouldEqual, "12345678")
+		So(response.Status, ShouldEqual, "held")
+		So(response.HoldReasons, ShouldResemble, []string{"officer-recently-appointed"})
+		So(response.Reviews, ShouldHaveLength, 1)
+		So(response.Reviews[0].Decision, ShouldEqual, "approved")
+		So(response.Reviews[0].ReviewedBy.Email, ShouldEqual, "reviewer@test.com")
+		So(response.Reviews[0].ReviewedAt, ShouldEqual, &reviewedAt)
+	})
+
+	Convey("Dispatched auth code request from DB is transformed to an admin response", t, func() {
+		dispatchedAt := time.Now()
+		dao := &models.AuthCodeRequestResourceDao{
+			ID: "123",
+			Data: models.AuthCodeRequestDataDao{
+				Status: models.StatusDispatched,
+				LetterDispatches: []models.LetterDispatchDao{
+					{
+						Backend:         models.LetterBackendAuthCodeAPI,
+						OutboxItemID:    "outbox123",
+						StatusCode:      201,
+						LetterReference: "LTR123",
+						DispatchedAt:    &dispatchedAt,
+					},
+				},
+			},
+		}
+
+		response := AuthCodeRequestResourceDaoToAdminResponse(dao)
+
+		So(response.LetterDispatches, ShouldResemble, []models.LetterDispatch{
+			{Backend: "authcode-api", StatusCode: 201, LetterReference: "LTR123", DispatchedAt: &dispatchedAt},
+		})
+	})
+
+	Convey("Page of auth code requests from DB is transformed to an admin list response", t, func() {
+		response := AuthCodeRequestResourceDaoListToAdminResponse([]models.AuthCodeRequestResourceDao{{ID: "123"}}, 0, 15, 1)
+
+		So(response.TotalResults, ShouldEqual, 1)
+		So(response.Items, ShouldHaveLength, 1)
+		So(response.Items[0].ID, ShouldEqual, "123")
+		So(response.Items[0].HoldReasons, ShouldBeNil)
 	})
 }
@@ -0,0 +1,58 @@
+package transformers
+
+import (
+	"github.com/companieshouse/emergency-auth-code-api/models"
+)
+
+// AuthCodeRequestResourceDaoToDataSubjectResponse will transform an auth code resource dao and its audit
+// trail into the entity exported to the data subject who created it, leaving out the identities of staff
+// and the reasons it was held for review
+func AuthCodeRequestResourceDaoToDataSubjectResponse(model *models.AuthCodeRequestResourceDao, auditEntries []models.AuditEntryDao) *models.DataSubjectAuthCodeRequest {
+	resp := &models.DataSubjectAuthCodeRequest{
+		ID:                              model.ID,
+		AuthCodeRequestResourceResponse: *AuthCodeRequestResourceDaoToResponse(model),
+		AuditTrail:                      make([]models.DataSubjectAuditEntry, 0, len(auditEntries)),
+	}
+
+	for _, review := range model.Data.Reviews {
+		resp.Reviews = append(resp.Reviews, models.DataSubjectReview{
+			Decision:   string(review.Decision),
+			ReviewedAt: review.ReviewedAt,
+		})
+	}
+
+	for _, resend := range model.Data.Resends {
+		resp.Resends = append(resp.Resends, models.DataSubjectResend{
+			ReasonCode: string(resend.ReasonCode),
+			ResentAt:   resend.ResentAt,
+		})
+	}
+
+	for _, event := range model.Data.LetterEvents {
+		resp.LetterEvents = append(resp.LetterEvents, models.DataSubjectLetterEvent{
+			Type:       string(event.Type),
+			Reference:  event.Reference,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	for _, entry := range auditEntries {
+		exported := models.DataSubjectAuditEntry{
+			ID:        entry.ID,
+			Action:    string(entry.Action),
+			ActorType: string(entry.ActorType),
+			Before:    auditStateDaoToResponse(entry.Before),
+			After:     auditStateDaoToResponse(entry.After),
+			At:        entry.At,
+		}
+		if entry.ActorType == models.ActorTypeUser {
+			exported.Actor = &models.Actor{
+				ID:    entry.Actor.ID,
+				Email: entry.Actor.Email,
+			}
+		}
+		resp.AuditTrail = append(resp.AuditTrail, exported)
+	}
+
+	return resp
+}
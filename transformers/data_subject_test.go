@@ -0,0 +1,54 @@
+package transformers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/companieshouse/emergency-auth-code-api/models"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestUnitAuthCodeRequestResourceDaoToDataSubjectResponse(t *testing.T) {
+
+	Convey("Auth code request is exported to its data subject without staff identities or hold reasons", t, func() {
+		at := time.Now()
+		staff := models.ActorDao{ID: "staff-id", Email: "staff@test.com"}
+		dao := &models.AuthCodeRequestResourceDao{
+			ID: "123",
+			Data: models.AuthCodeRequestDataDao{
+				CompanyNumber: "12345678",
+				Status:        models.StatusReturned,
+				CreatedBy:     models.CreatedByDao{ID: "user-id", Email: "user@test.com"},
+				HoldReasons:   []models.HoldReason{models.HoldReasonOfficerRecentlyAppointed},
+				Reviews:       []models.ReviewDao{{Decision: models.ReviewApproved, Reason: "fraud checks passed", ReviewedBy: staff, ReviewedAt: &at}},
+				Resends:       []models.ResendDao{{ReasonCode: models.ResendReasonNotReceived, Operator: "operator-name", Note: "called in", RequestedBy: staff, ResentAt: &at}},
+				LetterEvents:  []models.LetterEventDao{{Type: models.LetterEventReturnedUndelivered, Reference: "LTR123", Detail: "gone away", OccurredAt: &at, ReceivedAt: &at, ReportedBy: staff}},
+			},
+		}
+		entries := []models.AuditEntryDao{
+			{ID: "1", Action: models.AuditActionCreated, Actor: models.ActorDao{ID: "user-id", Email: "user@test.com"}, ActorType: models.ActorTypeUser, RequestID: "trace"},
+			{ID: "2", Action: models.AuditActionApproved, Actor: staff, ActorType: models.ActorTypeAPIKey, RequestID: "trace"},
+		}
+
+		resp := AuthCodeRequestResourceDaoToDataSubjectResponse(dao, entries)
+
+		So(resp.ID, ShouldEqual, "123")
+		So(resp.UserEmail, ShouldEqual, "user@test.com")
+		So(resp.Reviews, ShouldResemble, []models.DataSubjectReview{{Decision: "approved", ReviewedAt: &at}})
+		So(resp.Resends, ShouldResemble, []models.DataSubjectResend{{ReasonCode: "not-received", ResentAt: &at}})
+		So(resp.LetterEvents, ShouldResemble, []models.DataSubjectLetterEvent{{Type: "returned-undelivered", Reference: "LTR123", OccurredAt: &at}})
+		So(resp.AuditTrail, ShouldHaveLength, 2)
+		So(resp.AuditTrail[0].Actor, ShouldResemble, &models.Actor{ID: "user-id", Email: "user@test.com"})
+		So(resp.AuditTrail[1].Actor, ShouldBeNil)
+		So(resp.AuditTrail[1].ActorType, ShouldEqual, "api-key")
+
+		body, err := json.Marshal(resp)
+		So(err, ShouldBeNil)
+		for _, absent := range []string{"staff-id", "staff@test.com", "operator-name", "called in", "fraud checks passed", "gone away",
+			"hold_reasons", "officer-recently-appointed", "reviewed_by", "requested_by", "reported_by", "request_id"} {
+			So(string(body), ShouldNotContainSubstring, absent)
+		}
+	})
+}
@@ -1,10 +1,13 @@
 package utils
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/companieshouse/chs.go/log"
 	"github.com/companieshouse/emergency-auth-code-api/models"
@@ -27,16 +30,50 @@ func WriteResponseMessage(w http.ResponseWriter, req *http.Request, status int,
 	WriteJSONWithStatus(w, req, models.NewMessageResponse(message), status)
 }
 
-// WriteJSONWithStatus writes the interface as a json string with the supplied status.
+// Etagger is implemented by response payloads which carry the etag of the resource they represent
+type Etagger interface {
+	ResourceEtag() string
+}
+
+// WriteJSONWithStatus writes the interface as a json string with the supplied status. Successful
+// responses are sent with an ETag, taken from the payload if it is an Etagger or otherwise from a hash
+// of the content, and a GET whose If-None-Match matches the ETag is answered with 304 Not Modified.
 func WriteJSONWithStatus(w http.ResponseWriter, r *http.Request, data interface{}, status int) {
 	w.Header().Set("Content-Type", "application/json")
+
+	var body bytes.Buffer
+	err := json.NewEncoder(&body).Encode(data)
+	if err != nil {
+		log.ErrorR(r, fmt.Errorf("error writing response: %v", err))
+		w.WriteHeader(status)
+		return
+	}
+
+	if status == http.StatusOK || status == http.StatusCreated {
+		etag := responseEtag(data, body.Bytes())
+		w.Header().Set("ETag", `"`+etag+`"`)
+
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && EtagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
 	w.WriteHeader(status)
-	err := json.NewEncoder(w).Encode(data)
+	_, err = w.Write(body.Bytes())
 	if err != nil {
 		log.ErrorR(r, fmt.Errorf("error writing response: %v", err))
 	}
 }
 
+// responseEtag returns the etag of a response payload, or a hash of its content if it has none
+func responseEtag(data interface{}, body []byte) string {
+	if etagger, ok := data.(Etagger); ok && etagger.ResourceEtag() != "" {
+		return etagger.ResourceEtag()
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(body))
+}
+
 // GetValueFromVars returns a specified value from the supplied request vars.
 func GetValueFromVars(vars map[string]string, key string) (string, error) {
 	val := vars[key]
@@ -45,3 +82,22 @@ func GetValueFromVars(vars map[string]string, key string) (string, error) {
 	}
 	return val, nil
 }
+
+// EtagMatches returns whether an If-Match or If-None-Match header value matches the supplied etag.
+// The header may hold a list of quoted, and optionally weak, etags, or * to match any etag.
+func EtagMatches(header, etag string) bool {
+	if etag == "" {
+		return false
+	}
+	for _, value := range strings.Split(header, ",") {
+		value = strings.TrimSpace(value)
+		if value == "*" {
+			return true
+		}
+		value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+		if value == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -32,6 +32,81 @@ func TestUnitWriteJSON(t *testing.T) {
 
 		So(w.Code, ShouldEqual, http.StatusOK)
 		So(w.Header().Get("Content-Type"), ShouldEqual, "application/json")
+		So(w.Body.String(), ShouldEqual, `{"message":"successful marshalling"}`+"\n")
+	})
+}
+
+func TestUnitWriteJSONEtag(t *testing.T) {
+	Convey("content hash is sent as the etag", t, func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		WriteJSON(w, r, models.NewMessageResponse("message"))
+
+		So(w.Header().Get("ETag"), ShouldHaveLength, 66)
+
+		Convey("and unchanged content is not modified", func() {
+			w2 := httptest.NewRecorder()
+			r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+			r2.Header.Set("If-None-Match", w.Header().Get("ETag"))
+
+			WriteJSON(w2, r2, models.NewMessageResponse("message"))
+
+			So(w2.Code, ShouldEqual, http.StatusNotModified)
+			So(w2.Body.String(), ShouldEqual, "")
+		})
+
+		Convey("and changed content is returned", func() {
+			w2 := httptest.NewRecorder()
+			r2 := httptest.NewRequest(http.MethodGet, "/", nil)
+			r2.Header.Set("If-None-Match", w.Header().Get("ETag"))
+
+			WriteJSON(w2, r2, models.NewMessageResponse("changed message"))
+
+			So(w2.Code, ShouldEqual, http.StatusOK)
+			So(w2.Header().Get("ETag"), ShouldNotEqual, w.Header().Get("ETag"))
+		})
+	})
+
+	Convey("resource etag is sent as the etag", t, func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		WriteJSON(w, r, &models.AuthCodeRequestResourceResponse{Etag: "abc"})
+
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("ETag"), ShouldEqual, `"abc"`)
+	})
+
+	Convey("matching etag is not modified", t, func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("If-None-Match", `"abc"`)
+
+		WriteJSON(w, r, &models.AuthCodeRequestResourceResponse{Etag: "abc"})
+
+		So(w.Code, ShouldEqual, http.StatusNotModified)
+		So(w.Body.String(), ShouldEqual, "")
+	})
+
+	Convey("matching etag is ignored on writes", t, func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPut, "/", nil)
+		r.Header.Set("If-None-Match", `"abc"`)
+
+		WriteJSON(w, r, &models.AuthCodeRequestResourceResponse{Etag: "abc"})
+
+		So(w.Code, ShouldEqual, http.StatusOK)
+		So(w.Header().Get("ETag"), ShouldEqual, `"abc"`)
+	})
+
+	Convey("error responses have no etag", t, func() {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+		WriteErrorMessage(w, r, http.StatusNotFound, "message")
+
+		So(w.Header().Get("ETag"), ShouldBeEmpty)
 	})
 }
 
@@ -78,3 +153,19 @@ func TestUnitGetCompanyNumber(t *testing.T) {
 		So(err.Error(), ShouldEqual, "company_number not found in vars")
 	})
 }
+
+func TestUnitEtagMatches(t *testing.T) {
+	Convey("Etag matches", t, func() {
+		So(EtagMatches("abc", "abc"), ShouldBeTrue)
+		So(EtagMatches(`"abc"`, "abc"), ShouldBeTrue)
+		So(EtagMatches(`W/"abc"`, "abc"), ShouldBeTrue)
+		So(EtagMatches(`"xyz", "abc"`, "abc"), ShouldBeTrue)
+		So(EtagMatches("*", "abc"), ShouldBeTrue)
+	})
+
+	Convey("Etag does not match", t, func() {
+		So(EtagMatches(`"xyz"`, "abc"), ShouldBeFalse)
+		So(EtagMatches("*", ""), ShouldBeFalse)
+		So(EtagMatches(`""`, ""), ShouldBeFalse)
+	})
+}